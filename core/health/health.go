@@ -0,0 +1,300 @@
+// Package health tracks the health of the miners, sharders and blobbers:
+// rolling latency, error rate and finalized round lag. The SDK records the
+// outcome of its requests in the Default registry and ranks the nodes with it
+// to pick the best ones.
+package health
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0chain/gosdk/core/telemetry"
+)
+
+var (
+	// DefaultDecay weight of a new sample in the rolling averages
+	DefaultDecay = 0.2
+	// DefaultErrorPenalty score added by an error rate of 100%
+	DefaultErrorPenalty = 10 * time.Second
+	// DefaultLagPenalty score added by every round of lag
+	DefaultLagPenalty = time.Second
+	// DefaultHalfLife period after which the error rate and the round lag
+	// of a node without new samples are halved, so that it is tried again
+	DefaultHalfLife = time.Minute
+)
+
+// Default is the registry shared by the SDK.
+var Default = NewRegistry()
+
+// Stats is the health of a node.
+type Stats struct {
+	Node string         `json:"node"`
+	Kind telemetry.Kind `json:"kind"`
+	// Latency is the rolling average latency of the requests.
+	Latency time.Duration `json:"latency"`
+	// ErrorRate is the rolling ratio of failed requests, between 0 and 1.
+	ErrorRate float64 `json:"error_rate"`
+	Requests  int64   `json:"requests"`
+	Failures  int64   `json:"failures"`
+	// Round is the latest finalized round reported by the node, and RoundLag
+	// how far it is behind the most advanced node of the same kind.
+	Round     int64     `json:"round"`
+	RoundLag  int64     `json:"round_lag"`
+	LastSeen  time.Time `json:"last_seen"`
+	LastError string    `json:"last_error,omitempty"`
+	// Score is the expected cost of a request to the node, lower is better.
+	Score time.Duration `json:"score"`
+}
+
+type nodeStats struct {
+	kind      telemetry.Kind
+	latency   float64
+	errorRate float64
+	requests  int64
+	failures  int64
+	round     int64
+	lastSeen  time.Time
+	lastError string
+}
+
+// Registry tracks the health of the nodes. It is safe for concurrent use.
+type Registry struct {
+	Decay        float64
+	ErrorPenalty time.Duration
+	LagPenalty   time.Duration
+	HalfLife     time.Duration
+
+	mu       sync.RWMutex
+	nodes    map[string]*nodeStats
+	maxRound map[telemetry.Kind]int64
+	now      func() time.Time
+}
+
+// NewRegistry creates a Registry with the default settings.
+func NewRegistry() *Registry {
+	return &Registry{
+		Decay:        DefaultDecay,
+		ErrorPenalty: DefaultErrorPenalty,
+		LagPenalty:   DefaultLagPenalty,
+		HalfLife:     DefaultHalfLife,
+		nodes:        make(map[string]*nodeStats),
+		maxRound:     make(map[telemetry.Kind]int64),
+		now:          time.Now,
+	}
+}
+
+// Record records the outcome of a request to node: its latency, and its
+// status code or the error if it has no response. Transport errors and 5xx
+// status codes are failures; requests canceled by the caller are ignored.
+func (r *Registry) Record(kind telemetry.Kind, node string, latency time.Duration, statusCode int, err error) {
+	if node == "" || (err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded))) {
+		return
+	}
+	failed := err != nil || statusCode >= http.StatusInternalServerError
+	node = NodeOf(node)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.get(kind, node)
+	failure := 0.0
+	if failed {
+		failure = 1
+		s.failures++
+		if err != nil {
+			s.lastError = err.Error()
+		} else {
+			s.lastError = http.StatusText(statusCode)
+		}
+	}
+	if s.requests == 0 {
+		s.latency = float64(latency)
+		s.errorRate = failure
+	} else {
+		prev := r.decayed(s.errorRate, s.lastSeen)
+		s.latency += r.Decay * (float64(latency) - s.latency)
+		s.errorRate = prev + r.Decay*(failure-prev)
+	}
+	s.requests++
+	s.lastSeen = r.now()
+}
+
+// ObserveRound records the latest finalized round reported by node.
+func (r *Registry) ObserveRound(kind telemetry.Kind, node string, round int64) {
+	if node == "" {
+		return
+	}
+	node = NodeOf(node)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.get(kind, node)
+	if round > s.round {
+		s.round = round
+	}
+	if round > r.maxRound[kind] {
+		r.maxRound[kind] = round
+	}
+}
+
+func (r *Registry) get(kind telemetry.Kind, node string) *nodeStats {
+	s, ok := r.nodes[node]
+	if !ok {
+		s = &nodeStats{kind: kind}
+		r.nodes[node] = s
+	}
+	if kind != telemetry.KindHTTP {
+		s.kind = kind
+	}
+	return s
+}
+
+// decayed returns v halved every HalfLife since t.
+func (r *Registry) decayed(v float64, t time.Time) float64 {
+	if r.HalfLife <= 0 || t.IsZero() {
+		return v
+	}
+	return v * math.Pow(0.5, float64(r.now().Sub(t))/float64(r.HalfLife))
+}
+
+func (r *Registry) stats(node string, s *nodeStats) Stats {
+	st := Stats{
+		Node:      node,
+		Kind:      s.kind,
+		Latency:   time.Duration(s.latency),
+		ErrorRate: r.decayed(s.errorRate, s.lastSeen),
+		Requests:  s.requests,
+		Failures:  s.failures,
+		Round:     s.round,
+		LastSeen:  s.lastSeen,
+		LastError: s.lastError,
+	}
+	if s.round > 0 {
+		st.RoundLag = r.maxRound[s.kind] - s.round
+	}
+	lag := r.decayed(float64(st.RoundLag), s.lastSeen)
+	st.Score = st.Latency +
+		time.Duration(st.ErrorRate*float64(r.ErrorPenalty)) +
+		time.Duration(lag*float64(r.LagPenalty))
+	return st
+}
+
+// Get returns the health of node, false if nothing is known about it.
+func (r *Registry) Get(node string) (Stats, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.nodes[NodeOf(node)]
+	if !ok {
+		return Stats{}, false
+	}
+	return r.stats(NodeOf(node), s), true
+}
+
+// Snapshot returns the health of all the known nodes, by kind and from the
+// best one.
+func (r *Registry) Snapshot() []Stats {
+	r.mu.RLock()
+	list := make([]Stats, 0, len(r.nodes))
+	for node, s := range r.nodes {
+		list = append(list, r.stats(node, s))
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind < list[j].Kind
+		}
+		if list[i].Score != list[j].Score {
+			return list[i].Score < list[j].Score
+		}
+		return list[i].Node < list[j].Node
+	})
+	return list
+}
+
+// Rank returns nodes ordered from the best one. Nodes without samples get the
+// average score of the others, ties are shuffled so that the load is spread.
+func (r *Registry) Rank(nodes []string) []string {
+	ranked := make([]string, len(nodes))
+	copy(ranked, nodes)
+	rand.Shuffle(len(ranked), func(i, j int) { ranked[i], ranked[j] = ranked[j], ranked[i] })
+
+	scores := make(map[string]time.Duration, len(ranked))
+	var (
+		total time.Duration
+		known int
+	)
+	r.mu.RLock()
+	for _, node := range ranked {
+		if s, ok := r.nodes[NodeOf(node)]; ok && s.requests > 0 {
+			score := r.stats(node, s).Score
+			scores[node] = score
+			total += score
+			known++
+		}
+	}
+	r.mu.RUnlock()
+
+	if known > 0 {
+		avg := total / time.Duration(known)
+		for _, node := range ranked {
+			if _, ok := scores[node]; !ok {
+				scores[node] = avg
+			}
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i]] < scores[ranked[j]]
+	})
+	return ranked
+}
+
+// Best returns the n best nodes, all of them if there are less than n.
+func (r *Registry) Best(nodes []string, n int) []string {
+	ranked := r.Rank(nodes)
+	if n < len(ranked) {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// Reset forgets all the nodes.
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	r.nodes = make(map[string]*nodeStats)
+	r.maxRound = make(map[telemetry.Kind]int64)
+	r.mu.Unlock()
+}
+
+// NodeOf returns the node serving rawurl: its scheme, host and the path prefix
+// before the API path "/v1/", as several nodes can be served by the same host.
+func NodeOf(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return strings.TrimSuffix(rawurl, "/")
+	}
+	return NodeOfURL(u)
+}
+
+// NodeOfURL is NodeOf for a parsed URL.
+func NodeOfURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	p := u.Path
+	if i := strings.Index(p, "/v1/"); i >= 0 {
+		p = p[:i]
+	}
+	return u.Scheme + "://" + u.Host + strings.TrimSuffix(p, "/")
+}
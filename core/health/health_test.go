@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/0chain/gosdk/core/telemetry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	now := time.Now()
+	r := NewRegistry()
+	r.now = func() time.Time { return now }
+
+	const (
+		fast  = "https://dev.0chain.net/sharder01"
+		slow  = "https://dev.0chain.net/sharder02"
+		flaky = "https://dev.0chain.net/sharder03"
+		fresh = "https://dev.0chain.net/sharder04"
+	)
+	for i := 0; i < 5; i++ {
+		r.Record(telemetry.KindSharder, fast+"/v1/screst/abc", 50*time.Millisecond, http.StatusOK, nil)
+		r.Record(telemetry.KindSharder, slow, 400*time.Millisecond, http.StatusOK, nil)
+		r.Record(telemetry.KindSharder, flaky, 10*time.Millisecond, 0, errors.New("connection refused"))
+	}
+	r.Record(telemetry.KindSharder, fast, time.Hour, 0, context.Canceled)
+
+	s, ok := r.Get(fast)
+	require.True(t, ok)
+	require.Equal(t, 50*time.Millisecond, s.Latency)
+	require.EqualValues(t, 5, s.Requests)
+	require.Zero(t, s.ErrorRate)
+
+	s, _ = r.Get(flaky)
+	require.EqualValues(t, 5, s.Failures)
+	require.Equal(t, 1.0, s.ErrorRate)
+	require.Equal(t, "connection refused", s.LastError)
+
+	require.Equal(t, []string{fast, slow, flaky}, r.Rank([]string{flaky, slow, fast}))
+	require.Equal(t, []string{fast}, r.Best([]string{flaky, fresh, slow, fast}, 1))
+
+	// a sharder behind by rounds is ranked after the others
+	r.ObserveRound(telemetry.KindSharder, fast, 100)
+	r.ObserveRound(telemetry.KindSharder, slow, 110)
+	s, _ = r.Get(fast)
+	require.EqualValues(t, 10, s.RoundLag)
+	require.Equal(t, []string{slow, fast}, r.Rank([]string{fast, slow}))
+
+	// the errors are forgotten over time
+	now = now.Add(10 * r.HalfLife)
+	s, _ = r.Get(flaky)
+	require.Less(t, s.ErrorRate, 0.001)
+
+	snapshot := r.Snapshot()
+	require.Len(t, snapshot, 3)
+	require.Equal(t, flaky, snapshot[0].Node)
+
+	r.Reset()
+	require.Empty(t, r.Snapshot())
+}
+
+func TestNodeOf(t *testing.T) {
+	require.Equal(t, "https://dev.0chain.net/blobber01", NodeOf("https://dev.0chain.net/blobber01/v1/file/upload/abc"))
+	require.Equal(t, "https://dev.0chain.net/miner01", NodeOf("https://dev.0chain.net/miner01/"))
+	require.Equal(t, "http://127.0.0.1:5051", NodeOf("http://127.0.0.1:5051/v1/file/upload/abc"))
+}
@@ -0,0 +1,290 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Version is the version of the keystore file format.
+const Version = 1
+
+const (
+	// KDFScrypt derives the encryption key with scrypt.
+	KDFScrypt = "scrypt"
+	// KDFArgon2id derives the encryption key with argon2id.
+	KDFArgon2id = "argon2id"
+	// CipherAESGCM encrypts the wallet with AES-256 in GCM mode.
+	CipherAESGCM = "aes-256-gcm"
+
+	keyLen  = 32
+	saltLen = 32
+)
+
+// Upper bounds of the key derivation parameters read from keystore files, so
+// a crafted file can't make the key derivation exhaust the memory or the cpu.
+const (
+	maxScryptMemory = 1 << 30 // 128*N*R bytes
+	maxScryptP      = 16
+	maxArgon2Time   = 16
+	maxArgon2Memory = 1 << 20 // in KiB
+)
+
+// Options are the key derivation settings used to encrypt wallets.
+type Options struct {
+	// KDF is KDFScrypt or KDFArgon2id.
+	KDF string
+
+	ScryptN int
+	ScryptR int
+	ScryptP int
+
+	Argon2Time    uint32
+	Argon2Memory  uint32 // in KiB
+	Argon2Threads uint8
+}
+
+var (
+	// StandardOptions are the recommended settings, taking about a second to
+	// derive a key.
+	StandardOptions = Options{
+		KDF:           KDFScrypt,
+		ScryptN:       1 << 18,
+		ScryptR:       8,
+		ScryptP:       1,
+		Argon2Time:    3,
+		Argon2Memory:  64 * 1024,
+		Argon2Threads: 4,
+	}
+
+	// LightOptions are weaker settings for devices with little memory and for
+	// tests.
+	LightOptions = Options{
+		KDF:           KDFScrypt,
+		ScryptN:       1 << 12,
+		ScryptR:       8,
+		ScryptP:       6,
+		Argon2Time:    1,
+		Argon2Memory:  4 * 1024,
+		Argon2Threads: 2,
+	}
+)
+
+// KDFParams are the parameters the key of a wallet was derived with.
+type KDFParams struct {
+	Salt string `json:"salt"`
+
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+// CryptoParams describe how a wallet is encrypted.
+type CryptoParams struct {
+	Cipher     string    `json:"cipher"`
+	CipherText string    `json:"ciphertext"`
+	Nonce      string    `json:"nonce"`
+	KDF        string    `json:"kdf"`
+	KDFParams  KDFParams `json:"kdfparams"`
+}
+
+// EncryptedWallet is the content of a keystore file. The client id and key are
+// in clear, to find a wallet without its password; they are authenticated
+// with the encrypted wallet.
+type EncryptedWallet struct {
+	Version   int          `json:"version"`
+	ClientID  string       `json:"client_id"`
+	ClientKey string       `json:"client_key"`
+	Crypto    CryptoParams `json:"crypto"`
+}
+
+// Encrypt encrypts the wallet with a key derived from password, with opts or
+// StandardOptions if nil.
+func Encrypt(w *zcncrypto.Wallet, password string, opts *Options) (*EncryptedWallet, error) {
+	if opts == nil {
+		opts = &StandardOptions
+	}
+	plain, err := json.Marshal(w)
+	if err != nil {
+		return nil, errors.Wrap(err, "keystore: failed to marshal wallet")
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	params := KDFParams{Salt: hex.EncodeToString(salt)}
+	switch opts.KDF {
+	case KDFScrypt, "":
+		params.N, params.R, params.P = opts.ScryptN, opts.ScryptR, opts.ScryptP
+	case KDFArgon2id:
+		params.Time, params.Memory, params.Threads = opts.Argon2Time, opts.Argon2Memory, opts.Argon2Threads
+	default:
+		return nil, errors.New("keystore", "unknown kdf: "+opts.KDF)
+	}
+
+	e := &EncryptedWallet{
+		Version:   Version,
+		ClientID:  w.ClientID,
+		ClientKey: w.ClientKey,
+		Crypto: CryptoParams{
+			Cipher:    CipherAESGCM,
+			KDF:       opts.KDF,
+			KDFParams: params,
+		},
+	}
+	if e.Crypto.KDF == "" {
+		e.Crypto.KDF = KDFScrypt
+	}
+	if err := e.Crypto.validateKDF(); err != nil {
+		return nil, err
+	}
+
+	key, err := e.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	e.Crypto.Nonce = hex.EncodeToString(nonce)
+	e.Crypto.CipherText = hex.EncodeToString(gcm.Seal(nil, nonce, plain, e.additionalData()))
+	return e, nil
+}
+
+// Decrypt decrypts the wallet with password.
+func (e *EncryptedWallet) Decrypt(password string) (*zcncrypto.Wallet, error) {
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(e.Crypto.Nonce)
+	if err != nil {
+		return nil, errors.Throw(ErrInvalidFile, "invalid nonce")
+	}
+	cipherText, err := hex.DecodeString(e.Crypto.CipherText)
+	if err != nil {
+		return nil, errors.Throw(ErrInvalidFile, "invalid ciphertext")
+	}
+
+	key, err := e.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.Throw(ErrInvalidFile, "invalid nonce")
+	}
+	plain, err := gcm.Open(nil, nonce, cipherText, e.additionalData())
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	w := &zcncrypto.Wallet{}
+	if err := json.Unmarshal(plain, w); err != nil {
+		return nil, errors.Throw(ErrInvalidFile, err.Error())
+	}
+	return w, nil
+}
+
+// ParseEncryptedWallet parses the content of a keystore file.
+func ParseEncryptedWallet(data []byte) (*EncryptedWallet, error) {
+	e := &EncryptedWallet{}
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, errors.Throw(ErrInvalidFile, err.Error())
+	}
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *EncryptedWallet) validate() error {
+	if e.Version != Version {
+		return errors.Throw(ErrInvalidFile, fmt.Sprintf("unsupported version %d", e.Version))
+	}
+	if e.Crypto.Cipher != CipherAESGCM {
+		return errors.Throw(ErrInvalidFile, "unsupported cipher: "+e.Crypto.Cipher)
+	}
+	if e.ClientID == "" {
+		return errors.Throw(ErrInvalidFile, "missing client id")
+	}
+	return e.Crypto.validateKDF()
+}
+
+func (c *CryptoParams) validateKDF() error {
+	p := c.KDFParams
+	switch c.KDF {
+	case KDFScrypt:
+		if p.N <= 1 || p.N&(p.N-1) != 0 || p.R <= 0 || p.P <= 0 {
+			return errors.Throw(ErrInvalidFile, "invalid scrypt parameters")
+		}
+		if p.P > maxScryptP || p.R > maxScryptMemory/128/p.N {
+			return errors.Throw(ErrInvalidFile, "scrypt parameters too large")
+		}
+	case KDFArgon2id:
+		if p.Time == 0 || p.Memory == 0 || p.Threads == 0 {
+			return errors.Throw(ErrInvalidFile, "invalid argon2id parameters")
+		}
+		if p.Time > maxArgon2Time || p.Memory > maxArgon2Memory {
+			return errors.Throw(ErrInvalidFile, "argon2id parameters too large")
+		}
+	default:
+		return errors.Throw(ErrInvalidFile, "unknown kdf: "+c.KDF)
+	}
+	return nil
+}
+
+// additionalData binds the clear fields to the ciphertext, so they can't be
+// changed without the password.
+func (e *EncryptedWallet) additionalData() []byte {
+	return []byte(fmt.Sprintf("%d:%s:%s", e.Version, e.ClientID, e.ClientKey))
+}
+
+func (e *EncryptedWallet) deriveKey(password string) ([]byte, error) {
+	p := e.Crypto.KDFParams
+	salt, err := hex.DecodeString(p.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, errors.Throw(ErrInvalidFile, "invalid salt")
+	}
+
+	switch e.Crypto.KDF {
+	case KDFScrypt:
+		key, err := scrypt.Key([]byte(password), salt, p.N, p.R, p.P, keyLen)
+		if err != nil {
+			return nil, errors.Throw(ErrInvalidFile, err.Error())
+		}
+		return key, nil
+	case KDFArgon2id:
+		return argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, keyLen), nil
+	default:
+		return nil, errors.Throw(ErrInvalidFile, "unknown kdf: "+e.Crypto.KDF)
+	}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
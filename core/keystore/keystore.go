@@ -0,0 +1,266 @@
+package keystore
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/zcncrypto"
+)
+
+const fileExt = ".json"
+
+var namePattern = regexp.MustCompile(`^[0-9a-zA-Z_-][0-9a-zA-Z_.-]*$`)
+
+// Keystore is a directory of named, encrypted wallets, one file per wallet.
+//
+//	ks, err := keystore.New(dir, nil)
+//	err = ks.Create("default", wallet, password)
+//	err = ks.Unlock("default", password, 10*time.Minute)
+//	wallet, err = ks.Wallet("default")
+type Keystore struct {
+	dir  string
+	opts Options
+
+	mu       sync.Mutex
+	unlocked map[string]*unlockedWallet
+}
+
+type unlockedWallet struct {
+	wallet *zcncrypto.Wallet
+	timer  *time.Timer
+}
+
+// New opens the keystore in dir. The directory is created when the first
+// wallet is stored. New wallets are encrypted with opts, StandardOptions if nil.
+func New(dir string, opts *Options) (*Keystore, error) {
+	ks := &Keystore{
+		dir:      dir,
+		opts:     StandardOptions,
+		unlocked: make(map[string]*unlockedWallet),
+	}
+	if opts != nil {
+		ks.opts = *opts
+	}
+	return ks, nil
+}
+
+// List returns the names of the wallets, sorted.
+func (ks *Keystore) List() ([]string, error) {
+	entries, err := os.ReadDir(ks.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), fileExt)
+		if entry.IsDir() || name == entry.Name() || !namePattern.MatchString(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Info returns the encrypted wallet, to read its client id and key without the
+// password.
+func (ks *Keystore) Info(name string) (*EncryptedWallet, error) {
+	return ks.read(name)
+}
+
+// Create encrypts the wallet with password and stores it under name.
+func (ks *Keystore) Create(name string, w *zcncrypto.Wallet, password string) error {
+	e, err := Encrypt(w, password, &ks.opts)
+	if err != nil {
+		return err
+	}
+	return ks.write(name, e, false)
+}
+
+// Get decrypts the wallet with password.
+func (ks *Keystore) Get(name, password string) (*zcncrypto.Wallet, error) {
+	e, err := ks.read(name)
+	if err != nil {
+		return nil, err
+	}
+	return e.Decrypt(password)
+}
+
+// Delete removes the wallet, once password is checked.
+func (ks *Keystore) Delete(name, password string) error {
+	if _, err := ks.Get(name, password); err != nil {
+		return err
+	}
+	ks.Lock(name)
+	return os.Remove(ks.path(name))
+}
+
+// ChangePassword encrypts the wallet with a new password.
+func (ks *Keystore) ChangePassword(name, oldPassword, newPassword string) error {
+	w, err := ks.Get(name, oldPassword)
+	if err != nil {
+		return err
+	}
+	e, err := Encrypt(w, newPassword, &ks.opts)
+	if err != nil {
+		return err
+	}
+	return ks.write(name, e, true)
+}
+
+// Export returns the encrypted keystore file of the wallet, to be imported in
+// another keystore.
+func (ks *Keystore) Export(name string) ([]byte, error) {
+	if !namePattern.MatchString(name) {
+		return nil, ErrInvalidName
+	}
+	data, err := os.ReadFile(ks.path(name))
+	if os.IsNotExist(err) {
+		return nil, errors.Throw(ErrNotFound, name)
+	}
+	return data, err
+}
+
+// Import stores an exported keystore file under name. The file is kept
+// encrypted with its own password.
+func (ks *Keystore) Import(name string, data []byte) error {
+	e, err := ParseEncryptedWallet(data)
+	if err != nil {
+		return err
+	}
+	return ks.write(name, e, false)
+}
+
+// ImportWallet encrypts a plain json wallet, like the ones created by
+// zcncore.CreateWallet, and stores it under name.
+func (ks *Keystore) ImportWallet(name, walletJSON, password string) error {
+	w := &zcncrypto.Wallet{}
+	if err := json.Unmarshal([]byte(walletJSON), w); err != nil {
+		return errors.Wrap(err, "keystore: invalid wallet")
+	}
+	return ks.Create(name, w, password)
+}
+
+// ExportWallet returns the wallet as plain json.
+func (ks *Keystore) ExportWallet(name, password string) (string, error) {
+	w, err := ks.Get(name, password)
+	if err != nil {
+		return "", err
+	}
+	return w.Marshal()
+}
+
+// Unlock decrypts the wallet and keeps it in memory for timeout, until Lock if
+// zero. The timeout only covers the wallet returned by Wallet: a copy handed
+// to the sdk, like zcncore.SetWalletFromKeystore does, stays in memory.
+func (ks *Keystore) Unlock(name, password string, timeout time.Duration) error {
+	w, err := ks.Get(name, password)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if u, ok := ks.unlocked[name]; ok && u.timer != nil {
+		u.timer.Stop()
+	}
+	u := &unlockedWallet{wallet: w}
+	if timeout > 0 {
+		u.timer = time.AfterFunc(timeout, func() {
+			ks.mu.Lock()
+			defer ks.mu.Unlock()
+			if ks.unlocked[name] == u {
+				delete(ks.unlocked, name)
+			}
+		})
+	}
+	ks.unlocked[name] = u
+	return nil
+}
+
+// Lock forgets the unlocked wallet.
+func (ks *Keystore) Lock(name string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if u, ok := ks.unlocked[name]; ok {
+		if u.timer != nil {
+			u.timer.Stop()
+		}
+		delete(ks.unlocked, name)
+	}
+}
+
+// Wallet returns the wallet if it is unlocked, ErrLocked otherwise.
+func (ks *Keystore) Wallet(name string) (*zcncrypto.Wallet, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	u, ok := ks.unlocked[name]
+	if !ok {
+		return nil, errors.Throw(ErrLocked, name)
+	}
+	return u.wallet, nil
+}
+
+func (ks *Keystore) path(name string) string {
+	return filepath.Join(ks.dir, name+fileExt)
+}
+
+func (ks *Keystore) read(name string) (*EncryptedWallet, error) {
+	data, err := ks.Export(name)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEncryptedWallet(data)
+}
+
+// write stores the wallet atomically, replacing the existing one only if
+// replace is set.
+func (ks *Keystore) write(name string, e *EncryptedWallet, replace bool) error {
+	if !namePattern.MatchString(name) {
+		return ErrInvalidName
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	path := ks.path(name)
+	if _, err := os.Stat(path); err == nil && !replace {
+		return errors.Throw(ErrExists, name)
+	} else if os.IsNotExist(err) && replace {
+		return errors.Throw(ErrNotFound, name)
+	}
+
+	if err := os.MkdirAll(ks.dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(ks.dir, "."+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,187 @@
+package keystore
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/stretchr/testify/require"
+)
+
+func testWallet() *zcncrypto.Wallet {
+	return &zcncrypto.Wallet{
+		ClientID:  "30764bcba73216b67c36b05a17b4dd076bfdc5bb0ed84856f27622188c377269",
+		ClientKey: "1f495df9605a4479a7dd6e5c7a78caf9f9d54e3a40f62a3dd68ed377115fe614",
+		Keys: []zcncrypto.KeyPair{{
+			PublicKey:  "1f495df9605a4479a7dd6e5c7a78caf9f9d54e3a40f62a3dd68ed377115fe614",
+			PrivateKey: "41729ed8d82f782646d2d30b9719acfd236842b9b6e47fee12b7bdbd05b35122",
+		}},
+		Mnemonic: "glare mistake gun joke bid spare across diagram wrap cube swear cactus",
+		Version:  "1.0",
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	for _, kdf := range []string{KDFScrypt, KDFArgon2id} {
+		t.Run(kdf, func(t *testing.T) {
+			opts := LightOptions
+			opts.KDF = kdf
+			e, err := Encrypt(testWallet(), "secret", &opts)
+			require.NoError(t, err)
+			require.Equal(t, kdf, e.Crypto.KDF)
+
+			data, err := json.Marshal(e)
+			require.NoError(t, err)
+			require.NotContains(t, string(data), testWallet().Keys[0].PrivateKey)
+			require.NotContains(t, string(data), "glare")
+
+			w, err := e.Decrypt("secret")
+			require.NoError(t, err)
+			require.Equal(t, testWallet(), w)
+
+			_, err = e.Decrypt("wrong")
+			require.True(t, errors.Is(err, ErrDecrypt))
+
+			// the clear fields are authenticated
+			e.ClientID = "other"
+			_, err = e.Decrypt("secret")
+			require.True(t, errors.Is(err, ErrDecrypt))
+		})
+	}
+}
+
+func TestKeystore(t *testing.T) {
+	ks, err := New(t.TempDir(), &LightOptions)
+	require.NoError(t, err)
+
+	require.NoError(t, ks.Create("main", testWallet(), "secret"))
+	require.True(t, errors.Is(ks.Create("main", testWallet(), "secret"), ErrExists))
+	require.True(t, errors.Is(ks.Create("../main", testWallet(), "secret"), ErrInvalidName))
+
+	walletJSON, err := testWallet().Marshal()
+	require.NoError(t, err)
+	require.NoError(t, ks.ImportWallet("backup", walletJSON, "other"))
+
+	names, err := ks.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"backup", "main"}, names)
+
+	info, err := ks.Info("main")
+	require.NoError(t, err)
+	require.Equal(t, testWallet().ClientID, info.ClientID)
+
+	_, err = ks.Get("missing", "secret")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	require.NoError(t, ks.ChangePassword("main", "secret", "new"))
+	_, err = ks.Get("main", "secret")
+	require.True(t, errors.Is(err, ErrDecrypt))
+	w, err := ks.Get("main", "new")
+	require.NoError(t, err)
+	require.Equal(t, testWallet(), w)
+
+	// export from one keystore, import in another one
+	data, err := ks.Export("main")
+	require.NoError(t, err)
+	other, err := New(t.TempDir(), &LightOptions)
+	require.NoError(t, err)
+	require.NoError(t, other.Import("imported", data))
+	w, err = other.Get("imported", "new")
+	require.NoError(t, err)
+	require.Equal(t, testWallet(), w)
+	require.True(t, errors.Is(other.Import("bad", []byte("{}")), ErrInvalidFile))
+
+	require.True(t, errors.Is(ks.Delete("backup", "secret"), ErrDecrypt))
+	require.NoError(t, ks.Delete("backup", "other"))
+	names, err = ks.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"main"}, names)
+}
+
+func TestKeystoreUnlock(t *testing.T) {
+	ks, err := New(t.TempDir(), &LightOptions)
+	require.NoError(t, err)
+	require.NoError(t, ks.Create("main", testWallet(), "secret"))
+
+	_, err = ks.Wallet("main")
+	require.True(t, errors.Is(err, ErrLocked))
+	require.True(t, errors.Is(ks.Unlock("main", "wrong", 0), ErrDecrypt))
+
+	require.NoError(t, ks.Unlock("main", "secret", 0))
+	w, err := ks.Wallet("main")
+	require.NoError(t, err)
+	require.Equal(t, testWallet().ClientID, w.ClientID)
+	ks.Lock("main")
+	_, err = ks.Wallet("main")
+	require.True(t, errors.Is(err, ErrLocked))
+
+	require.NoError(t, ks.Unlock("main", "secret", 20*time.Millisecond))
+	_, err = ks.Wallet("main")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, err := ks.Wallet("main")
+		return errors.Is(err, ErrLocked)
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestKDFBounds(t *testing.T) {
+	e, err := Encrypt(testWallet(), "secret", &LightOptions)
+	require.NoError(t, err)
+	for name, params := range map[string]func(p *KDFParams){
+		"scrypt n":    func(p *KDFParams) { p.N = 1 << 30 },
+		"scrypt r":    func(p *KDFParams) { p.R = 1 << 20 },
+		"scrypt p":    func(p *KDFParams) { p.P = 1 << 20 },
+		"scrypt zero": func(p *KDFParams) { p.N = 0 },
+	} {
+		c := *e
+		params(&c.Crypto.KDFParams)
+		data, err := json.Marshal(&c)
+		require.NoError(t, err)
+		_, err = ParseEncryptedWallet(data)
+		require.True(t, errors.Is(err, ErrInvalidFile), name)
+		_, err = c.Decrypt("secret")
+		require.True(t, errors.Is(err, ErrInvalidFile), name)
+	}
+
+	opts := LightOptions
+	opts.KDF = KDFArgon2id
+	e, err = Encrypt(testWallet(), "secret", &opts)
+	require.NoError(t, err)
+	for name, params := range map[string]func(p *KDFParams){
+		"argon2 time":   func(p *KDFParams) { p.Time = 1 << 30 },
+		"argon2 memory": func(p *KDFParams) { p.Memory = 1 << 30 },
+	} {
+		c := *e
+		params(&c.Crypto.KDFParams)
+		_, err = c.Decrypt("secret")
+		require.True(t, errors.Is(err, ErrInvalidFile), name)
+	}
+
+	opts.Argon2Memory = 1 << 30
+	_, err = Encrypt(testWallet(), "secret", &opts)
+	require.True(t, errors.Is(err, ErrInvalidFile))
+}
+
+func TestKeystoreCreatesDirOnWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keystore")
+	ks, err := New(dir, &LightOptions)
+	require.NoError(t, err)
+
+	// reading doesn't create the directory
+	_, err = ks.Get("main", "secret")
+	require.True(t, errors.Is(err, ErrNotFound))
+	names, err := ks.List()
+	require.NoError(t, err)
+	require.Empty(t, names)
+	_, err = os.Stat(dir)
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, ks.Create("main", testWallet(), "secret"))
+	names, err = ks.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"main"}, names)
+}
@@ -0,0 +1,26 @@
+// Package keystore stores 0chain wallets in password encrypted files.
+package keystore
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNotFound no wallet with this name in the keystore
+	ErrNotFound = errors.New("[keystore] wallet not found")
+
+	// ErrExists a wallet with this name is already in the keystore
+	ErrExists = errors.New("[keystore] wallet already exists")
+
+	// ErrInvalidName the wallet name is empty or not a valid file name
+	ErrInvalidName = errors.New("[keystore] invalid wallet name")
+
+	// ErrInvalidFile the keystore file is malformed
+	ErrInvalidFile = errors.New("[keystore] invalid keystore file")
+
+	// ErrDecrypt the password is wrong or the keystore file was tampered with
+	ErrDecrypt = errors.New("[keystore] could not decrypt wallet")
+
+	// ErrLocked the wallet is not unlocked
+	ErrLocked = errors.New("[keystore] wallet is locked")
+)
@@ -0,0 +1,174 @@
+package resty
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/0chain/gosdk/core/health"
+)
+
+// ErrCircuitOpen is returned instead of sending a request to a host whose
+// circuit is open.
+var ErrCircuitOpen = errors.New("resty: circuit is open, the host is unhealthy")
+
+// CircuitState is the state of the circuit of a host.
+type CircuitState int
+
+const (
+	// CircuitClosed the requests are sent to the host.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen the requests to the host fail with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen a single probe request is sent to the host, its outcome
+	// closes or opens the circuit.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker tracks the health of the hosts, see NodeOf for the host of a URL. The circuit of a host opens
+// after FailureThreshold consecutive failures; after OpenTimeout, a probe
+// request is let through, its success closes the circuit and its failure opens
+// it again. It is safe for concurrent use.
+type CircuitBreaker struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+	now   func() time.Time
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker creates a CircuitBreaker.
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		OpenTimeout:      openTimeout,
+		hosts:            make(map[string]*circuit),
+		now:              time.Now,
+	}
+}
+
+// Allow returns ErrCircuitOpen if no request should be sent to host. Every
+// allowed request must be followed by a call to Record with its outcome.
+// A nil CircuitBreaker allows every request.
+func (cb *CircuitBreaker) Allow(host string) error {
+	if cb == nil {
+		return nil
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.hosts[host]
+	if !ok {
+		return nil
+	}
+	if c.state == CircuitClosed {
+		return nil
+	}
+	// open, or half-open with a probe in progress which is given up after
+	// OpenTimeout if its outcome is never recorded
+	now := cb.now()
+	if now.Sub(c.openedAt) < cb.OpenTimeout {
+		return ErrCircuitOpen
+	}
+	c.state = CircuitHalfOpen
+	c.openedAt = now
+	return nil
+}
+
+// Record records the outcome of a request sent to host: its status code, or
+// the error if it has no response. Transport errors and 5xx status codes are
+// failures; the cancellation or the deadline of the request context are
+// ignored.
+func (cb *CircuitBreaker) Record(host string, statusCode int, err error) {
+	if cb == nil {
+		return
+	}
+	if err != nil && (errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen)) {
+		return
+	}
+	failed := err != nil || statusCode >= http.StatusInternalServerError
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.hosts[host]
+	if !failed {
+		if ok {
+			delete(cb.hosts, host)
+		}
+		return
+	}
+	if !ok {
+		c = &circuit{}
+		cb.hosts[host] = c
+	}
+	c.failures++
+	if c.state == CircuitHalfOpen || c.failures >= cb.FailureThreshold {
+		c.state = CircuitOpen
+		c.openedAt = cb.now()
+	}
+}
+
+// State returns the state of the circuit of host.
+func (cb *CircuitBreaker) State(host string) CircuitState {
+	if cb == nil {
+		return CircuitClosed
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.hosts[host]
+	if !ok {
+		return CircuitClosed
+	}
+	if c.state == CircuitOpen && cb.now().Sub(c.openedAt) >= cb.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// Reset closes the circuit of host.
+func (cb *CircuitBreaker) Reset(host string) {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	delete(cb.hosts, host)
+	cb.mu.Unlock()
+}
+
+// NodeOf returns the node of u tracked by the circuit breaker, see health.NodeOf.
+func NodeOf(u *url.URL) string {
+	return health.NodeOfURL(u)
+}
+
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
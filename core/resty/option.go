@@ -2,17 +2,35 @@ package resty
 
 import (
 	"net/http"
+
+	"github.com/0chain/gosdk/core/telemetry"
 )
 
-// WithRetry set retry times if request is failure with 5xx status code. retry is ingore if it is less than 1.
+// WithRetry set the maximum attempts of a failed request, with the default backoff. retry is ignored if it is less than 1.
 func WithRetry(retry int) Option {
 	return func(r *Resty) {
 		if retry > 0 {
-			r.retry = retry
+			r.retryPolicy = NewBackoffPolicy(retry)
 		}
 	}
 }
 
+// WithRetryPolicy set the retry policy, NoRetry disables the retries
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(r *Resty) {
+		if policy != nil {
+			r.retryPolicy = policy
+		}
+	}
+}
+
+// WithCircuitBreaker set the circuit breaker, DefaultCircuitBreaker by default. nil disables it.
+func WithCircuitBreaker(cb *CircuitBreaker) Option {
+	return func(r *Resty) {
+		r.breaker = cb
+	}
+}
+
 // WithHeader set header for http request
 func WithHeader(header map[string]string) Option {
 	return func(r *Resty) {
@@ -40,6 +58,13 @@ func WithTransport(transport *http.Transport) Option {
 	}
 }
 
+// WithTelemetryKind set the kind of the nodes reported to the telemetry, telemetry.KindHTTP by default
+func WithTelemetryKind(kind telemetry.Kind) Option {
+	return func(r *Resty) {
+		r.kind = kind
+	}
+}
+
 // WithClient set client
 func WithClient(c Client) Option {
 	return func(r *Resty) {
@@ -10,7 +10,8 @@ import (
 	"sync"
 	"time"
 
-	"github.com/0chain/gosdk/core/sys"
+	"github.com/0chain/gosdk/core/health"
+	"github.com/0chain/gosdk/core/telemetry"
 )
 
 func clone(m map[string]string) map[string]string {
@@ -27,9 +28,11 @@ func New(opts ...Option) *Resty {
 	r := &Resty{
 		// Default timeout to use for HTTP requests when either the parent context doesn't have a timeout set
 		// or the context's timeout is longer than DefaultRequestTimeout.
-		timeout: DefaultRequestTimeout,
-		retry:   DefaultRetry,
-		header:  clone(DefaultHeader),
+		timeout:     DefaultRequestTimeout,
+		retryPolicy: NewBackoffPolicy(DefaultRetry),
+		breaker:     DefaultCircuitBreaker,
+		header:      clone(DefaultHeader),
+		kind:        telemetry.KindHTTP,
 	}
 
 	for _, option := range opts {
@@ -78,9 +81,11 @@ type Resty struct {
 	handle             Handle
 	requestInterceptor func(req *http.Request) error
 
-	timeout time.Duration
-	retry   int
-	header  map[string]string
+	timeout     time.Duration
+	retryPolicy RetryPolicy
+	breaker     *CircuitBreaker
+	header      map[string]string
+	kind        telemetry.Kind
 }
 
 // Then is used to call the handle function when the request has completed processing
@@ -153,44 +158,49 @@ func (r *Resty) httpDo(req *http.Request) {
 		defer wg.Done()
 		var resp *http.Response
 		var err error
-		if r.retry > 0 {
-			for i := 1; ; i++ {
-				var bodyCopy io.ReadCloser
-				if (request.Method == http.MethodPost || request.Method == http.MethodPut) && request.Body != nil {
-					// clone io.ReadCloser to fix retry issue https://github.com/golang/go/issues/36095
-					bodyCopy, _ = request.GetBody() //nolint: errcheck
-				}
+		host := NodeOf(request.URL)
+		for i := 1; ; i++ {
+			var bodyCopy io.ReadCloser
+			if request.Body != nil && request.GetBody != nil {
+				// clone io.ReadCloser to fix retry issue https://github.com/golang/go/issues/36095
+				bodyCopy, _ = request.GetBody() //nolint: errcheck
+			}
 
+			resp = nil
+			if err = r.breaker.Allow(host); err == nil {
+				start := time.Now()
 				resp, err = r.client.Do(request)
-				//success: 200,201,202,204
-				if resp != nil && (resp.StatusCode == http.StatusOK ||
-					resp.StatusCode == http.StatusCreated ||
-					resp.StatusCode == http.StatusAccepted ||
-					resp.StatusCode == http.StatusNoContent) {
-					break
-				}
-				// close body ReadClose to release resource before retrying it
-				if resp != nil && resp.Body != nil {
-					// don't close it if it is latest retry
-					if i < r.retry {
-						resp.Body.Close()
-					}
-				}
-
-				if i == r.retry {
-					break
+				r.breaker.Record(host, statusCode(resp), err)
+				if r.kind != telemetry.KindHTTP {
+					health.Default.Record(r.kind, host, time.Since(start), statusCode(resp), err)
 				}
+				telemetry.RecordRequest(r.ctx, telemetry.NewRequest(r.kind, request, resp, start, i, err))
+			}
+			//success: 200,201,202,204
+			if resp != nil && (resp.StatusCode == http.StatusOK ||
+				resp.StatusCode == http.StatusCreated ||
+				resp.StatusCode == http.StatusAccepted ||
+				resp.StatusCode == http.StatusNoContent) {
+				break
+			}
 
-				if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
-					sys.Sleep(1 * time.Second)
-				}
+			delay, retry := r.retryPolicy.Backoff(i, request, resp, err)
+			if !retry {
+				break
+			}
+			// close body ReadClose to release resource before retrying it
+			if resp != nil && resp.Body != nil {
+				resp.Body.Close()
+			}
+			telemetry.RecordRetry(r.ctx, telemetry.Retry{Operation: "resty", Attempt: i + 1, Err: err})
+			if !wait(r.ctx, delay) {
+				resp, err = nil, r.ctx.Err()
+				break
+			}
 
-				if (request.Method == http.MethodPost || request.Method == http.MethodPut) && request.Body != nil {
-					request.Body = bodyCopy
-				}
+			if bodyCopy != nil {
+				request.Body = bodyCopy
 			}
-		} else {
-			resp, err = r.client.Do(request.WithContext(r.ctx))
 		}
 
 		result := Result{Request: request, Response: resp, Err: err}
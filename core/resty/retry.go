@@ -0,0 +1,173 @@
+package resty
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides if a failed request is retried, and when.
+type RetryPolicy interface {
+	// Backoff is called after the attempt-th attempt of req failed, with its
+	// response or error. It returns the delay before the next attempt, and false
+	// if the request must not be retried.
+	Backoff(attempt int, req *http.Request, resp *http.Response, err error) (time.Duration, bool)
+}
+
+// NoRetry is the RetryPolicy sending every request once.
+var NoRetry RetryPolicy = noRetry{}
+
+type noRetry struct{}
+
+func (noRetry) Backoff(int, *http.Request, *http.Response, error) (time.Duration, bool) {
+	return 0, false
+}
+
+// BackoffPolicy retries the failed requests with an exponential backoff:
+// BaseDelay, 2*BaseDelay, 4*BaseDelay... up to MaxDelay, randomized by Jitter.
+// A Retry-After header of the response overrides the computed delay, up to
+// MaxDelay.
+//
+// Network errors, 408, 429, 500, 502, 503 and 504 are retried. Requests with a
+// non idempotent method (POST, PATCH) are only retried if they were not
+// processed: dial errors, 429 and 503. They are retried as the idempotent ones
+// if RetryNonIdempotent is set, or if they carry an Idempotency-Key header.
+type BackoffPolicy struct {
+	// MaxAttempts is the maximum number of attempts, the first one included.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Jitter is the fraction of the delay that is randomized, between 0 and 1.
+	Jitter             float64
+	RetryNonIdempotent bool
+}
+
+// NewBackoffPolicy returns a BackoffPolicy making up to maxAttempts attempts,
+// with the default delays.
+func NewBackoffPolicy(maxAttempts int) *BackoffPolicy {
+	return &BackoffPolicy{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+		Jitter:      DefaultRetryJitter,
+	}
+}
+
+// Backoff implements RetryPolicy.
+func (p *BackoffPolicy) Backoff(attempt int, req *http.Request, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || !p.retryable(req, resp, err) {
+		return 0, false
+	}
+
+	delay := p.delay(attempt)
+	if d, ok := retryAfter(resp); ok {
+		delay = d
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay, true
+}
+
+func (p *BackoffPolicy) delay(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 && delay > 0 {
+		jitter := time.Duration(p.Jitter * float64(delay))
+		delay = delay - jitter + time.Duration(rand.Int63n(int64(jitter)+1))
+	}
+	return delay
+}
+
+func (p *BackoffPolicy) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, ErrCircuitOpen) {
+			return false
+		}
+		if p.RetryNonIdempotent || IsIdempotent(req) {
+			return true
+		}
+		return isDialError(err)
+	}
+	if resp == nil {
+		return false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusRequestTimeout, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusGatewayTimeout:
+		return p.RetryNonIdempotent || IsIdempotent(req)
+	}
+	return false
+}
+
+// IsIdempotent reports whether sending req several times has the same effect
+// as sending it once.
+func IsIdempotent(req *http.Request) bool {
+	if req == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+// isDialError reports whether err happened before the request was sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// retryAfter parses the Retry-After header of resp, in seconds or as a date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// wait waits for d, it returns false if ctx is done first.
+func wait(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
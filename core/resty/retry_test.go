@@ -0,0 +1,148 @@
+package resty
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0chain/gosdk/core/resty/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffPolicy(t *testing.T) {
+	p := &BackoffPolicy{MaxAttempts: 4, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	get, _ := http.NewRequest(http.MethodGet, "http://sharder/v1/block", nil)
+	post, _ := http.NewRequest(http.MethodPost, "http://miner/v1/transaction/put", nil)
+	status := func(code int) *http.Response {
+		return &http.Response{StatusCode: code, Header: http.Header{}}
+	}
+
+	delay, ok := p.Backoff(1, get, status(http.StatusBadGateway), nil)
+	require.True(t, ok)
+	require.Equal(t, 100*time.Millisecond, delay)
+	delay, _ = p.Backoff(2, get, status(http.StatusBadGateway), nil)
+	require.Equal(t, 200*time.Millisecond, delay)
+	delay, _ = p.Backoff(3, get, status(http.StatusBadGateway), nil)
+	require.Equal(t, 300*time.Millisecond, delay)
+	_, ok = p.Backoff(4, get, status(http.StatusBadGateway), nil)
+	require.False(t, ok, "max attempts")
+
+	_, ok = p.Backoff(1, get, status(http.StatusBadRequest), nil)
+	require.False(t, ok, "client error")
+	_, ok = p.Backoff(1, get, nil, context.Canceled)
+	require.False(t, ok, "canceled")
+
+	// non idempotent requests are only retried if they were not processed
+	_, ok = p.Backoff(1, post, status(http.StatusInternalServerError), nil)
+	require.False(t, ok)
+	_, ok = p.Backoff(1, post, nil, errors.New("connection reset"))
+	require.False(t, ok)
+	_, ok = p.Backoff(1, post, nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")})
+	require.True(t, ok)
+	_, ok = p.Backoff(1, post, status(http.StatusServiceUnavailable), nil)
+	require.True(t, ok)
+	post.Header.Set("Idempotency-Key", "txn-hash")
+	_, ok = p.Backoff(1, post, status(http.StatusInternalServerError), nil)
+	require.True(t, ok)
+
+	resp := status(http.StatusTooManyRequests)
+	resp.Header.Set("Retry-After", "0")
+	delay, ok = p.Backoff(1, get, resp, nil)
+	require.True(t, ok)
+	require.Zero(t, delay)
+	resp.Header.Set("Retry-After", "120")
+	delay, _ = p.Backoff(1, get, resp, nil)
+	require.Equal(t, p.MaxDelay, delay)
+
+	p.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		delay, _ = p.Backoff(2, get, status(http.StatusBadGateway), nil)
+		require.True(t, delay >= 100*time.Millisecond && delay <= 200*time.Millisecond, delay)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	cb := NewCircuitBreaker(2, time.Minute)
+	cb.now = func() time.Time { return now }
+	const node = "http://blobber01"
+
+	cb.Record(node, http.StatusBadGateway, nil)
+	require.Equal(t, CircuitClosed, cb.State(node))
+	cb.Record(node, 0, context.Canceled)
+	cb.Record(node, http.StatusNotFound, nil)
+	require.Equal(t, CircuitClosed, cb.State(node), "a success resets the failures")
+
+	cb.Record(node, 0, errors.New("connection refused"))
+	cb.Record(node, http.StatusInternalServerError, nil)
+	require.Equal(t, CircuitOpen, cb.State(node))
+	require.ErrorIs(t, cb.Allow(node), ErrCircuitOpen)
+	require.NoError(t, cb.Allow("http://blobber02"))
+
+	// a single probe after the timeout, its failure opens the circuit again
+	now = now.Add(time.Minute)
+	require.Equal(t, CircuitHalfOpen, cb.State(node))
+	require.NoError(t, cb.Allow(node))
+	require.ErrorIs(t, cb.Allow(node), ErrCircuitOpen)
+	cb.Record(node, http.StatusServiceUnavailable, nil)
+	require.Equal(t, CircuitOpen, cb.State(node))
+
+	now = now.Add(time.Minute)
+	require.NoError(t, cb.Allow(node))
+	cb.Record(node, http.StatusOK, nil)
+	require.Equal(t, CircuitClosed, cb.State(node))
+
+	var disabled *CircuitBreaker
+	require.NoError(t, disabled.Allow(node))
+}
+
+func TestNodeOf(t *testing.T) {
+	for rawurl, node := range map[string]string{
+		"https://dev.0chain.net/sharder01/v1/screst/abc": "https://dev.0chain.net/sharder01",
+		"https://dev.0chain.net/miner01/":                "https://dev.0chain.net/miner01",
+		"http://127.0.0.1:5051/v1/file/upload/abc":       "http://127.0.0.1:5051",
+	} {
+		u, err := http.NewRequest(http.MethodGet, rawurl, nil)
+		require.NoError(t, err)
+		require.Equal(t, node, NodeOf(u.URL))
+	}
+}
+
+func TestRestyRetry(t *testing.T) {
+	const url = "http://Test_Resty_Retry/v1/block"
+	client := &mocks.Client{}
+	client.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: ioutil.NopCloser(strings.NewReader("busy"))}
+	}, nil).Times(2)
+	client.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("ok"))}
+	}, nil).Once()
+
+	r := New(WithClient(client), WithCircuitBreaker(NewCircuitBreaker(5, time.Minute)),
+		WithRetryPolicy(&BackoffPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	var body string
+	r.Then(func(req *http.Request, resp *http.Response, respBody []byte, cf context.CancelFunc, err error) error {
+		body = string(respBody)
+		return err
+	})
+	r.DoGet(context.TODO(), url)
+	require.Empty(t, r.Wait())
+	require.Equal(t, "ok", body)
+	client.AssertNumberOfCalls(t, "Do", 3)
+
+	// the circuit is open, no request is sent
+	cb := NewCircuitBreaker(1, time.Minute)
+	cb.Record("http://Test_Resty_Retry", http.StatusBadGateway, nil)
+	r = New(WithClient(client), WithCircuitBreaker(cb))
+	r.DoGet(context.TODO(), url)
+	errs := r.Wait()
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], ErrCircuitOpen)
+	client.AssertNumberOfCalls(t, "Do", 3)
+}
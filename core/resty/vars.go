@@ -9,6 +9,15 @@ var (
 	DefaultDialTimeout = 5 * time.Second
 	// DefaultRequestTimeout default time out of a http request
 	DefaultRequestTimeout = 10 * time.Second
-	// DefaultRetry retry times if a request is failed with 5xx status code
+	// DefaultRetry maximum attempts of a failed request, the first one included
 	DefaultRetry = 3
+	// DefaultRetryBaseDelay delay before the first retry of a request
+	DefaultRetryBaseDelay = 200 * time.Millisecond
+	// DefaultRetryMaxDelay maximum delay between two attempts of a request
+	DefaultRetryMaxDelay = 5 * time.Second
+	// DefaultRetryJitter fraction of the retry delay that is randomized
+	DefaultRetryJitter = 0.2
+
+	// DefaultCircuitBreaker circuit breaker shared by the requests to the sharders, miners and blobbers
+	DefaultCircuitBreaker = NewCircuitBreaker(5, 30*time.Second)
 )
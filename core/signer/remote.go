@@ -0,0 +1,166 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/0chain/errors"
+)
+
+const (
+	keyPath  = "/v1/key"
+	signPath = "/v1/sign"
+
+	// DefaultRemoteTimeout bounds a request to a remote signer.
+	DefaultRemoteTimeout = 10 * time.Second
+
+	maxResponseSize = 64 * 1024
+)
+
+// KeyResponse is the response of the key endpoint of a remote signer.
+type KeyResponse struct {
+	ClientID        string `json:"client_id"`
+	PublicKey       string `json:"public_key"`
+	SignatureScheme string `json:"signature_scheme"`
+}
+
+// SignRequest is the request of the sign endpoint of a remote signer.
+type SignRequest struct {
+	Hash string `json:"hash"`
+}
+
+// SignResponse is the response of the sign endpoint of a remote signer.
+type SignResponse struct {
+	Signature string `json:"signature"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// RemoteSigner is a Signer delegating to a remote signer over http, see
+// NewHandler for the server side.
+type RemoteSigner struct {
+	url    string
+	token  string
+	client *http.Client
+	key    KeyResponse
+}
+
+// RemoteOption configures a RemoteSigner.
+type RemoteOption func(s *RemoteSigner)
+
+// WithToken authenticates the requests with a bearer token.
+func WithToken(token string) RemoteOption {
+	return func(s *RemoteSigner) {
+		s.token = token
+	}
+}
+
+// WithHTTPClient sets the http client, to configure TLS client certificates
+// for example.
+func WithHTTPClient(client *http.Client) RemoteOption {
+	return func(s *RemoteSigner) {
+		s.client = client
+	}
+}
+
+// NewRemoteSigner connects to the remote signer at url and reads the key it
+// signs with.
+func NewRemoteSigner(ctx context.Context, url string, opts ...RemoteOption) (*RemoteSigner, error) {
+	s := &RemoteSigner{
+		url:    strings.TrimRight(url, "/"),
+		client: &http.Client{Timeout: DefaultRemoteTimeout},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.do(ctx, http.MethodGet, keyPath, nil, &s.key); err != nil {
+		return nil, err
+	}
+	if !isSignatureScheme(s.key.SignatureScheme) || s.key.PublicKey == "" {
+		return nil, errors.New("remote_signer", "invalid key: "+s.key.SignatureScheme)
+	}
+	return s, nil
+}
+
+// ClientID implements Signer.
+func (s *RemoteSigner) ClientID() string {
+	return s.key.ClientID
+}
+
+// PublicKey implements Signer.
+func (s *RemoteSigner) PublicKey() string {
+	return s.key.PublicKey
+}
+
+// SignatureScheme implements Signer.
+func (s *RemoteSigner) SignatureScheme() string {
+	return s.key.SignatureScheme
+}
+
+// Sign implements Signer.
+func (s *RemoteSigner) Sign(hash string) (string, error) {
+	return s.SignContext(context.Background(), hash)
+}
+
+// SignContext signs the hash, the request is canceled with ctx.
+func (s *RemoteSigner) SignContext(ctx context.Context, hash string) (string, error) {
+	var resp SignResponse
+	if err := s.do(ctx, http.MethodPost, signPath, &SignRequest{Hash: hash}, &resp); err != nil {
+		return "", err
+	}
+	if resp.Signature == "" {
+		return "", errors.New("remote_signer", "empty signature")
+	}
+	return resp.Signature, nil
+}
+
+func (s *RemoteSigner) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		buf, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.url+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "remote_signer: request failed")
+	}
+	defer resp.Body.Close()
+	buf, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return errors.Wrap(err, "remote_signer: failed to read response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e errorResponse
+		if json.Unmarshal(buf, &e) != nil || e.Error == "" {
+			e.Error = http.StatusText(resp.StatusCode)
+		}
+		return errors.New("remote_signer", fmt.Sprintf("%s: %s", resp.Status, e.Error))
+	}
+	if err := json.Unmarshal(buf, out); err != nil {
+		return errors.Wrap(err, "remote_signer: invalid response")
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package signer
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/0chain/errors"
+)
+
+const maxHashLen = 128
+
+// NewHandler returns the http handler of a reference remote signer, signing
+// with s the hashes requested by RemoteSigner. Requests must carry the bearer
+// token, which can't be empty. Only hex encoded hashes up to 64 bytes are
+// accepted; any client holding the token can still have any such value signed.
+//
+//	s, _ := signer.NewLocalSigner("bls0chain", wallet)
+//	h, err := signer.NewHandler(s, token)
+//	http.ListenAndServeTLS(":9443", cert, key, h)
+func NewHandler(s Signer, token string) (http.Handler, error) {
+	if token == "" {
+		return nil, errors.New("signer", "the token is required")
+	}
+	h := &handler{signer: s, token: token}
+	mux := http.NewServeMux()
+	mux.HandleFunc(keyPath, h.authorize(h.key))
+	mux.HandleFunc(signPath, h.authorize(h.sign))
+	return mux, nil
+}
+
+type handler struct {
+	signer Signer
+	token  string
+}
+
+func (h *handler) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, &errorResponse{Error: "invalid token"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (h *handler) key(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, &KeyResponse{
+		ClientID:        h.signer.ClientID(),
+		PublicKey:       h.signer.PublicKey(),
+		SignatureScheme: h.signer.SignatureScheme(),
+	})
+}
+
+func (h *handler) sign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "method not allowed"})
+		return
+	}
+	var req SignRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxResponseSize)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "invalid request"})
+		return
+	}
+	if req.Hash == "" || len(req.Hash) > maxHashLen {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "invalid hash"})
+		return
+	}
+	if _, err := hex.DecodeString(req.Hash); err != nil {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "invalid hash"})
+		return
+	}
+
+	sig, err := h.signer.Sign(req.Hash)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, &SignResponse{Signature: sig})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) //nolint: errcheck
+}
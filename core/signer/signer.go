@@ -0,0 +1,79 @@
+// Package signer signs hashes on behalf of a client, with its keys held in
+// process or by a remote signer, so that the keys of production wallets can
+// live in a separate hardened process.
+package signer
+
+import (
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/zcncrypto"
+)
+
+// Signer signs hashes with the keys of a client.
+type Signer interface {
+	// ClientID is the id of the client whose keys sign.
+	ClientID() string
+	// PublicKey is the public key of the client.
+	PublicKey() string
+	// SignatureScheme is "bls0chain" or "ed25519".
+	SignatureScheme() string
+	// Sign signs the hash.
+	Sign(hash string) (string, error)
+}
+
+// LocalSigner is a Signer holding the keys of a wallet in process.
+type LocalSigner struct {
+	scheme string
+	wallet *zcncrypto.Wallet
+}
+
+// NewLocalSigner creates a LocalSigner with the keys of the wallet. When the
+// wallet has several keys, their signatures are combined, like client.Sign.
+func NewLocalSigner(scheme string, w *zcncrypto.Wallet) (*LocalSigner, error) {
+	if !isSignatureScheme(scheme) {
+		return nil, errors.New("signer", "unknown signature scheme: "+scheme)
+	}
+	if w == nil || len(w.Keys) == 0 {
+		return nil, errors.New("signer", "the wallet has no keys")
+	}
+	return &LocalSigner{scheme: scheme, wallet: w}, nil
+}
+
+// ClientID implements Signer.
+func (s *LocalSigner) ClientID() string {
+	return s.wallet.ClientID
+}
+
+// PublicKey implements Signer.
+func (s *LocalSigner) PublicKey() string {
+	return s.wallet.ClientKey
+}
+
+// SignatureScheme implements Signer.
+func (s *LocalSigner) SignatureScheme() string {
+	return s.scheme
+}
+
+// Sign implements Signer.
+func (s *LocalSigner) Sign(hash string) (string, error) {
+	var sig string
+	for _, kp := range s.wallet.Keys {
+		ss := zcncrypto.NewSignatureScheme(s.scheme)
+		if err := ss.SetPrivateKey(kp.PrivateKey); err != nil {
+			return "", err
+		}
+		var err error
+		if sig == "" {
+			sig, err = ss.Sign(hash)
+		} else {
+			sig, err = ss.Add(sig, hash)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return sig, nil
+}
+
+func isSignatureScheme(scheme string) bool {
+	return scheme == "bls0chain" || scheme == "ed25519"
+}
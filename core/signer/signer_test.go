@@ -0,0 +1,76 @@
+package signer
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0chain/gosdk/core/encryption"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/stretchr/testify/require"
+)
+
+func newLocalSigner(t *testing.T, scheme string) *LocalSigner {
+	w, err := zcncrypto.NewSignatureScheme(scheme).GenerateKeys()
+	require.NoError(t, err)
+	s, err := NewLocalSigner(scheme, w)
+	require.NoError(t, err)
+	return s
+}
+
+func verify(t *testing.T, s Signer, sig, hash string) bool {
+	ss := zcncrypto.NewSignatureScheme(s.SignatureScheme())
+	require.NoError(t, ss.SetPublicKey(s.PublicKey()))
+	ok, err := ss.Verify(sig, hash)
+	require.NoError(t, err)
+	return ok
+}
+
+func TestLocalSigner(t *testing.T) {
+	_, err := NewLocalSigner("rsa", &zcncrypto.Wallet{})
+	require.Error(t, err)
+	_, err = NewLocalSigner("bls0chain", &zcncrypto.Wallet{})
+	require.Error(t, err)
+
+	for _, scheme := range []string{"bls0chain", "ed25519"} {
+		t.Run(scheme, func(t *testing.T) {
+			s := newLocalSigner(t, scheme)
+			hash := encryption.Hash("message")
+			sig, err := s.Sign(hash)
+			require.NoError(t, err)
+			require.True(t, verify(t, s, sig, hash))
+		})
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	local := newLocalSigner(t, "bls0chain")
+	_, err := NewHandler(local, "")
+	require.Error(t, err)
+	handler, err := NewHandler(local, "token")
+	require.NoError(t, err)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	ctx := context.Background()
+
+	_, err = NewRemoteSigner(ctx, server.URL)
+	require.Error(t, err)
+
+	_, err = NewRemoteSigner(ctx, server.URL, WithToken("wrong"))
+	require.Error(t, err)
+
+	remote, err := NewRemoteSigner(ctx, server.URL+"/", WithToken("token"))
+	require.NoError(t, err)
+	require.Equal(t, local.ClientID(), remote.ClientID())
+	require.Equal(t, local.PublicKey(), remote.PublicKey())
+	require.Equal(t, "bls0chain", remote.SignatureScheme())
+
+	hash := encryption.Hash("message")
+	sig, err := remote.Sign(hash)
+	require.NoError(t, err)
+	require.True(t, verify(t, remote, sig, hash))
+
+	// only hashes are signed
+	_, err = remote.Sign("not a hash")
+	require.Error(t, err)
+}
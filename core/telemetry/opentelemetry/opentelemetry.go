@@ -0,0 +1,123 @@
+// Package opentelemetry reports the SDK telemetry as OpenTelemetry spans.
+//
+//	telemetry.Set(opentelemetry.New(tracerProvider))
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0chain/gosdk/core/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/0chain/gosdk"
+
+// Instrumentation implements telemetry.Instrumentation with an OpenTelemetry
+// tracer. Requests are reported as client spans, consensus outcomes and
+// retries as events of the current span.
+type Instrumentation struct {
+	tracer trace.Tracer
+}
+
+// New creates the instrumentation. The global tracer provider is used if tp is nil.
+func New(tp trace.TracerProvider) *Instrumentation {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Instrumentation{tracer: tp.Tracer(instrumentationName)}
+}
+
+// StartSpan implements telemetry.Instrumentation.
+func (i *Instrumentation) StartSpan(ctx context.Context, name string) (context.Context, telemetry.Span) {
+	ctx, s := i.tracer.Start(ctx, name)
+	return ctx, span{s}
+}
+
+// RequestDone implements telemetry.Instrumentation.
+func (i *Instrumentation) RequestDone(ctx context.Context, r telemetry.Request) {
+	_, s := i.tracer.Start(ctx, "HTTP "+r.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(r.Start),
+		trace.WithAttributes(
+			attribute.String("gosdk.node.kind", string(r.Kind)),
+			attribute.String("http.method", r.Method),
+			attribute.String("net.peer.name", r.Host),
+			attribute.String("http.target", r.Path),
+			attribute.Int("http.status_code", r.StatusCode),
+			attribute.Int64("http.request_content_length", r.BytesSent),
+			attribute.Int64("http.response_content_length", r.BytesReceived),
+			attribute.Int("gosdk.attempt", r.Attempt),
+		))
+	if r.Err != nil {
+		s.RecordError(r.Err)
+		s.SetStatus(codes.Error, r.Err.Error())
+	} else if r.StatusCode >= 400 {
+		s.SetStatus(codes.Error, fmt.Sprintf("status code %d", r.StatusCode))
+	}
+	s.End(trace.WithTimestamp(r.Start.Add(r.Duration)))
+}
+
+// ConsensusDone implements telemetry.Instrumentation.
+func (i *Instrumentation) ConsensusDone(ctx context.Context, c telemetry.Consensus) {
+	trace.SpanFromContext(ctx).AddEvent("consensus", trace.WithAttributes(
+		attribute.String("gosdk.operation", c.Operation),
+		attribute.String("gosdk.node.kind", string(c.Kind)),
+		attribute.Int("gosdk.consensus.required", c.Required),
+		attribute.Int("gosdk.consensus.got", c.Got),
+		attribute.Int("gosdk.consensus.total", c.Total),
+		attribute.Bool("gosdk.consensus.ok", c.OK),
+	))
+}
+
+// RetryAttempt implements telemetry.Instrumentation.
+func (i *Instrumentation) RetryAttempt(ctx context.Context, r telemetry.Retry) {
+	attrs := []attribute.KeyValue{
+		attribute.String("gosdk.operation", r.Operation),
+		attribute.Int("gosdk.attempt", r.Attempt),
+	}
+	if r.Err != nil {
+		attrs = append(attrs, attribute.String("error", r.Err.Error()))
+	}
+	trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(attrs...))
+}
+
+type span struct {
+	trace.Span
+}
+
+func (s span) SetAttribute(key string, value interface{}) {
+	s.SetAttributes(toAttribute(key, value))
+}
+
+func (s span) End(err error) {
+	if err != nil {
+		s.RecordError(err)
+		s.SetStatus(codes.Error, err.Error())
+	}
+	s.Span.End()
+}
+
+func toAttribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case uint64:
+		return attribute.Int64(key, int64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case fmt.Stringer:
+		return attribute.Stringer(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,122 @@
+// Package prometheus exposes the SDK telemetry as Prometheus metrics.
+//
+//	inst, err := prometheus.New(prom.DefaultRegisterer, "gosdk")
+//	...
+//	telemetry.Set(inst)
+package prometheus
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/0chain/gosdk/core/telemetry"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Instrumentation implements telemetry.Instrumentation with Prometheus
+// collectors:
+//
+//	<namespace>_request_duration_seconds{kind,host,method,status}
+//	<namespace>_request_bytes_total{kind,host,direction}
+//	<namespace>_consensus_total{operation,kind,result}
+//	<namespace>_retries_total{operation}
+//	<namespace>_span_duration_seconds{name,result}
+type Instrumentation struct {
+	requestDuration *prom.HistogramVec
+	requestBytes    *prom.CounterVec
+	consensus       *prom.CounterVec
+	retries         *prom.CounterVec
+	spanDuration    *prom.HistogramVec
+}
+
+// New creates the instrumentation and registers its collectors with reg.
+func New(reg prom.Registerer, namespace string) (*Instrumentation, error) {
+	i := &Instrumentation{
+		requestDuration: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the requests to the blobbers, sharders and miners.",
+			Buckets:   prom.ExponentialBuckets(0.01, 2, 12),
+		}, []string{"kind", "host", "method", "status"}),
+		requestBytes: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "request_bytes_total",
+			Help:      "Bytes sent and received, by node.",
+		}, []string{"kind", "host", "direction"}),
+		consensus: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "consensus_total",
+			Help:      "Consensus outcomes of the operations.",
+		}, []string{"operation", "kind", "result"}),
+		retries: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Retried operations.",
+		}, []string{"operation"}),
+		spanDuration: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "span_duration_seconds",
+			Help:      "Duration of the SDK operations.",
+			Buckets:   prom.ExponentialBuckets(0.01, 2, 14),
+		}, []string{"name", "result"}),
+	}
+
+	for _, c := range []prom.Collector{i.requestDuration, i.requestBytes, i.consensus, i.retries, i.spanDuration} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
+}
+
+// StartSpan implements telemetry.Instrumentation.
+func (i *Instrumentation) StartSpan(ctx context.Context, name string) (context.Context, telemetry.Span) {
+	return ctx, &span{i: i, name: name, start: time.Now()}
+}
+
+// RequestDone implements telemetry.Instrumentation.
+func (i *Instrumentation) RequestDone(_ context.Context, r telemetry.Request) {
+	status := "error"
+	if r.Err == nil {
+		status = strconv.Itoa(r.StatusCode)
+	}
+	kind := string(r.Kind)
+	i.requestDuration.WithLabelValues(kind, r.Host, r.Method, status).Observe(r.Duration.Seconds())
+	if r.BytesSent > 0 {
+		i.requestBytes.WithLabelValues(kind, r.Host, "sent").Add(float64(r.BytesSent))
+	}
+	if r.BytesReceived > 0 {
+		i.requestBytes.WithLabelValues(kind, r.Host, "received").Add(float64(r.BytesReceived))
+	}
+}
+
+// ConsensusDone implements telemetry.Instrumentation.
+func (i *Instrumentation) ConsensusDone(_ context.Context, c telemetry.Consensus) {
+	i.consensus.WithLabelValues(c.Operation, string(c.Kind), result(c.OK)).Inc()
+}
+
+// RetryAttempt implements telemetry.Instrumentation.
+func (i *Instrumentation) RetryAttempt(_ context.Context, r telemetry.Retry) {
+	i.retries.WithLabelValues(r.Operation).Inc()
+}
+
+type span struct {
+	i     *Instrumentation
+	name  string
+	start time.Time
+}
+
+// SetAttribute is a no-op, attributes would make the cardinality unbounded.
+func (s *span) SetAttribute(string, interface{}) {}
+
+func (s *span) End(err error) {
+	s.i.spanDuration.WithLabelValues(s.name, result(err == nil)).Observe(time.Since(s.start).Seconds())
+}
+
+func result(ok bool) string {
+	if ok {
+		return "ok"
+	}
+	return "failed"
+}
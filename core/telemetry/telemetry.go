@@ -0,0 +1,186 @@
+// Package telemetry defines the instrumentation hooks called by the SDK on its
+// network calls: requests to blobbers, sharders and miners, consensus outcomes
+// and retries. The instrumentation is set once at init with Set, it does
+// nothing by default.
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kind is the kind of node a request is sent to.
+type Kind string
+
+const (
+	KindBlobber Kind = "blobber"
+	KindSharder Kind = "sharder"
+	KindMiner   Kind = "miner"
+	// KindHTTP is used when the kind of the node is unknown.
+	KindHTTP Kind = "http"
+)
+
+// Request describes a completed HTTP request.
+type Request struct {
+	Kind   Kind
+	Method string
+	Host   string
+	// Path is the URL path, without the query.
+	Path          string
+	StatusCode    int
+	Start         time.Time
+	Duration      time.Duration
+	BytesSent     int64
+	BytesReceived int64
+	// Attempt is 1 for the first attempt of the request.
+	Attempt int
+	Err     error
+}
+
+// Consensus describes the outcome of an operation needing the agreement of
+// several nodes.
+type Consensus struct {
+	Operation string
+	Kind      Kind
+	Required  int
+	Got       int
+	Total     int
+	OK        bool
+}
+
+// Retry describes a retried operation.
+type Retry struct {
+	// Operation is a fixed name, like "txn.verify", used as a metric label:
+	// it must not contain ids or paths.
+	Operation string
+	// Attempt is the number of the attempt about to be made, starting at 2.
+	Attempt int
+	Err     error
+}
+
+// Span is an operation in progress.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	// End ends the span, err is the outcome of the operation.
+	End(err error)
+}
+
+// Instrumentation receives the events of the SDK. Implementations must be
+// safe for concurrent use and must not block.
+type Instrumentation interface {
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+	RequestDone(ctx context.Context, r Request)
+	ConsensusDone(ctx context.Context, c Consensus)
+	RetryAttempt(ctx context.Context, r Retry)
+}
+
+// Nop is the Instrumentation used by default, it does nothing.
+type Nop struct{}
+
+func (Nop) StartSpan(ctx context.Context, _ string) (context.Context, Span) { return ctx, nopSpan{} }
+func (Nop) RequestDone(context.Context, Request)                            {}
+func (Nop) ConsensusDone(context.Context, Consensus)                        {}
+func (Nop) RetryAttempt(context.Context, Retry)                             {}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttribute(string, interface{}) {}
+func (nopSpan) End(error)                        {}
+
+type holder struct {
+	Instrumentation
+}
+
+var (
+	current atomic.Value
+	setOnce sync.Once
+)
+
+func init() {
+	current.Store(holder{Nop{}})
+}
+
+// Set sets the instrumentation of the SDK. It should be called once at init,
+// before the first network call; it returns false and does nothing if the
+// instrumentation is already set.
+func Set(i Instrumentation) bool {
+	if i == nil {
+		return false
+	}
+	set := false
+	setOnce.Do(func() {
+		current.Store(holder{i})
+		set = true
+	})
+	return set
+}
+
+// Get returns the instrumentation of the SDK.
+func Get() Instrumentation {
+	return current.Load().(holder).Instrumentation
+}
+
+// StartSpan starts a span with the instrumentation of the SDK.
+func StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Get().StartSpan(ctx, name)
+}
+
+// RecordRequest reports a completed request.
+func RecordRequest(ctx context.Context, r Request) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	Get().RequestDone(ctx, r)
+}
+
+// RecordConsensus reports a consensus outcome.
+func RecordConsensus(ctx context.Context, c Consensus) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	Get().ConsensusDone(ctx, c)
+}
+
+// RecordRetry reports a retry.
+func RecordRetry(ctx context.Context, r Retry) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	Get().RetryAttempt(ctx, r)
+}
+
+// NewRequest describes the request req, sent at start, and its response.
+// BytesReceived is taken from the Content-Length of the response; callers
+// reading the body should set it to the actual size.
+func NewRequest(kind Kind, req *http.Request, resp *http.Response, start time.Time, attempt int, err error) Request {
+	r := Request{
+		Kind:     kind,
+		Start:    start,
+		Duration: time.Since(start),
+		Attempt:  attempt,
+		Err:      err,
+	}
+	if req != nil {
+		r.Method = req.Method
+		if req.URL != nil {
+			r.Host = req.URL.Host
+			r.Path = req.URL.Path
+		}
+		if req.ContentLength > 0 {
+			r.BytesSent = req.ContentLength
+		}
+	}
+	if resp != nil {
+		r.StatusCode = resp.StatusCode
+		if resp.ContentLength > 0 {
+			r.BytesReceived = resp.ContentLength
+		}
+	}
+	return r
+}
@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	Nop
+	mu       sync.Mutex
+	requests []Request
+}
+
+func (r *recorder) RequestDone(_ context.Context, req Request) {
+	r.mu.Lock()
+	r.requests = append(r.requests, req)
+	r.mu.Unlock()
+}
+
+func TestSet(t *testing.T) {
+	require.IsType(t, Nop{}, Get())
+	require.False(t, Set(nil))
+
+	r := &recorder{}
+	require.True(t, Set(r))
+	require.False(t, Set(Nop{}))
+	require.Same(t, r, Get())
+
+	RecordRequest(nil, Request{Kind: KindSharder}) //nolint: staticcheck
+	RecordConsensus(context.Background(), Consensus{})
+	_, span := StartSpan(nil, "test") //nolint: staticcheck
+	span.End(nil)
+	require.Len(t, r.requests, 1)
+	require.Equal(t, KindSharder, r.requests[0].Kind)
+}
+
+func TestNewRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://blobber:5051/v1/file/upload/abc?x=1", nil)
+	require.NoError(t, err)
+	req.ContentLength = 10
+	resp := &http.Response{StatusCode: http.StatusOK, ContentLength: 20}
+
+	start := time.Now().Add(-time.Second)
+	r := NewRequest(KindBlobber, req, resp, start, 2, nil)
+	require.Equal(t, KindBlobber, r.Kind)
+	require.Equal(t, http.MethodPost, r.Method)
+	require.Equal(t, "blobber:5051", r.Host)
+	require.Equal(t, "/v1/file/upload/abc", r.Path)
+	require.Equal(t, http.StatusOK, r.StatusCode)
+	require.EqualValues(t, 10, r.BytesSent)
+	require.EqualValues(t, 20, r.BytesReceived)
+	require.Equal(t, 2, r.Attempt)
+	require.GreaterOrEqual(t, r.Duration, time.Second)
+
+	errFailed := errors.New("failed")
+	r = NewRequest(KindBlobber, req, nil, start, 1, errFailed)
+	require.Zero(t, r.StatusCode)
+	require.Equal(t, errFailed, r.Err)
+}
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/0chain/errors"
 	"github.com/0chain/gosdk/core/util"
 )
 
@@ -23,12 +24,21 @@ type NonceCache struct {
 	cache    map[string]int64
 	guard    sync.Mutex
 	sharders []string
+	manager  NonceManager
 }
 
 func InitCache(sharders []string) {
 	Cache.sharders = sharders
 }
 
+// SetNonceManager makes the nonce cache hand out the nonces of manager, nil
+// restores the in-memory cache.
+func SetNonceManager(manager NonceManager) {
+	Cache.guard.Lock()
+	defer Cache.guard.Unlock()
+	Cache.manager = manager
+}
+
 func init() {
 	once.Do(func() {
 		Cache = &NonceCache{
@@ -37,7 +47,24 @@ func init() {
 	})
 }
 
+// GetNextNonce returns the next nonce of the client, zero if it couldn't be
+// reserved. ReserveNextNonce returns the error.
 func (nc *NonceCache) GetNextNonce(clientId string) int64 {
+	nonce, _ := nc.ReserveNextNonce(clientId)
+	return nonce
+}
+
+// ReserveNextNonce returns the next nonce of the client. With a nonce manager
+// the nonce is reserved in its store, which serializes the reservations.
+func (nc *NonceCache) ReserveNextNonce(clientId string) (int64, error) {
+	if manager := nc.getManager(); manager != nil {
+		nonce, err := manager.Reserve(context.Background(), clientId)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to reserve a nonce of "+clientId)
+		}
+		return nonce, nil
+	}
+
 	nc.guard.Lock()
 	defer nc.guard.Unlock()
 	if _, ok := nc.cache[clientId]; !ok {
@@ -49,7 +76,13 @@ func (nc *NonceCache) GetNextNonce(clientId string) int64 {
 	}
 
 	nc.cache[clientId] += 1
-	return nc.cache[clientId]
+	return nc.cache[clientId], nil
+}
+
+func (nc *NonceCache) getManager() NonceManager {
+	nc.guard.Lock()
+	defer nc.guard.Unlock()
+	return nc.manager
 }
 
 func (nc *NonceCache) Set(clientId string, nonce int64) {
@@ -59,11 +92,33 @@ func (nc *NonceCache) Set(clientId string, nonce int64) {
 }
 
 func (nc *NonceCache) Evict(clientId string) {
+	if manager := nc.getManager(); manager != nil {
+		manager.Sync(context.Background(), clientId) //nolint: errcheck
+		return
+	}
 	nc.guard.Lock()
 	defer nc.guard.Unlock()
 	delete(nc.cache, clientId)
 }
 
+// Release gives back the nonce of a transaction that was not sent.
+func (nc *NonceCache) Release(clientId string, nonce int64) {
+	if manager := nc.getManager(); manager != nil {
+		manager.Release(context.Background(), clientId, nonce) //nolint: errcheck
+		return
+	}
+	nc.guard.Lock()
+	defer nc.guard.Unlock()
+	delete(nc.cache, clientId)
+}
+
+// Confirm records the nonce of a transaction confirmed on chain.
+func (nc *NonceCache) Confirm(clientId string, nonce int64) {
+	if manager := nc.getManager(); manager != nil {
+		manager.Confirm(context.Background(), clientId, nonce) //nolint: errcheck
+	}
+}
+
 func queryFromSharders(sharders []string, query string,
 	result chan *util.GetResponse) {
 
@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,9 @@ import (
 	"github.com/0chain/errors"
 	"github.com/0chain/gosdk/core/common"
 	"github.com/0chain/gosdk/core/encryption"
+	"github.com/0chain/gosdk/core/health"
+	"github.com/0chain/gosdk/core/resty"
+	"github.com/0chain/gosdk/core/telemetry"
 	"github.com/0chain/gosdk/core/util"
 	lru "github.com/hashicorp/golang-lru"
 )
@@ -266,6 +270,36 @@ func SendTransactionSync(txn *Transaction, miners []string) {
 	wg.Wait()
 }
 
+// sendTransaction sends txn to the miners, it fails if no miner accepted it.
+func sendTransaction(txn *Transaction, miners []string) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sent bool
+		errs []string
+	)
+	for _, miner := range miners {
+		wg.Add(1)
+		go func(miner string) {
+			defer wg.Done()
+			_, err := sendTransactionToURL(fmt.Sprintf("%v/%v", miner, TXN_SUBMIT_URL), txn, nil)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, miner+": "+err.Error())
+				return
+			}
+			sent = true
+		}(miner)
+	}
+	wg.Wait()
+
+	if !sent {
+		return errors.New("transaction_send_error", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
 func sendTransactionToURL(url string, txn *Transaction, wg *sync.WaitGroup) ([]byte, error) {
 	if wg != nil {
 		defer wg.Done()
@@ -275,7 +309,26 @@ func sendTransactionToURL(url string, txn *Transaction, wg *sync.WaitGroup) ([]b
 		//Logger.Error("Error in serializing the transaction", txn, err.Error())
 		return nil, err
 	}
+	node := strings.TrimSuffix(strings.TrimSuffix(url, TXN_SUBMIT_URL), "/")
+	if err := resty.DefaultCircuitBreaker.Allow(node); err != nil {
+		return nil, err
+	}
+	start := time.Now()
 	postResponse, err := postReq.Post()
+	resty.DefaultCircuitBreaker.Record(node, postResponse.StatusCode, err)
+	health.Default.Record(telemetry.KindMiner, node, time.Since(start), postResponse.StatusCode, err)
+	telemetry.RecordRequest(context.Background(), telemetry.Request{
+		Kind:          telemetry.KindMiner,
+		Method:        http.MethodPost,
+		Host:          node,
+		Path:          TXN_SUBMIT_URL,
+		StatusCode:    postResponse.StatusCode,
+		Start:         start,
+		Duration:      time.Since(start),
+		BytesReceived: int64(len(postResponse.Body)),
+		Attempt:       1,
+		Err:           err,
+	})
 	if postResponse.StatusCode >= 200 && postResponse.StatusCode <= 299 {
 		return []byte(postResponse.Body), nil
 	}
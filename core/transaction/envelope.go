@@ -0,0 +1,207 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/signer"
+	"github.com/0chain/gosdk/core/zcncrypto"
+)
+
+// EnvelopeVersion is the version of the envelope format written by this sdk.
+const EnvelopeVersion = 1
+
+// Envelope is the portable form of a transaction. It lets a transaction be
+// built and broadcast on an online machine and signed on an offline one:
+//
+//	env, _ := BuildUnsigned(req, miners)        // online
+//	data, _ := env.Marshal()
+//	env, _ = UnmarshalEnvelope(data)            // offline
+//	_ = env.Sign(scheme)
+//	data, _ = env.Marshal()
+//	env, _ = UnmarshalEnvelope(data)            // online
+//	hash, _ := BroadcastEnvelope(env, miners)
+//	txn, _ := VerifyTransaction(hash, sharders)
+type Envelope struct {
+	Version         int          `json:"version"`
+	SignatureScheme string       `json:"signature_scheme"`
+	Transaction     *Transaction `json:"transaction"`
+}
+
+// UnsignedTxn describes the transaction to build with BuildUnsigned.
+type UnsignedTxn struct {
+	ClientID        string
+	PublicKey       string
+	ChainID         string
+	SignatureScheme string
+
+	ToClientID      string
+	Value           uint64
+	TransactionType int
+	TransactionData string
+
+	// Nonce must be set, it can't be fetched on the signing machine.
+	Nonce int64
+	// Fee is estimated from the miners if zero for smart contract transactions.
+	Fee uint64
+	// CreationDate defaults to now.
+	CreationDate int64
+}
+
+// BuildUnsigned builds an unsigned transaction envelope. miners are only used to
+// estimate the fee, they can be nil if req.Fee is set.
+func BuildUnsigned(req UnsignedTxn, miners []string) (*Envelope, error) {
+	if req.Nonce <= 0 {
+		return nil, ErrInvalidNonce
+	}
+	if req.ClientID == "" || req.PublicKey == "" {
+		return nil, errors.New("build_unsigned", "client id and public key are required")
+	}
+	if !isSignatureScheme(req.SignatureScheme) {
+		return nil, errors.New("build_unsigned", "unknown signature scheme: "+req.SignatureScheme)
+	}
+
+	txn := NewTransactionEntity(req.ClientID, req.ChainID, req.PublicKey, req.Nonce)
+	if req.CreationDate > 0 {
+		txn.CreationDate = req.CreationDate
+	}
+	txn.ToClientID = req.ToClientID
+	txn.Value = req.Value
+	txn.TransactionType = req.TransactionType
+	txn.TransactionData = req.TransactionData
+	txn.TransactionFee = req.Fee
+
+	if txn.TransactionFee == 0 && txn.TransactionType == TxnTypeSmartContract && len(miners) > 0 {
+		fee, err := EstimateFee(txn, miners, 0.2)
+		if err != nil {
+			return nil, errors.Wrap(err, "build_unsigned: failed to estimate fee")
+		}
+		txn.TransactionFee = fee
+	}
+
+	txn.ComputeHashData()
+	return &Envelope{
+		Version:         EnvelopeVersion,
+		SignatureScheme: req.SignatureScheme,
+		Transaction:     txn,
+	}, nil
+}
+
+// Marshal serializes the envelope.
+func (e *Envelope) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// UnmarshalEnvelope parses a serialized envelope and checks that the hash of
+// the transaction matches its content.
+func UnmarshalEnvelope(data []byte) (*Envelope, error) {
+	e := &Envelope{}
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, errors.Throw(ErrInvalidEnvelope, err.Error())
+	}
+	if e.Version != EnvelopeVersion {
+		return nil, errors.Throw(ErrInvalidEnvelope, fmt.Sprintf("unsupported version %d", e.Version))
+	}
+	if e.Transaction == nil {
+		return nil, errors.Throw(ErrInvalidEnvelope, "missing transaction")
+	}
+	if !isSignatureScheme(e.SignatureScheme) {
+		return nil, errors.Throw(ErrInvalidEnvelope, "unknown signature scheme: "+e.SignatureScheme)
+	}
+	if err := e.checkHash(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// IsSigned returns true if the transaction of the envelope is signed.
+func (e *Envelope) IsSigned() bool {
+	return e.Transaction != nil && e.Transaction.Signature != ""
+}
+
+// Sign signs the transaction with scheme, whose private key must be set and
+// match the public key of the transaction.
+func (e *Envelope) Sign(scheme zcncrypto.SignatureScheme) error {
+	return e.sign(scheme.GetPublicKey(), scheme.Sign)
+}
+
+// SignWith signs the transaction with s, whose public key must match the
+// public key of the transaction.
+func (e *Envelope) SignWith(s signer.Signer) error {
+	if s.SignatureScheme() != e.SignatureScheme {
+		return errors.New("sign_envelope", "the signer uses the "+s.SignatureScheme()+" signature scheme")
+	}
+	return e.sign(s.PublicKey(), s.Sign)
+}
+
+func (e *Envelope) sign(publicKey string, sign SignFunc) error {
+	if e.Transaction == nil {
+		return errors.Throw(ErrInvalidEnvelope, "missing transaction")
+	}
+	if err := e.checkHash(); err != nil {
+		return err
+	}
+	if publicKey != "" && publicKey != e.Transaction.PublicKey {
+		return errors.New("sign_envelope", "the key doesn't match the public key of the transaction")
+	}
+	sig, err := sign(e.Transaction.Hash)
+	if err != nil {
+		return err
+	}
+	e.Transaction.Signature = sig
+	if err := e.VerifySignature(); err != nil {
+		e.Transaction.Signature = ""
+		return errors.Wrap(err, "sign_envelope: the key doesn't match the public key of the transaction")
+	}
+	return nil
+}
+
+// VerifySignature checks the signature of the transaction against its public
+// key.
+func (e *Envelope) VerifySignature() error {
+	if !e.IsSigned() {
+		return ErrNotSigned
+	}
+	scheme := zcncrypto.NewSignatureScheme(e.SignatureScheme)
+	if err := scheme.SetPublicKey(e.Transaction.PublicKey); err != nil {
+		return err
+	}
+	ok, err := e.Transaction.VerifyTransaction(func(signature, msgHash, publicKey string) (bool, error) {
+		return scheme.Verify(signature, msgHash)
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("verify_envelope", "invalid signature")
+	}
+	return nil
+}
+
+// BroadcastEnvelope verifies the signature of the envelope and sends its
+// transaction to the miners. It returns the hash of the transaction once at
+// least one miner accepted it; use VerifyTransaction to wait for it.
+func BroadcastEnvelope(e *Envelope, miners []string) (string, error) {
+	if err := e.VerifySignature(); err != nil {
+		return "", err
+	}
+	if err := sendTransaction(e.Transaction, miners); err != nil {
+		return "", err
+	}
+	return e.Transaction.Hash, nil
+}
+
+func (e *Envelope) checkHash() error {
+	hash := e.Transaction.Hash
+	e.Transaction.ComputeHashData()
+	if hash != e.Transaction.Hash {
+		e.Transaction.Hash = hash
+		return errors.Throw(ErrInvalidEnvelope, "hash mismatch")
+	}
+	return nil
+}
+
+func isSignatureScheme(scheme string) bool {
+	return scheme == "bls0chain" || scheme == "ed25519"
+}
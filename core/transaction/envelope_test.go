@@ -0,0 +1,108 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/signer"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/stretchr/testify/require"
+)
+
+func newEd25519Signer(t *testing.T) (*zcncrypto.Wallet, zcncrypto.SignatureScheme) {
+	w, err := zcncrypto.NewSignatureScheme("ed25519").GenerateKeys()
+	require.NoError(t, err)
+	scheme := zcncrypto.NewSignatureScheme("ed25519")
+	require.NoError(t, scheme.SetPrivateKey(w.Keys[0].PrivateKey))
+	return w, scheme
+}
+
+func TestEnvelope(t *testing.T) {
+	w, scheme := newEd25519Signer(t)
+	req := UnsignedTxn{
+		ClientID:        w.ClientID,
+		PublicKey:       w.ClientKey,
+		ChainID:         "chain",
+		SignatureScheme: "ed25519",
+		ToClientID:      "to",
+		Value:           10,
+		TransactionType: TxnTypeSend,
+		Nonce:           7,
+		Fee:             1,
+	}
+
+	t.Run("nonce is required", func(t *testing.T) {
+		r := req
+		r.Nonce = 0
+		_, err := BuildUnsigned(r, nil)
+		require.ErrorIs(t, err, ErrInvalidNonce)
+	})
+
+	t.Run("build, sign and verify", func(t *testing.T) {
+		env, err := BuildUnsigned(req, nil)
+		require.NoError(t, err)
+		require.False(t, env.IsSigned())
+		require.ErrorIs(t, env.VerifySignature(), ErrNotSigned)
+
+		data, err := env.Marshal()
+		require.NoError(t, err)
+
+		offline, err := UnmarshalEnvelope(data)
+		require.NoError(t, err)
+		require.NoError(t, offline.Sign(scheme))
+		signed, err := offline.Marshal()
+		require.NoError(t, err)
+
+		online, err := UnmarshalEnvelope(signed)
+		require.NoError(t, err)
+		require.True(t, online.IsSigned())
+		require.NoError(t, online.VerifySignature())
+		require.Equal(t, env.Transaction.Hash, online.Transaction.Hash)
+		require.EqualValues(t, 7, online.Transaction.TransactionNonce)
+	})
+
+	t.Run("tampered envelope", func(t *testing.T) {
+		env, err := BuildUnsigned(req, nil)
+		require.NoError(t, err)
+		env.Transaction.Value = 1000
+		data, err := env.Marshal()
+		require.NoError(t, err)
+
+		_, err = UnmarshalEnvelope(data)
+		require.True(t, errors.Is(err, ErrInvalidEnvelope))
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, other := newEd25519Signer(t)
+		env, err := BuildUnsigned(req, nil)
+		require.NoError(t, err)
+		require.Error(t, env.Sign(other))
+		require.False(t, env.IsSigned())
+	})
+}
+
+func TestEnvelopeSignWith(t *testing.T) {
+	w, _ := newEd25519Signer(t)
+	env, err := BuildUnsigned(UnsignedTxn{
+		ClientID:        w.ClientID,
+		PublicKey:       w.ClientKey,
+		ChainID:         "chain",
+		SignatureScheme: "ed25519",
+		ToClientID:      "to",
+		TransactionType: TxnTypeSend,
+		Nonce:           1,
+	}, nil)
+	require.NoError(t, err)
+
+	other, err := zcncrypto.NewSignatureScheme("ed25519").GenerateKeys()
+	require.NoError(t, err)
+	s, err := signer.NewLocalSigner("ed25519", other)
+	require.NoError(t, err)
+	require.Error(t, env.SignWith(s))
+	require.False(t, env.IsSigned())
+
+	s, err = signer.NewLocalSigner("ed25519", w)
+	require.NoError(t, err)
+	require.NoError(t, env.SignWith(s))
+	require.NoError(t, env.VerifySignature())
+}
@@ -0,0 +1,313 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/common"
+)
+
+// FeeStrategy computes the fee of a transaction.
+type FeeStrategy interface {
+	Fee(ctx context.Context, txn *Transaction) (uint64, error)
+}
+
+// FeeStrategyFunc adapts a function to a FeeStrategy.
+type FeeStrategyFunc func(ctx context.Context, txn *Transaction) (uint64, error)
+
+// Fee implements FeeStrategy.
+func (f FeeStrategyFunc) Fee(ctx context.Context, txn *Transaction) (uint64, error) {
+	return f(ctx, txn)
+}
+
+// FixedFee returns a strategy always using fee.
+func FixedFee(fee uint64) FeeStrategy {
+	return FeeStrategyFunc(func(ctx context.Context, txn *Transaction) (uint64, error) {
+		return fee, nil
+	})
+}
+
+// TableFee returns a strategy using the fees table of the miners, like
+// EstimateFee.
+func TableFee(miners []string) FeeStrategy {
+	return FeeStrategyFunc(func(ctx context.Context, txn *Transaction) (uint64, error) {
+		return EstimateFee(txn, miners, 0.2)
+	})
+}
+
+// FeeStats are the fees paid by the recent transactions.
+type FeeStats struct {
+	Min  uint64
+	Mean uint64
+	Max  uint64
+}
+
+// PercentileFee returns a strategy using the given percentile, between 0 and
+// 1, of the fees of the recent transactions returned by stats. The
+// distribution is approximated as linear between min and mean, and between
+// mean and max.
+func PercentileFee(stats func(ctx context.Context) (*FeeStats, error), percentile float64) FeeStrategy {
+	return FeeStrategyFunc(func(ctx context.Context, txn *Transaction) (uint64, error) {
+		s, err := stats(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return s.percentile(percentile), nil
+	})
+}
+
+func (s *FeeStats) percentile(p float64) uint64 {
+	p = math.Max(0, math.Min(1, p))
+	if p <= 0.5 {
+		return s.Min + uint64(float64(s.Mean-s.Min)*p/0.5)
+	}
+	return s.Mean + uint64(float64(s.Max-s.Mean)*(p-0.5)/0.5)
+}
+
+// MaxFee caps the fee of strategy to max.
+func MaxFee(strategy FeeStrategy, max uint64) FeeStrategy {
+	return FeeStrategyFunc(func(ctx context.Context, txn *Transaction) (uint64, error) {
+		fee, err := strategy.Fee(ctx, txn)
+		if err != nil {
+			return 0, err
+		}
+		if fee > max {
+			fee = max
+		}
+		return fee, nil
+	})
+}
+
+const (
+	// DefaultFeeBumpMultiplier is the factor applied to the fee of a stuck
+	// transaction.
+	DefaultFeeBumpMultiplier = 1.25
+	// DefaultFeeBumpAttempts is the number of versions of a transaction sent by
+	// a FeeBumper.
+	DefaultFeeBumpAttempts = 3
+)
+
+// FeeAttempt is a version of a transaction sent by a FeeBumper.
+type FeeAttempt struct {
+	Hash string `json:"hash"`
+	Fee  uint64 `json:"fee"`
+}
+
+// FeeBumpResult is the outcome of FeeBumper.Submit.
+type FeeBumpResult struct {
+	// Attempts are the versions of the transaction sent, in order.
+	Attempts []FeeAttempt
+	// Confirmed is the index in Attempts of the version confirmed, -1 if none.
+	Confirmed int
+	// Transaction is the confirmed transaction.
+	Transaction *Transaction
+}
+
+// FeeBumper sends a transaction and, when it is not confirmed in time,
+// replaces it with a copy with the same nonce and a higher fee. All the
+// versions sent are verified, as any of them can be the one confirmed.
+type FeeBumper struct {
+	Miners   []string
+	Sharders []string
+	Sign     SignFunc
+
+	// Strategy computes the fee of the first version if the transaction has
+	// none, TableFee of Miners if nil.
+	Strategy FeeStrategy
+	// Multiplier is the factor applied to the fee of each new version.
+	Multiplier float64
+	// MaxFee caps the bumped fees, zero means no cap.
+	MaxFee uint64
+	// Attempts is the number of versions sent.
+	Attempts       int
+	VerifyInterval time.Duration
+	VerifyAttempts int
+
+	send   func(txn *Transaction, miners []string) error
+	verify func(hash string, sharders []string) (*Transaction, error)
+}
+
+// NewFeeBumper creates a FeeBumper with the default settings.
+func NewFeeBumper(miners, sharders []string, sign SignFunc) *FeeBumper {
+	return &FeeBumper{
+		Miners:         miners,
+		Sharders:       sharders,
+		Sign:           sign,
+		Multiplier:     DefaultFeeBumpMultiplier,
+		Attempts:       DefaultFeeBumpAttempts,
+		VerifyInterval: DefaultPipelineVerifyInterval,
+		VerifyAttempts: DefaultPipelineVerifyAttempts,
+	}
+}
+
+// Submit sends txn, setting its nonce and fee if needed, and bumps its fee
+// until a version is confirmed or Attempts versions were sent.
+func (b *FeeBumper) Submit(ctx context.Context, txn *Transaction) (*FeeBumpResult, error) {
+	if txn.TransactionFee == 0 {
+		strategy := b.Strategy
+		if strategy == nil {
+			strategy = TableFee(b.Miners)
+		}
+		fee, err := strategy.Fee(ctx, txn)
+		if err != nil {
+			return &FeeBumpResult{Confirmed: -1}, err
+		}
+		txn.TransactionFee = fee
+	}
+	if txn.TransactionNonce == 0 {
+		nonce, err := Cache.ReserveNextNonce(txn.ClientID)
+		if err != nil {
+			return &FeeBumpResult{Confirmed: -1}, err
+		}
+		txn.TransactionNonce = nonce
+	}
+	return b.submit(ctx, txn, false)
+}
+
+// Resubmit bumps the fee of txn, already signed and sent but not confirmed
+// in time. txn counts as the first of the Attempts versions and is still
+// verified with the new ones.
+func (b *FeeBumper) Resubmit(ctx context.Context, txn *Transaction) (*FeeBumpResult, error) {
+	return b.submit(ctx, txn, true)
+}
+
+func (b *FeeBumper) submit(ctx context.Context, txn *Transaction, sent bool) (*FeeBumpResult, error) {
+	result := &FeeBumpResult{Confirmed: -1}
+
+	attempts := b.Attempts
+	if attempts <= 0 {
+		attempts = DefaultFeeBumpAttempts
+	}
+
+	var (
+		versions []*Transaction
+		err      error
+	)
+	cur := txn
+	for i := 0; i < attempts; i++ {
+		if i == 0 && sent {
+			versions = append(versions, cur)
+			result.Attempts = append(result.Attempts, FeeAttempt{Hash: cur.Hash, Fee: cur.TransactionFee})
+			continue
+		}
+		if i > 0 {
+			cur = b.bump(cur)
+		}
+		if err = cur.ComputeHashAndSign(b.Sign); err != nil {
+			if i == 0 {
+				Cache.Release(txn.ClientID, txn.TransactionNonce)
+				return result, err
+			}
+			break
+		}
+		if err = b.sendTxn(cur); err != nil {
+			if i == 0 {
+				Cache.Release(txn.ClientID, txn.TransactionNonce)
+				return result, err
+			}
+			// the previous versions may still be confirmed
+			cur = versions[len(versions)-1]
+			continue
+		}
+		versions = append(versions, cur)
+		result.Attempts = append(result.Attempts, FeeAttempt{Hash: cur.Hash, Fee: cur.TransactionFee})
+
+		idx, confirmed, verr := b.waitConfirmation(ctx, versions)
+		if confirmed != nil {
+			Cache.Confirm(txn.ClientID, txn.TransactionNonce)
+			result.Confirmed = idx
+			result.Transaction = confirmed
+			if confirmed.Status == TxnFail || confirmed.Status == TxnChargeableError {
+				return result, errors.New("transaction_failed", confirmed.TransactionOutput)
+			}
+			return result, nil
+		}
+		err = verr
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	Cache.Evict(txn.ClientID)
+	msg := fmt.Sprintf("transaction with nonce %d not confirmed after %d attempts", txn.TransactionNonce, len(versions))
+	if err == nil {
+		return result, errors.New("fee_bump", msg)
+	}
+	return result, errors.Wrap(err, "fee_bump: "+msg)
+}
+
+// bump returns a copy of txn with a higher fee. Its creation date is updated,
+// so that its hash differs from the previous version, the fee not being part
+// of the hash.
+func (b *FeeBumper) bump(txn *Transaction) *Transaction {
+	next := *txn
+	next.Hash = ""
+	next.Signature = ""
+
+	multiplier := b.Multiplier
+	if multiplier <= 1 {
+		multiplier = DefaultFeeBumpMultiplier
+	}
+	fee := uint64(math.Ceil(float64(txn.TransactionFee) * multiplier))
+	if fee == txn.TransactionFee {
+		fee++
+	}
+	if b.MaxFee > 0 && fee > b.MaxFee {
+		fee = b.MaxFee
+	}
+	next.TransactionFee = fee
+
+	next.CreationDate = int64(common.Now())
+	if next.CreationDate <= txn.CreationDate {
+		next.CreationDate = txn.CreationDate + 1
+	}
+	return &next
+}
+
+// waitConfirmation verifies all the versions until one is confirmed or
+// VerifyAttempts are done.
+func (b *FeeBumper) waitConfirmation(ctx context.Context, versions []*Transaction) (int, *Transaction, error) {
+	interval := b.VerifyInterval
+	if interval <= 0 {
+		interval = DefaultPipelineVerifyInterval
+	}
+	attempts := b.VerifyAttempts
+	if attempts <= 0 {
+		attempts = DefaultPipelineVerifyAttempts
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		select {
+		case <-ctx.Done():
+			return -1, nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		for idx, v := range versions {
+			var txn *Transaction
+			txn, err = b.verifyTxn(v.Hash)
+			if err == nil && txn != nil {
+				return idx, txn, nil
+			}
+		}
+	}
+	return -1, nil, err
+}
+
+func (b *FeeBumper) sendTxn(txn *Transaction) error {
+	if b.send != nil {
+		return b.send(txn, b.Miners)
+	}
+	return sendTransaction(txn, b.Miners)
+}
+
+func (b *FeeBumper) verifyTxn(hash string) (*Transaction, error) {
+	if b.verify != nil {
+		return b.verify(hash, b.Sharders)
+	}
+	return VerifyTransaction(hash, b.Sharders)
+}
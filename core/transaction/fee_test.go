@@ -0,0 +1,118 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeStrategies(t *testing.T) {
+	ctx := context.Background()
+	txn := &Transaction{}
+
+	fee, err := FixedFee(100).Fee(ctx, txn)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, fee)
+
+	stats := func(ctx context.Context) (*FeeStats, error) {
+		return &FeeStats{Min: 10, Mean: 20, Max: 60}, nil
+	}
+	for p, want := range map[float64]uint64{0: 10, 0.25: 15, 0.5: 20, 0.75: 40, 1: 60, 2: 60} {
+		fee, err = PercentileFee(stats, p).Fee(ctx, txn)
+		require.NoError(t, err)
+		require.Equal(t, want, fee, "percentile %v", p)
+	}
+
+	fee, err = MaxFee(PercentileFee(stats, 1), 30).Fee(ctx, txn)
+	require.NoError(t, err)
+	require.EqualValues(t, 30, fee)
+}
+
+func TestFeeBumper(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent []*Transaction
+	)
+	b := NewFeeBumper([]string{"miner"}, []string{"sharder"}, func(msg string) (string, error) {
+		return "sig:" + msg, nil
+	})
+	b.Strategy = FixedFee(100)
+	b.MaxFee = 140
+	b.VerifyInterval = time.Millisecond
+	b.VerifyAttempts = 2
+	b.send = func(txn *Transaction, miners []string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, txn)
+		return nil
+	}
+	// only the third version is picked up by the miners
+	b.verify = func(hash string, sharders []string) (*Transaction, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(sent) == 3 && sent[2].Hash == hash {
+			return &Transaction{Hash: hash, Status: TxnSuccess}, nil
+		}
+		return nil, ErrNoTxnDetail
+	}
+
+	txn := NewTransactionEntity("fee_bump_client", "chain", "pk", 5)
+	res, err := b.Submit(context.Background(), txn)
+	require.NoError(t, err)
+	require.Equal(t, 2, res.Confirmed)
+	require.Len(t, res.Attempts, 3)
+	require.EqualValues(t, 100, res.Attempts[0].Fee)
+	require.EqualValues(t, 125, res.Attempts[1].Fee)
+	require.EqualValues(t, 140, res.Attempts[2].Fee, "the fee should be capped")
+	for _, v := range sent {
+		require.EqualValues(t, 5, v.TransactionNonce)
+	}
+	require.NotEqual(t, res.Attempts[0].Hash, res.Attempts[1].Hash)
+	require.Equal(t, sent[2].Hash, res.Transaction.Hash)
+}
+
+func TestFeeBumperResubmit(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent []*Transaction
+	)
+	b := NewFeeBumper([]string{"miner"}, []string{"sharder"}, func(msg string) (string, error) {
+		return "sig:" + msg, nil
+	})
+	b.VerifyInterval = time.Millisecond
+	b.VerifyAttempts = 2
+	b.send = func(txn *Transaction, miners []string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, txn)
+		return nil
+	}
+
+	txn := NewTransactionEntity("fee_resubmit_client", "chain", "pk", 7)
+	txn.TransactionFee = 100
+	require.NoError(t, txn.ComputeHashAndSign(b.Sign))
+	stuck := txn.Hash
+
+	// the stuck version gets confirmed once the first bump is sent
+	b.verify = func(hash string, sharders []string) (*Transaction, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(sent) > 0 && hash == stuck {
+			return &Transaction{Hash: hash, Status: TxnSuccess}, nil
+		}
+		return nil, ErrNoTxnDetail
+	}
+
+	res, err := b.Resubmit(context.Background(), txn)
+	require.NoError(t, err)
+	require.Len(t, sent, 1, "the stuck version should not be sent again")
+	require.Len(t, res.Attempts, 2)
+	require.Equal(t, stuck, res.Attempts[0].Hash)
+	require.EqualValues(t, 125, res.Attempts[1].Fee)
+	require.EqualValues(t, 7, sent[0].TransactionNonce)
+	require.Equal(t, 0, res.Confirmed)
+	require.Equal(t, stuck, res.Transaction.Hash)
+}
@@ -0,0 +1,361 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultNonceLease is how long a reserved nonce is kept for the transaction
+	// using it. A nonce neither confirmed nor released by then is handed out
+	// again if the chain didn't use it.
+	DefaultNonceLease = 5 * time.Minute
+
+	// DefaultNonceSyncInterval is how often the nonce of the chain is read from
+	// the sharders to detect gaps.
+	DefaultNonceSyncInterval = time.Minute
+)
+
+// ErrNonceConflict the nonce state was updated concurrently too many times
+var ErrNonceConflict = stdErrors.New("[txn] too many concurrent updates of the nonce state")
+
+// NonceManager hands out the nonces of the transactions of a client. It is
+// safe to share between processes through a shared NonceStore.
+type NonceManager interface {
+	// Reserve returns a nonce not used by any other transaction of the client.
+	Reserve(ctx context.Context, clientID string) (int64, error)
+	// Release gives back a reserved nonce whose transaction was not sent, it is
+	// handed out again before any new nonce.
+	Release(ctx context.Context, clientID string, nonce int64) error
+	// Confirm marks the nonce, and all the nonces before it, as used on chain.
+	Confirm(ctx context.Context, clientID string, nonce int64) error
+	// Sync reads the nonce of the client from the chain and fills the gaps:
+	// nonces above it that are neither reserved nor released are handed out
+	// again. It returns the gaps found.
+	Sync(ctx context.Context, clientID string) ([]int64, error)
+}
+
+// NonceState is the state of the nonces of a client kept in a NonceStore.
+type NonceState struct {
+	// Confirmed is the nonce of the last transaction known to be on chain.
+	Confirmed int64 `json:"confirmed"`
+	// Next is the highest nonce handed out.
+	Next int64 `json:"next"`
+	// Reserved maps the nonces handed out to the expiration of their lease in
+	// unix nanoseconds.
+	Reserved map[int64]int64 `json:"reserved,omitempty"`
+	// Free are the released nonces, in ascending order.
+	Free []int64 `json:"free,omitempty"`
+	// Synced is the time of the last sync with the chain in unix nanoseconds.
+	Synced int64 `json:"synced"`
+}
+
+// NonceStore stores the NonceState of the clients.
+type NonceStore interface {
+	// Load returns the state of the client, a zero state if there is none.
+	Load(ctx context.Context, clientID string) (*NonceState, error)
+	// Update runs fn on the state of the client and saves it, atomically for all
+	// the users of the store.
+	Update(ctx context.Context, clientID string, fn func(s *NonceState) error) error
+}
+
+// NonceKV is a key-value store, like redis or etcd, a NonceStore can be built
+// on with NewKVNonceStore.
+type NonceKV interface {
+	// Get returns the value of key, nil if it doesn't exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// CompareAndSwap sets key to value if its current value is old, nil old
+	// meaning that key doesn't exist. It returns false if the value differs.
+	CompareAndSwap(ctx context.Context, key string, old, value []byte) (bool, error)
+}
+
+// StoreNonceManager is the NonceManager keeping its state in a NonceStore.
+type StoreNonceManager struct {
+	Store NonceStore
+	// LeaseTimeout is how long a reservation is kept, DefaultNonceLease if zero.
+	LeaseTimeout time.Duration
+	// SyncInterval is how often Reserve syncs with the chain,
+	// DefaultNonceSyncInterval if zero.
+	SyncInterval time.Duration
+	// FetchNonce returns the nonce of the client on chain, it reads the balance
+	// of the client from Sharders if nil.
+	FetchNonce func(ctx context.Context, clientID string) (int64, error)
+	Sharders   []string
+}
+
+// NewNonceManager creates a StoreNonceManager reading the nonces on chain from
+// the sharders.
+func NewNonceManager(store NonceStore, sharders []string) *StoreNonceManager {
+	return &StoreNonceManager{
+		Store:        store,
+		LeaseTimeout: DefaultNonceLease,
+		SyncInterval: DefaultNonceSyncInterval,
+		Sharders:     sharders,
+	}
+}
+
+// Reserve implements NonceManager. The state is synced with the chain first if
+// it is older than SyncInterval.
+func (m *StoreNonceManager) Reserve(ctx context.Context, clientID string) (int64, error) {
+	s, err := m.Store.Load(ctx, clientID)
+	if err != nil {
+		return 0, err
+	}
+	if time.Since(time.Unix(0, s.Synced)) > m.syncInterval() {
+		// a stale state is still usable, only a never synced one is not
+		if _, err := m.Sync(ctx, clientID); err != nil && s.Synced == 0 {
+			return 0, err
+		}
+	}
+
+	var nonce int64
+	err = m.Store.Update(ctx, clientID, func(s *NonceState) error {
+		now := time.Now()
+		s.expireLeases(now)
+		nonce = s.reserve(now.Add(m.leaseTimeout()))
+		return nil
+	})
+	return nonce, err
+}
+
+// Release implements NonceManager.
+func (m *StoreNonceManager) Release(ctx context.Context, clientID string, nonce int64) error {
+	return m.Store.Update(ctx, clientID, func(s *NonceState) error {
+		if _, ok := s.Reserved[nonce]; !ok || nonce <= s.Confirmed {
+			return nil
+		}
+		delete(s.Reserved, nonce)
+		s.addFree(nonce)
+		return nil
+	})
+}
+
+// Confirm implements NonceManager.
+func (m *StoreNonceManager) Confirm(ctx context.Context, clientID string, nonce int64) error {
+	return m.Store.Update(ctx, clientID, func(s *NonceState) error {
+		s.confirm(nonce)
+		return nil
+	})
+}
+
+// Sync implements NonceManager.
+func (m *StoreNonceManager) Sync(ctx context.Context, clientID string) ([]int64, error) {
+	confirmed, err := m.fetchNonce(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	var gaps []int64
+	err = m.Store.Update(ctx, clientID, func(s *NonceState) error {
+		now := time.Now()
+		s.Synced = now.UnixNano()
+		s.confirm(confirmed)
+		s.expireLeases(now)
+		gaps = s.fillGaps()
+		return nil
+	})
+	return gaps, err
+}
+
+func (m *StoreNonceManager) fetchNonce(ctx context.Context, clientID string) (int64, error) {
+	if m.FetchNonce != nil {
+		return m.FetchNonce(ctx, clientID)
+	}
+	nonce, _, err := GetBalanceFieldFromSharders(clientID, "nonce", m.Sharders)
+	return nonce, err
+}
+
+func (m *StoreNonceManager) leaseTimeout() time.Duration {
+	if m.LeaseTimeout > 0 {
+		return m.LeaseTimeout
+	}
+	return DefaultNonceLease
+}
+
+func (m *StoreNonceManager) syncInterval() time.Duration {
+	if m.SyncInterval > 0 {
+		return m.SyncInterval
+	}
+	return DefaultNonceSyncInterval
+}
+
+func (s *NonceState) reserve(lease time.Time) int64 {
+	var nonce int64
+	if len(s.Free) > 0 {
+		nonce = s.Free[0]
+		s.Free = s.Free[1:]
+	} else {
+		if s.Next < s.Confirmed {
+			s.Next = s.Confirmed
+		}
+		s.Next++
+		nonce = s.Next
+	}
+	if s.Reserved == nil {
+		s.Reserved = make(map[int64]int64)
+	}
+	s.Reserved[nonce] = lease.UnixNano()
+	return nonce
+}
+
+func (s *NonceState) confirm(nonce int64) {
+	if nonce <= s.Confirmed {
+		return
+	}
+	s.Confirmed = nonce
+	if s.Next < nonce {
+		s.Next = nonce
+	}
+	for n := range s.Reserved {
+		if n <= nonce {
+			delete(s.Reserved, n)
+		}
+	}
+	free := s.Free[:0]
+	for _, n := range s.Free {
+		if n > nonce {
+			free = append(free, n)
+		}
+	}
+	s.Free = free
+}
+
+func (s *NonceState) expireLeases(now time.Time) {
+	for n, expires := range s.Reserved {
+		if expires < now.UnixNano() {
+			delete(s.Reserved, n)
+			s.addFree(n)
+		}
+	}
+}
+
+// fillGaps frees the nonces above Confirmed that nobody holds.
+func (s *NonceState) fillGaps() []int64 {
+	free := make(map[int64]bool, len(s.Free))
+	for _, n := range s.Free {
+		free[n] = true
+	}
+	var gaps []int64
+	for n := s.Confirmed + 1; n <= s.Next; n++ {
+		if _, ok := s.Reserved[n]; ok || free[n] {
+			continue
+		}
+		gaps = append(gaps, n)
+		s.addFree(n)
+	}
+	return gaps
+}
+
+func (s *NonceState) addFree(nonce int64) {
+	i := sort.Search(len(s.Free), func(i int) bool { return s.Free[i] >= nonce })
+	if i < len(s.Free) && s.Free[i] == nonce {
+		return
+	}
+	s.Free = append(s.Free, 0)
+	copy(s.Free[i+1:], s.Free[i:])
+	s.Free[i] = nonce
+}
+
+// MemoryNonceStore is a NonceStore for a single process.
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	states map[string]*NonceState
+}
+
+// NewMemoryNonceStore creates a MemoryNonceStore.
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{states: make(map[string]*NonceState)}
+}
+
+// Load implements NonceStore.
+func (ms *MemoryNonceStore) Load(ctx context.Context, clientID string) (*NonceState, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	s := &NonceState{}
+	if cur, ok := ms.states[clientID]; ok {
+		// copy through json so that the caller can't change the stored state
+		buf, _ := json.Marshal(cur)
+		_ = json.Unmarshal(buf, s)
+	}
+	return s, nil
+}
+
+// Update implements NonceStore.
+func (ms *MemoryNonceStore) Update(ctx context.Context, clientID string, fn func(s *NonceState) error) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	s, ok := ms.states[clientID]
+	if !ok {
+		s = &NonceState{}
+	}
+	if err := fn(s); err != nil {
+		return err
+	}
+	ms.states[clientID] = s
+	return nil
+}
+
+// maxKVNonceRetries is how many times a KV update is retried on conflict.
+const maxKVNonceRetries = 100
+
+type kvNonceStore struct {
+	kv     NonceKV
+	prefix string
+}
+
+// NewKVNonceStore creates a NonceStore keeping the state of the client under
+// prefix+clientID in kv. Updates use optimistic concurrency, so kv can be
+// shared by many processes.
+func NewKVNonceStore(kv NonceKV, prefix string) NonceStore {
+	return &kvNonceStore{kv: kv, prefix: prefix}
+}
+
+func (ks *kvNonceStore) Load(ctx context.Context, clientID string) (*NonceState, error) {
+	_, s, err := ks.load(ctx, clientID)
+	return s, err
+}
+
+func (ks *kvNonceStore) Update(ctx context.Context, clientID string, fn func(s *NonceState) error) error {
+	for i := 0; i < maxKVNonceRetries; i++ {
+		old, s, err := ks.load(ctx, clientID)
+		if err != nil {
+			return err
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+		buf, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		ok, err := ks.kv.CompareAndSwap(ctx, ks.prefix+clientID, old, buf)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return ErrNonceConflict
+}
+
+func (ks *kvNonceStore) load(ctx context.Context, clientID string) ([]byte, *NonceState, error) {
+	buf, err := ks.kv.Get(ctx, ks.prefix+clientID)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := &NonceState{}
+	if buf != nil {
+		if err := json.Unmarshal(buf, s); err != nil {
+			return nil, nil, err
+		}
+	}
+	return buf, s, nil
+}
@@ -0,0 +1,114 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/0chain/errors"
+)
+
+var clientIDPattern = regexp.MustCompile(`^[0-9a-zA-Z_-]+$`)
+
+// FileNonceStore is a NonceStore keeping the state of each client in a json file
+// of a directory. Updates hold an exclusive lock on the file, so the processes
+// using the same directory share the nonces.
+type FileNonceStore struct {
+	dir string
+}
+
+// NewFileNonceStore creates a FileNonceStore in dir.
+func NewFileNonceStore(dir string) (*FileNonceStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileNonceStore{dir: dir}, nil
+}
+
+// Load implements NonceStore.
+func (fs *FileNonceStore) Load(ctx context.Context, clientID string) (*NonceState, error) {
+	var state *NonceState
+	err := fs.withLock(ctx, clientID, func() error {
+		s, err := fs.read(clientID)
+		state = s
+		return err
+	})
+	return state, err
+}
+
+// Update implements NonceStore.
+func (fs *FileNonceStore) Update(ctx context.Context, clientID string, fn func(s *NonceState) error) error {
+	return fs.withLock(ctx, clientID, func() error {
+		s, err := fs.read(clientID)
+		if err != nil {
+			return err
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+		return fs.write(clientID, s)
+	})
+}
+
+func (fs *FileNonceStore) withLock(ctx context.Context, clientID string, fn func() error) error {
+	if !clientIDPattern.MatchString(clientID) {
+		return errors.New("nonce_store", "invalid client id: "+clientID)
+	}
+	f, err := os.OpenFile(filepath.Join(fs.dir, clientID+".lock"), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			return err
+		}
+		if locked {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	defer unlockFile(f) //nolint: errcheck
+
+	return fn()
+}
+
+func (fs *FileNonceStore) read(clientID string) (*NonceState, error) {
+	s := &NonceState{}
+	buf, err := os.ReadFile(filepath.Join(fs.dir, clientID+".json"))
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(buf, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (fs *FileNonceStore) write(clientID string, s *NonceState) error {
+	buf, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(fs.dir, clientID+".json")
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
@@ -0,0 +1,181 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryKV struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (kv *memoryKV) Get(ctx context.Context, key string) ([]byte, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	return kv.values[key], nil
+}
+
+func (kv *memoryKV) CompareAndSwap(ctx context.Context, key string, old, value []byte) (bool, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if !bytes.Equal(kv.values[key], old) {
+		return false, nil
+	}
+	kv.values[key] = value
+	return true, nil
+}
+
+func newTestNonceManager(store NonceStore, chainNonce *int64) *StoreNonceManager {
+	m := NewNonceManager(store, nil)
+	m.FetchNonce = func(ctx context.Context, clientID string) (int64, error) {
+		return *chainNonce, nil
+	}
+	return m
+}
+
+func TestNonceManager(t *testing.T) {
+	fileStore, err := NewFileNonceStore(t.TempDir())
+	require.NoError(t, err)
+	stores := map[string]NonceStore{
+		"memory": NewMemoryNonceStore(),
+		"kv":     NewKVNonceStore(&memoryKV{values: make(map[string][]byte)}, "nonce:"),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			chain := int64(10)
+			m := newTestNonceManager(store, &chain)
+
+			n, err := m.Reserve(ctx, "client")
+			require.NoError(t, err)
+			require.EqualValues(t, 11, n)
+			n, err = m.Reserve(ctx, "client")
+			require.NoError(t, err)
+			require.EqualValues(t, 12, n)
+
+			// a released nonce is handed out first
+			require.NoError(t, m.Release(ctx, "client", 11))
+			n, err = m.Reserve(ctx, "client")
+			require.NoError(t, err)
+			require.EqualValues(t, 11, n)
+
+			require.NoError(t, m.Confirm(ctx, "client", 12))
+			n, err = m.Reserve(ctx, "client")
+			require.NoError(t, err)
+			require.EqualValues(t, 13, n)
+
+			// nonce 13 was lost by a crashed process, the chain is at 12
+			require.NoError(t, store.Update(ctx, "client", func(s *NonceState) error {
+				delete(s.Reserved, 13)
+				return nil
+			}))
+			chain = 12
+			gaps, err := m.Sync(ctx, "client")
+			require.NoError(t, err)
+			require.Equal(t, []int64{13}, gaps)
+			n, err = m.Reserve(ctx, "client")
+			require.NoError(t, err)
+			require.EqualValues(t, 13, n)
+
+			// transactions sent by another wallet instance
+			chain = 20
+			_, err = m.Sync(ctx, "client")
+			require.NoError(t, err)
+			n, err = m.Reserve(ctx, "client")
+			require.NoError(t, err)
+			require.EqualValues(t, 21, n)
+		})
+	}
+}
+
+func TestNonceManagerLease(t *testing.T) {
+	ctx := context.Background()
+	chain := int64(0)
+	m := newTestNonceManager(NewMemoryNonceStore(), &chain)
+	m.LeaseTimeout = time.Millisecond
+
+	n, err := m.Reserve(ctx, "client")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+
+	time.Sleep(5 * time.Millisecond)
+	n, err = m.Reserve(ctx, "client")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n, "an expired reservation should be handed out again")
+}
+
+func TestFileNonceStoreConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	chain := int64(0)
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		seen  = make(map[int64]bool)
+		errsC = make(chan error, 40)
+	)
+	for i := 0; i < 4; i++ {
+		// one store per worker, like separate processes sharing the directory
+		store, err := NewFileNonceStore(dir)
+		require.NoError(t, err)
+		m := newTestNonceManager(store, &chain)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				n, err := m.Reserve(ctx, "client")
+				if err != nil {
+					errsC <- err
+					return
+				}
+				mu.Lock()
+				if seen[n] {
+					errsC <- ErrNonceConflict
+				}
+				seen[n] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errsC)
+	for err := range errsC {
+		require.NoError(t, err)
+	}
+	require.Len(t, seen, 40)
+}
+
+func TestNonceCacheManagerError(t *testing.T) {
+	chain := int64(0)
+	m := newTestNonceManager(NewMemoryNonceStore(), &chain)
+	m.FetchNonce = func(ctx context.Context, clientID string) (int64, error) {
+		return 0, context.DeadlineExceeded
+	}
+	SetNonceManager(m)
+	defer SetNonceManager(nil)
+
+	// the state was never synced with the chain, no nonce can be handed out
+	_, err := Cache.ReserveNextNonce("unsynced_client")
+	require.Error(t, err)
+	require.Zero(t, Cache.GetNextNonce("unsynced_client"))
+
+	b := NewFeeBumper([]string{"miner"}, []string{"sharder"}, func(msg string) (string, error) {
+		return "sig:" + msg, nil
+	})
+	b.Strategy = FixedFee(100)
+	b.send = func(txn *Transaction, miners []string) error {
+		t.Error("a transaction without nonce was sent")
+		return nil
+	}
+	_, err = b.Submit(context.Background(), NewTransactionEntity("unsynced_client", "chain", "pk", 0))
+	require.Error(t, err)
+}
@@ -0,0 +1,234 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0chain/errors"
+)
+
+const (
+	// DefaultPipelineConcurrency is the number of transactions a TxnPipeline
+	// submits and verifies at once.
+	DefaultPipelineConcurrency = 10
+	// DefaultPipelineVerifyInterval is the time between two verifications of a
+	// transaction.
+	DefaultPipelineVerifyInterval = time.Second
+	// DefaultPipelineVerifyAttempts is the number of verifications before a
+	// transaction is considered dropped and resubmitted.
+	DefaultPipelineVerifyAttempts = 15
+	// DefaultPipelineResubmits is the number of times a dropped transaction is
+	// resubmitted.
+	DefaultPipelineResubmits = 2
+)
+
+// PipelineResult is the outcome of a transaction of a TxnPipeline.
+type PipelineResult struct {
+	// Transaction is the confirmed transaction, or the submitted one on error.
+	Transaction *Transaction
+	Err         error
+}
+
+// TxnPipeline submits many transactions at once: it assigns them sequential
+// nonces, sends them to the miners without waiting for the previous ones, then
+// verifies them in parallel and resubmits those dropped by the miners.
+//
+//	p := NewTxnPipeline(miners, sharders, client.Sign)
+//	results := p.Submit(ctx, txns...)
+//	for _, r := range results {
+//		res := <-r
+//	}
+type TxnPipeline struct {
+	Miners   []string
+	Sharders []string
+	Sign     SignFunc
+
+	// Concurrency bounds the transactions submitted and verified at once.
+	Concurrency    int
+	VerifyInterval time.Duration
+	VerifyAttempts int
+	Resubmits      int
+
+	send   func(txn *Transaction, miners []string) error
+	verify func(hash string, sharders []string) (*Transaction, error)
+}
+
+// NewTxnPipeline creates a TxnPipeline with the default settings.
+func NewTxnPipeline(miners, sharders []string, sign SignFunc) *TxnPipeline {
+	return &TxnPipeline{
+		Miners:         miners,
+		Sharders:       sharders,
+		Sign:           sign,
+		Concurrency:    DefaultPipelineConcurrency,
+		VerifyInterval: DefaultPipelineVerifyInterval,
+		VerifyAttempts: DefaultPipelineVerifyAttempts,
+		Resubmits:      DefaultPipelineResubmits,
+	}
+}
+
+// Submit submits the transactions and returns a channel per transaction, in
+// the same order, receiving its result. Transactions without a nonce get the
+// next ones of Cache, in order; the fee of smart contract transactions is
+// estimated if not set. Submit returns once the nonces are assigned.
+func (p *TxnPipeline) Submit(ctx context.Context, txns ...*Transaction) []<-chan *PipelineResult {
+	results := make([]<-chan *PipelineResult, len(txns))
+	prepared := make([]*Transaction, 0, len(txns))
+	resultCs := make([]chan *PipelineResult, 0, len(txns))
+
+	for i, txn := range txns {
+		resultC := make(chan *PipelineResult, 1)
+		results[i] = resultC
+		if err := p.prepare(txn); err != nil {
+			resultC <- &PipelineResult{Transaction: txn, Err: err}
+			close(resultC)
+			continue
+		}
+		prepared = append(prepared, txn)
+		resultCs = append(resultCs, resultC)
+	}
+
+	// the slots are taken in nonce order, so that a transaction never waits
+	// for a slot held by one that can't be confirmed before it
+	go func() {
+		sem := make(chan struct{}, p.concurrency())
+		for i, txn := range prepared {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				Cache.Release(txn.ClientID, txn.TransactionNonce)
+				resultCs[i] <- &PipelineResult{Transaction: txn, Err: ctx.Err()}
+				close(resultCs[i])
+				continue
+			}
+			go func(txn *Transaction, resultC chan *PipelineResult) {
+				defer func() { <-sem }()
+				resultC <- p.process(ctx, txn)
+				close(resultC)
+			}(txn, resultCs[i])
+		}
+	}()
+
+	return results
+}
+
+// prepare sets the fee and the nonce of the transaction and signs it.
+func (p *TxnPipeline) prepare(txn *Transaction) error {
+	if txn.TransactionFee == 0 && txn.TransactionType == TxnTypeSmartContract {
+		fee, err := EstimateFee(txn, p.Miners, 0.2)
+		if err != nil {
+			return errors.Wrap(err, "pipeline: failed to estimate fee")
+		}
+		txn.TransactionFee = fee
+	}
+	if txn.TransactionNonce == 0 {
+		nonce, err := Cache.ReserveNextNonce(txn.ClientID)
+		if err != nil {
+			return errors.Wrap(err, "pipeline: failed to get the nonce of "+txn.ClientID)
+		}
+		txn.TransactionNonce = nonce
+	}
+	if txn.Signature == "" {
+		if err := txn.ComputeHashAndSign(p.Sign); err != nil {
+			Cache.Release(txn.ClientID, txn.TransactionNonce)
+			return err
+		}
+	}
+	return nil
+}
+
+// process sends the transaction and verifies it, resubmitting it if it's not
+// confirmed after VerifyAttempts.
+func (p *TxnPipeline) process(ctx context.Context, txn *Transaction) *PipelineResult {
+	var err error
+	for round := 0; round <= p.resubmits(); round++ {
+		if err = p.sendTxn(txn); err != nil {
+			if round == 0 {
+				// never accepted by any miner, the nonce is free again
+				Cache.Release(txn.ClientID, txn.TransactionNonce)
+				return &PipelineResult{Transaction: txn, Err: err}
+			}
+			continue
+		}
+
+		var confirmed *Transaction
+		confirmed, err = p.waitConfirmation(ctx, txn.Hash)
+		if confirmed != nil {
+			Cache.Confirm(txn.ClientID, txn.TransactionNonce)
+			if confirmed.Status == TxnFail || confirmed.Status == TxnChargeableError {
+				return &PipelineResult{Transaction: confirmed, Err: errors.New("transaction_failed", confirmed.TransactionOutput)}
+			}
+			return &PipelineResult{Transaction: confirmed}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	Cache.Evict(txn.ClientID)
+	msg := fmt.Sprintf("transaction %s not confirmed", txn.Hash)
+	if err == nil {
+		return &PipelineResult{Transaction: txn, Err: errors.New("pipeline", msg)}
+	}
+	return &PipelineResult{Transaction: txn, Err: errors.Wrap(err, "pipeline: "+msg)}
+}
+
+func (p *TxnPipeline) waitConfirmation(ctx context.Context, hash string) (*Transaction, error) {
+	var err error
+	for i := 0; i < p.verifyAttempts(); i++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(p.verifyInterval()):
+		}
+
+		var txn *Transaction
+		txn, err = p.verifyTxn(hash)
+		if err == nil && txn != nil {
+			return txn, nil
+		}
+	}
+	return nil, err
+}
+
+func (p *TxnPipeline) sendTxn(txn *Transaction) error {
+	if p.send != nil {
+		return p.send(txn, p.Miners)
+	}
+	return sendTransaction(txn, p.Miners)
+}
+
+func (p *TxnPipeline) verifyTxn(hash string) (*Transaction, error) {
+	if p.verify != nil {
+		return p.verify(hash, p.Sharders)
+	}
+	return VerifyTransaction(hash, p.Sharders)
+}
+
+func (p *TxnPipeline) concurrency() int {
+	if p.Concurrency > 0 {
+		return p.Concurrency
+	}
+	return DefaultPipelineConcurrency
+}
+
+func (p *TxnPipeline) verifyInterval() time.Duration {
+	if p.VerifyInterval > 0 {
+		return p.VerifyInterval
+	}
+	return DefaultPipelineVerifyInterval
+}
+
+func (p *TxnPipeline) verifyAttempts() int {
+	if p.VerifyAttempts > 0 {
+		return p.VerifyAttempts
+	}
+	return DefaultPipelineVerifyAttempts
+}
+
+func (p *TxnPipeline) resubmits() int {
+	if p.Resubmits > 0 {
+		return p.Resubmits
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0chain/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxnPipeline(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		sends = make(map[string]int)
+		data  = make(map[string]string)
+	)
+
+	p := NewTxnPipeline([]string{"miner"}, []string{"sharder"}, func(msg string) (string, error) {
+		return "sig:" + msg, nil
+	})
+	p.VerifyInterval = time.Millisecond
+	p.VerifyAttempts = 3
+	p.send = func(txn *Transaction, miners []string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if txn.TransactionData == "unreachable" {
+			return errors.New("transaction_send_error", "no miner")
+		}
+		sends[txn.Hash]++
+		data[txn.Hash] = txn.TransactionData
+		return nil
+	}
+	p.verify = func(hash string, sharders []string) (*Transaction, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		// the miners drop the "dropped" transaction the first time it's sent
+		if sends[hash] == 0 || (data[hash] == "dropped" && sends[hash] < 2) {
+			return nil, ErrNoTxnDetail
+		}
+		return &Transaction{Hash: hash, Status: TxnSuccess}, nil
+	}
+
+	txns := make([]*Transaction, 3)
+	for i := range txns {
+		txns[i] = NewTransactionEntity("pipeline_client", "chain", "pk", 0)
+		txns[i].TransactionType = TxnTypeSend
+	}
+	txns[1].TransactionData = "dropped"
+	txns[2].TransactionData = "unreachable"
+
+	results := p.Submit(context.Background(), txns...)
+	require.Len(t, results, 3)
+	require.Equal(t, txns[0].TransactionNonce+1, txns[1].TransactionNonce)
+	require.Equal(t, txns[1].TransactionNonce+1, txns[2].TransactionNonce)
+
+	r := <-results[0]
+	require.NoError(t, r.Err)
+	require.Equal(t, txns[0].Hash, r.Transaction.Hash)
+
+	r = <-results[1]
+	require.NoError(t, r.Err)
+	mu.Lock()
+	require.Equal(t, 2, sends[txns[1].Hash], "the dropped transaction should be resubmitted")
+	mu.Unlock()
+
+	r = <-results[2]
+	require.Error(t, r.Err)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/0chain/common/core/encryption"
 	"github.com/0chain/errors"
 	"github.com/0chain/gosdk/core/conf"
+	"github.com/0chain/gosdk/core/health"
 	"github.com/0chain/gosdk/core/resty"
 	"github.com/0chain/gosdk/core/util"
 )
@@ -61,7 +62,7 @@ func (v *OptimisticVerifier) VerifyTransactionOptimistic(txnHash string) (*Trans
 	if minNumConfirmation > len(v.sharders) {
 		return nil, errors.New("verify_optimistic", "wrong number of min_confirmations")
 	}
-	shuffled := util.Shuffle(v.sharders)[:minNumConfirmation]
+	shuffled := health.Default.Best(v.sharders, minNumConfirmation)
 
 	//prepare urls for confirmation request
 	urls := make([]string, 0, len(shuffled))
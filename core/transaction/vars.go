@@ -16,4 +16,13 @@ var (
 
 	// ErrTooLessConfirmation too less sharder to confirm transaction
 	ErrTooLessConfirmation = errors.New("[txn] too less sharders to confirm it")
+
+	// ErrInvalidEnvelope the transaction envelope is malformed or was tampered with
+	ErrInvalidEnvelope = errors.New("[txn] invalid transaction envelope")
+
+	// ErrNotSigned the transaction envelope is not signed
+	ErrNotSigned = errors.New("[txn] transaction is not signed")
+
+	// ErrInvalidNonce the transaction nonce must be set explicitly
+	ErrInvalidNonce = errors.New("[txn] invalid transaction nonce")
 )
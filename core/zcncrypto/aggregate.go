@@ -0,0 +1,29 @@
+package zcncrypto
+
+import (
+	"github.com/0chain/errors"
+)
+
+// AggregateSignatures combines BLS signatures of the same message, the result
+// verifies against the sum of the public keys, like the signatures combined
+// by SignatureScheme.Add.
+func AggregateSignatures(signatures ...string) (string, error) {
+	if len(signatures) == 0 {
+		return "", errors.New("aggregate_signatures", "no signature")
+	}
+	if BlsSignerInstance == nil {
+		return "", errors.New("aggregate_signatures", "bls is not available")
+	}
+	sum := BlsSignerInstance.NewSignature()
+	if err := sum.DeserializeHexStr(signatures[0]); err != nil {
+		return "", errors.Wrap(err, "aggregate_signatures: invalid signature")
+	}
+	for _, s := range signatures[1:] {
+		sig := BlsSignerInstance.NewSignature()
+		if err := sig.DeserializeHexStr(s); err != nil {
+			return "", errors.Wrap(err, "aggregate_signatures: invalid signature")
+		}
+		sum.Add(sig)
+	}
+	return sum.SerializeToHexStr(), nil
+}
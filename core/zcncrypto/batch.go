@@ -0,0 +1,250 @@
+package zcncrypto
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/0chain/errors"
+)
+
+// batchCoefficientSize is the size of the random coefficients of a batch, 128
+// bits making the odds of an invalid batch passing negligible.
+const batchCoefficientSize = 16
+
+// SignedHash is the signature of a hex hash by the owner of a public key, the
+// arguments of SignatureScheme.Verify.
+type SignedHash struct {
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+	Hash      string `json:"hash"`
+}
+
+// batchVerifier is implemented by the BlsSigners checking many pairings at once.
+type batchVerifier interface {
+	// verifyBatch reports whether every sigs[i] is the signature of msgs[i]
+	// by pks[i]: e(sum c_i sig_i, g) == prod e(sum c_i H(m_i), pk) with random
+	// c_i, one pairing per distinct public key.
+	verifyBatch(sigs []Signature, pks []PublicKey, msgs []string) bool
+
+	// aggregateVerify reports whether e(sig, g) == prod e(H(msgs[i]), pks[i]).
+	aggregateVerify(sig Signature, pks []PublicKey, msgs []string) bool
+}
+
+// blsItem is a decoded SignedHash.
+type blsItem struct {
+	pk  PublicKey
+	sig Signature
+	msg string
+}
+
+func decodeBlsItem(it SignedHash) (blsItem, error) {
+	pk := BlsSignerInstance.NewPublicKey()
+	if err := pk.DeserializeHexStr(it.PublicKey); err != nil {
+		return blsItem{}, errors.Wrap(err, "invalid public key")
+	}
+	sig := BlsSignerInstance.NewSignature()
+	if err := sig.DeserializeHexStr(it.Signature); err != nil {
+		return blsItem{}, errors.Wrap(err, "invalid signature")
+	}
+	raw, err := hex.DecodeString(it.Hash)
+	if err != nil {
+		return blsItem{}, errors.Wrap(err, "invalid hash")
+	}
+	return blsItem{pk: pk, sig: sig, msg: string(raw)}, nil
+}
+
+func getBatchVerifier() (batchVerifier, error) {
+	bv, ok := BlsSignerInstance.(batchVerifier)
+	if !ok {
+		return nil, errors.Throw(ErrBLSNotAvailable)
+	}
+	return bv, nil
+}
+
+func verifyBlsItems(bv batchVerifier, items []blsItem) bool {
+	sigs := make([]Signature, len(items))
+	pks := make([]PublicKey, len(items))
+	msgs := make([]string, len(items))
+	for i, it := range items {
+		sigs[i], pks[i], msgs[i] = it.sig, it.pk, it.msg
+	}
+	return bv.verifyBatch(sigs, pks, msgs)
+}
+
+// VerifyBatch reports whether all the signatures of items are valid. With the
+// bls0chain scheme they are checked together, at the cost of about one
+// pairing per distinct public key instead of two per signature; the other
+// schemes verify them one by one.
+func VerifyBatch(sigScheme string, items []SignedHash) (bool, error) {
+	if err := checkBatchScheme(sigScheme); err != nil {
+		return false, err
+	}
+	if sigScheme != "bls0chain" {
+		for i, it := range items {
+			ok, err := verifyOne(sigScheme, it)
+			if err != nil {
+				return false, errors.Wrap(err, fmt.Sprintf("verify_batch: item %d", i))
+			}
+			if !ok {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	bv, err := getBatchVerifier()
+	if err != nil {
+		return false, err
+	}
+	decoded := make([]blsItem, len(items))
+	for i, it := range items {
+		if decoded[i], err = decodeBlsItem(it); err != nil {
+			return false, errors.Wrap(err, fmt.Sprintf("verify_batch: item %d", i))
+		}
+	}
+	return verifyBlsItems(bv, decoded), nil
+}
+
+// InvalidSignatures returns the indexes, in ascending order, of the items whose
+// signature isn't valid, including the ones that can't be decoded. With the
+// bls0chain scheme a failing batch is bisected, the cost being logarithmic in
+// the number of items for a few invalid signatures.
+func InvalidSignatures(sigScheme string, items []SignedHash) ([]int, error) {
+	if err := checkBatchScheme(sigScheme); err != nil {
+		return nil, err
+	}
+	var invalid []int
+	if sigScheme != "bls0chain" {
+		for i, it := range items {
+			if ok, err := verifyOne(sigScheme, it); err != nil || !ok {
+				invalid = append(invalid, i)
+			}
+		}
+		return invalid, nil
+	}
+
+	bv, err := getBatchVerifier()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		decoded []blsItem
+		indexes []int
+	)
+	for i, it := range items {
+		d, err := decodeBlsItem(it)
+		if err != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+		decoded = append(decoded, d)
+		indexes = append(indexes, i)
+	}
+
+	var bisect func(lo, hi int)
+	bisect = func(lo, hi int) {
+		if lo == hi || verifyBlsItems(bv, decoded[lo:hi]) {
+			return
+		}
+		if hi-lo == 1 {
+			invalid = append(invalid, indexes[lo])
+			return
+		}
+		mid := (lo + hi) / 2
+		bisect(lo, mid)
+		bisect(mid, hi)
+	}
+	bisect(0, len(decoded))
+
+	sort.Ints(invalid)
+	return invalid, nil
+}
+
+// AggregateVerify reports whether signature, the aggregate by
+// AggregateSignatures of the signatures of hashes[i] by publicKeys[i], is
+// valid. The hashes must be distinct, the aggregate of signatures of the same
+// message being checked against the sum of the public keys instead.
+func AggregateVerify(signature string, publicKeys, hashes []string) (bool, error) {
+	if len(publicKeys) == 0 || len(publicKeys) != len(hashes) {
+		return false, errors.New("aggregate_verify", "one public key per hash is required")
+	}
+	bv, err := getBatchVerifier()
+	if err != nil {
+		return false, err
+	}
+
+	sig := BlsSignerInstance.NewSignature()
+	if err := sig.DeserializeHexStr(signature); err != nil {
+		return false, errors.Wrap(err, "aggregate_verify: invalid signature")
+	}
+	pks := make([]PublicKey, len(publicKeys))
+	msgs := make([]string, len(hashes))
+	seen := make(map[string]bool, len(hashes))
+	for i := range publicKeys {
+		pks[i] = BlsSignerInstance.NewPublicKey()
+		if err := pks[i].DeserializeHexStr(publicKeys[i]); err != nil {
+			return false, errors.Wrap(err, "aggregate_verify: invalid public key")
+		}
+		raw, err := hex.DecodeString(hashes[i])
+		if err != nil {
+			return false, errors.Wrap(err, "aggregate_verify: invalid hash")
+		}
+		msgs[i] = string(raw)
+		if seen[msgs[i]] {
+			return false, errors.New("aggregate_verify", "duplicate hash "+hashes[i])
+		}
+		seen[msgs[i]] = true
+	}
+	return bv.aggregateVerify(sig, pks, msgs), nil
+}
+
+// checkBatchScheme fails for the schemes NewSignatureScheme doesn't know.
+func checkBatchScheme(sigScheme string) error {
+	switch sigScheme {
+	case "bls0chain", "ed25519":
+		return nil
+	default:
+		return errors.New("verify_batch", "unknown signature scheme: "+sigScheme)
+	}
+}
+
+func verifyOne(sigScheme string, it SignedHash) (bool, error) {
+	ss := NewSignatureScheme(sigScheme)
+	if err := ss.SetPublicKey(it.PublicKey); err != nil {
+		return false, err
+	}
+	return ss.Verify(it.Signature, it.Hash)
+}
+
+// batchCoefficients returns n random non-zero little-endian scalars.
+func batchCoefficients(n int) ([][]byte, error) {
+	buf := make([]byte, n*batchCoefficientSize)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	coeffs := make([][]byte, n)
+	for i := range coeffs {
+		coeffs[i] = buf[i*batchCoefficientSize : (i+1)*batchCoefficientSize]
+		coeffs[i][0] |= 1
+	}
+	return coeffs, nil
+}
+
+// groupByKey returns the index in the distinct keys of pks of every key, and
+// the number of distinct keys.
+func groupByKey(pks []PublicKey) ([]int, int) {
+	groups := make([]int, len(pks))
+	index := make(map[string]int)
+	for i, pk := range pks {
+		k := pk.SerializeToHexStr()
+		g, ok := index[k]
+		if !ok {
+			g = len(index)
+			index[k] = g
+		}
+		groups[i] = g
+	}
+	return groups, len(index)
+}
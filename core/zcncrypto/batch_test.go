@@ -0,0 +1,114 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package zcncrypto
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/0chain/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func signedHashes(t *testing.T, scheme string, keys, n int) []SignedHash {
+	wallets := make([]*Wallet, keys)
+	for i := range wallets {
+		w, err := NewSignatureScheme(scheme).GenerateKeys()
+		require.NoError(t, err)
+		wallets[i] = w
+	}
+	items := make([]SignedHash, n)
+	for i := range items {
+		w := wallets[i%keys]
+		hash := Sha3Sum256(fmt.Sprint("marker ", i))
+		sig, err := w.Sign(hash, scheme)
+		require.NoError(t, err)
+		items[i] = SignedHash{PublicKey: w.Keys[0].PublicKey, Signature: sig, Hash: hash}
+	}
+	return items
+}
+
+func TestVerifyBatch(t *testing.T) {
+	for _, scheme := range []string{"bls0chain", "ed25519"} {
+		t.Run(scheme, func(t *testing.T) {
+			items := signedHashes(t, scheme, 3, 20)
+
+			ok, err := VerifyBatch(scheme, items)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			invalid, err := InvalidSignatures(scheme, items)
+			require.NoError(t, err)
+			require.Empty(t, invalid)
+
+			// signatures of other hashes, swapped keys, garbage
+			items[3].Hash, items[4].Hash = items[4].Hash, items[3].Hash
+			items[11].PublicKey = items[12].PublicKey
+			items[17].Signature = "zz"
+
+			ok, err = VerifyBatch(scheme, items[:10])
+			require.NoError(t, err)
+			require.False(t, ok)
+			_, err = VerifyBatch(scheme, items[15:])
+			require.Error(t, err)
+
+			invalid, err = InvalidSignatures(scheme, items)
+			require.NoError(t, err)
+			require.Equal(t, []int{3, 4, 11, 17}, invalid)
+		})
+	}
+
+	ok, err := VerifyBatch("bls0chain", nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// unknown schemes fail instead of panicking
+	items := signedHashes(t, "bls0chain", 1, 1)
+	_, err = VerifyBatch("", items)
+	require.Error(t, err)
+	_, err = InvalidSignatures("rsa", items)
+	require.Error(t, err)
+}
+
+func TestVerifyBatchZeroKey(t *testing.T) {
+	items := signedHashes(t, "bls0chain", 1, 2)
+	items[1].PublicKey = fmt.Sprintf("%0128d", 0)
+	items[1].Signature = fmt.Sprintf("%064d", 0)
+
+	ok, err := VerifyBatch("bls0chain", items)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestAggregateVerify(t *testing.T) {
+	items := signedHashes(t, "bls0chain", 4, 4)
+	var sigs, pks, hashes []string
+	for _, it := range items {
+		sigs = append(sigs, it.Signature)
+		pks = append(pks, it.PublicKey)
+		hashes = append(hashes, it.Hash)
+	}
+	sig, err := AggregateSignatures(sigs...)
+	require.NoError(t, err)
+
+	ok, err := AggregateVerify(sig, pks, hashes)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = AggregateVerify(sig, pks[:3], hashes[:3])
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	pks[0], pks[1] = pks[1], pks[0]
+	ok, err = AggregateVerify(sig, pks, hashes)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = AggregateVerify(sig, pks, []string{hashes[0], hashes[0], hashes[2], hashes[3]})
+	require.Error(t, err)
+
+	_, err = AggregateVerify(sig, pks, hashes[:2])
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrBLSNotAvailable))
+}
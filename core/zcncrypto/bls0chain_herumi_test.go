@@ -111,6 +111,66 @@ func TestCombinedSignAndVerify(t *testing.T) {
 	}
 }
 
+func TestAggregateSignatures(t *testing.T) {
+	sk0 := `c36f2f92b673cf057a32e8bd0ca88888e7ace40337b737e9c7459fdc4c521918`
+	sk1 := `704b6f489583bf1118432fcfb38e63fc2d4b61e524fb196cbd95413f8eb91c12`
+	hash := Sha3Sum256(data)
+
+	sig0 := NewSignatureScheme("bls0chain")
+	require.NoError(t, sig0.SetPrivateKey(sk0))
+	signature0, err := sig0.Sign(hash)
+	require.NoError(t, err)
+	sig1 := NewSignatureScheme("bls0chain")
+	require.NoError(t, sig1.SetPrivateKey(sk1))
+	signature1, err := sig1.Sign(hash)
+	require.NoError(t, err)
+
+	added, err := sig1.Add(signature0, hash)
+	require.NoError(t, err)
+	aggregated, err := AggregateSignatures(signature0, signature1)
+	require.NoError(t, err)
+	require.Equal(t, added, aggregated)
+
+	_, err = AggregateSignatures()
+	require.Error(t, err)
+	_, err = AggregateSignatures(signature0, "invalid")
+	require.Error(t, err)
+}
+
+func TestRecoverThresholdSignature(t *testing.T) {
+	group := NewSignatureScheme("bls0chain")
+	_, err := group.GenerateKeys()
+	require.NoError(t, err)
+	shares, err := GenerateThresholdKeyShares(2, 3, group)
+	require.NoError(t, err)
+	hash := Sha3Sum256(data)
+
+	var ids, sigs []string
+	for _, i := range []int{2, 0} {
+		share := NewSignatureScheme("bls0chain")
+		require.NoError(t, share.SetPrivateKey(shares[i].GetPrivateKey()))
+		sig, err := share.Sign(hash)
+		require.NoError(t, err)
+		ids = append(ids, shares[i].GetID())
+		sigs = append(sigs, sig)
+	}
+
+	sig, err := RecoverThresholdSignature(ids, sigs)
+	require.NoError(t, err)
+	verifyScheme := NewSignatureScheme("bls0chain")
+	require.NoError(t, verifyScheme.SetPublicKey(group.GetPublicKey()))
+	ok, err := verifyScheme.Verify(sig, hash)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// below the threshold the group signature can't be recovered
+	sig, err = RecoverThresholdSignature(ids[:1], sigs[:1])
+	require.NoError(t, err)
+	ok, err = verifyScheme.Verify(sig, hash)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
 func TestSplitKey(t *testing.T) {
 	primaryKeyStr := `c36f2f92b673cf057a32e8bd0ca88888e7ace40337b737e9c7459fdc4c521918`
 	sig0 := NewSignatureScheme("bls0chain")
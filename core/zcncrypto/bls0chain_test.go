@@ -6,16 +6,14 @@ package zcncrypto
 import (
 	"testing"
 
-	"github.com/herumi/bls-go-binary/bls"
 	"github.com/stretchr/testify/require"
 )
 
 const (
 	testMnemonic = "silent tape impulse glimpse state craft sheriff embody bonus clay confirm column swift kingdom door stove mad switch chalk theory pause canoe insane struggle"
 
-	testHerumiPublicKey    = "fd2f78b5988719434d6a0782231962934fe1a6f805f98e1bff2c90399a765500ffff9a1cc8c5826feea66d738a7e74ffba7f7dd23e499b5817d8a88e68185f95"
-	testHerumiPublicKeyStr = "1 55769a39902cff1b8ef905f8a6e14f9362192382076a4d43198798b5782ffd 155f18688ea8d817589b493ed27d7fbaff747e8a736da6ee6f82c5c81c9affff e72525d5dda83d7b169653d3a78bd6d6e36cee1f9974d8f30cbfac33a18efb9 19c1c219dbd76990330f778f18d472f10494a6811bb46e36d21bfdf273c03220"
-	testHerumiPrivateKey   = "baa512aee00f5ff9eafcd82a16fa81d450b2a1a1e35f638cb7e4c2caf01bc407"
+	testHerumiPublicKey  = "fd2f78b5988719434d6a0782231962934fe1a6f805f98e1bff2c90399a765500ffff9a1cc8c5826feea66d738a7e74ffba7f7dd23e499b5817d8a88e68185f95"
+	testHerumiPrivateKey = "baa512aee00f5ff9eafcd82a16fa81d450b2a1a1e35f638cb7e4c2caf01bc407"
 )
 
 func TestGenerateKeys(t *testing.T) {
@@ -27,11 +25,11 @@ func TestGenerateKeys(t *testing.T) {
 
 	require.Equal(t, testHerumiPublicKey, w1.Keys[0].PublicKey)
 
-	var pk1 bls.PublicKey
+	pk1 := BlsSignerInstance.NewPublicKey()
 	err = pk1.DeserializeHexStr(w1.Keys[0].PublicKey)
 	require.NoError(t, err)
 
-	require.Equal(t, testHerumiPublicKeyStr, pk1.GetHexString())
+	require.Equal(t, testHerumiPublicKey, pk1.SerializeToHexStr())
 
 	require.NoError(t, err)
 
@@ -44,7 +42,7 @@ func TestSignAndVerify(t *testing.T) {
 	require.Nil(t, err)
 
 	var pk = w.Keys[0].PublicKey
-	var pk1 bls.PublicKey
+	pk1 := BlsSignerInstance.NewPublicKey()
 
 	err = pk1.DeserializeHexStr(pk)
 	require.Nil(t, err)
@@ -0,0 +1,430 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package zcncrypto
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/0chain/gosdk/core/zcncrypto/bn254"
+)
+
+// frByteSize is the size of a serialized scalar.
+const frByteSize = 32
+
+var (
+	goRandMu     sync.Mutex
+	goRandReader io.Reader = rand.Reader
+)
+
+// goBls is a pure Go BlsSigner, byte-identical to herumiBls; it is the
+// BlsSigner of builds with the purego tag. It isn't constant time, see
+// bls_purego.go.
+type goBls struct {
+}
+
+func (b *goBls) NewFr() Fr {
+	return &goFr{}
+}
+
+func (b *goBls) NewSecretKey() SecretKey {
+	return &goSecretKey{}
+}
+
+func (b *goBls) NewPublicKey() PublicKey {
+	return &goPublicKey{}
+}
+
+func (b *goBls) NewSignature() Signature {
+	return &goSignature{}
+}
+
+func (b *goBls) NewID() ID {
+	return &goID{}
+}
+
+func (b *goBls) SetRandFunc(randReader io.Reader) {
+	goRandMu.Lock()
+	defer goRandMu.Unlock()
+	if randReader == nil {
+		randReader = rand.Reader
+	}
+	goRandReader = randReader
+}
+
+func (b *goBls) FrSub(out Fr, x Fr, y Fr) {
+	o1, _ := out.(*goFr)
+	x1, _ := x.(*goFr)
+	y1, _ := y.(*goFr)
+
+	o1.v.Sub(&x1.v, &y1.v)
+	o1.v.Mod(&o1.v, bn254.Order)
+}
+
+// setMasked sets z to the little-endian buf masked to the size of the order,
+// like mcl's setArrayMask.
+func setMasked(z *big.Int, buf []byte) {
+	size := (bn254.Order.BitLen() + 7) / 8
+	if len(buf) > size {
+		buf = buf[:size]
+	}
+	z.SetBytes(reverseBytes(buf))
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(bn254.Order.BitLen()))
+	mask.Sub(mask, big.NewInt(1))
+	z.And(z, mask)
+	if z.Cmp(bn254.Order) >= 0 {
+		z.And(z, mask.Rsh(mask, 1))
+	}
+}
+
+func serializeFr(x *big.Int) []byte {
+	return reverseBytes(x.FillBytes(make([]byte, frByteSize)))
+}
+
+func deserializeFr(z *big.Int, buf []byte) error {
+	if len(buf) != frByteSize {
+		return errors.New("invalid scalar size")
+	}
+	v := new(big.Int).SetBytes(reverseBytes(buf))
+	if v.Cmp(bn254.Order) >= 0 {
+		return errors.New("invalid scalar")
+	}
+	z.Set(v)
+	return nil
+}
+
+func reverseBytes(buf []byte) []byte {
+	r := make([]byte, len(buf))
+	for i, b := range buf {
+		r[len(buf)-1-i] = b
+	}
+	return r
+}
+
+type goFr struct {
+	v big.Int
+}
+
+func (fr *goFr) Serialize() []byte {
+	return serializeFr(&fr.v)
+}
+
+func (fr *goFr) SetLittleEndian(buf []byte) error {
+	setMasked(&fr.v, buf)
+	return nil
+}
+
+type goSecretKey struct {
+	v big.Int
+}
+
+func (sk *goSecretKey) SerializeToHexStr() string {
+	return hex.EncodeToString(sk.Serialize())
+}
+
+func (sk *goSecretKey) DeserializeHexStr(s string) error {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return deserializeFr(&sk.v, buf)
+}
+
+func (sk *goSecretKey) Serialize() []byte {
+	return serializeFr(&sk.v)
+}
+
+func (sk *goSecretKey) GetLittleEndian() []byte {
+	return sk.Serialize()
+}
+
+func (sk *goSecretKey) SetLittleEndian(buf []byte) error {
+	setMasked(&sk.v, buf)
+	return nil
+}
+
+// SetByCSPRNG reads 32 bytes from the random reader, like mcl, so that keys
+// generated from a seed are the ones of herumi.
+func (sk *goSecretKey) SetByCSPRNG() {
+	goRandMu.Lock()
+	defer goRandMu.Unlock()
+	buf := make([]byte, frByteSize)
+	if _, err := io.ReadFull(goRandReader, buf); err != nil {
+		panic("err SetByCSPRNG: " + err.Error())
+	}
+	setMasked(&sk.v, buf)
+	if sk.v.Sign() == 0 {
+		panic("err SetByCSPRNG zero")
+	}
+}
+
+func (sk *goSecretKey) GetPublicKey() PublicKey {
+	pk := &goPublicKey{}
+	pk.p.ScalarMult(bn254.G2Generator(), &sk.v)
+	return pk
+}
+
+func (sk *goSecretKey) Add(rhs SecretKey) {
+	i, _ := rhs.(*goSecretKey)
+	sk.v.Add(&sk.v, &i.v)
+	sk.v.Mod(&sk.v, bn254.Order)
+}
+
+func (sk *goSecretKey) Sign(m string) Signature {
+	h, err := bn254.HashToG1([]byte(m))
+	if err != nil {
+		// sha256 of m mapped to zero or an exceptional point, 2^-250 likely
+		panic("err Sign: " + err.Error())
+	}
+	sig := &goSignature{}
+	sig.p.ScalarMult(h, &sk.v)
+	return sig
+}
+
+func (sk *goSecretKey) GetMasterSecretKey(k int) ([]SecretKey, error) {
+	if k < 1 {
+		return nil, errors.New("cannot get master secret key for threshold less than 1")
+	}
+
+	msk := make([]SecretKey, k)
+	msk[0] = &goSecretKey{}
+	msk[0].(*goSecretKey).v.Set(&sk.v)
+	for i := 1; i < k; i++ {
+		c := &goSecretKey{}
+		c.SetByCSPRNG()
+		msk[i] = c
+	}
+	return msk, nil
+}
+
+// Set sets sk to the share of id of the polynomial msk.
+func (sk *goSecretKey) Set(msk []SecretKey, id ID) error {
+	if len(msk) == 0 {
+		return errors.New("empty master secret key")
+	}
+	x, ok := id.(*goID)
+	if !ok {
+		return errors.New("invalid go id")
+	}
+
+	y := new(big.Int)
+	for i := len(msk) - 1; i >= 0; i-- {
+		k, ok := msk[i].(*goSecretKey)
+		if !ok {
+			return errors.New("invalid go secret key")
+		}
+		y.Mul(y, &x.v)
+		y.Add(y, &k.v)
+		y.Mod(y, bn254.Order)
+	}
+	sk.v.Set(y)
+	return nil
+}
+
+type goPublicKey struct {
+	p bn254.G2
+}
+
+func (pk *goPublicKey) SerializeToHexStr() string {
+	return hex.EncodeToString(pk.Serialize())
+}
+
+func (pk *goPublicKey) DeserializeHexStr(s string) error {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return pk.p.Unmarshal(buf)
+}
+
+func (pk *goPublicKey) Serialize() []byte {
+	return pk.p.Marshal()
+}
+
+type goSignature struct {
+	p bn254.G1
+}
+
+func (sg *goSignature) SerializeToHexStr() string {
+	return hex.EncodeToString(sg.p.Marshal())
+}
+
+func (sg *goSignature) DeserializeHexStr(s string) error {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return sg.p.Unmarshal(buf)
+}
+
+func (sg *goSignature) Add(rhs Signature) {
+	sg2, _ := rhs.(*goSignature)
+	sg.p.Add(&sg.p, &sg2.p)
+}
+
+// Verify checks e(sig, g) == e(H(m), pk).
+func (sg *goSignature) Verify(pk PublicKey, m string) bool {
+	pub, _ := pk.(*goPublicKey)
+	if pub == nil || pub.p.IsZero() {
+		return false
+	}
+	h, err := bn254.HashToG1([]byte(m))
+	if err != nil {
+		return false
+	}
+	var neg bn254.G1
+	neg.Neg(h)
+	return bn254.PairingCheck([]*bn254.G1{&sg.p, &neg}, []*bn254.G2{bn254.G2Generator(), &pub.p})
+}
+
+type goID struct {
+	v big.Int
+}
+
+func (id *goID) SetHexString(s string) error {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	return id.set(s, 16)
+}
+
+func (id *goID) GetHexString() string {
+	return id.v.Text(16)
+}
+
+func (id *goID) SetDecString(s string) error {
+	return id.set(s, 10)
+}
+
+func (id *goID) set(s string, base int) error {
+	v, ok := new(big.Int).SetString(s, base)
+	if !ok {
+		return errors.New("invalid id: " + s)
+	}
+	if v.Cmp(bn254.Order) >= 0 {
+		return errors.New("invalid id: " + s)
+	}
+	id.v.Mod(v, bn254.Order)
+	return nil
+}
+
+// goRecoverThresholdSignature is the Lagrange interpolation at zero of the
+// signatures of the shares ids.
+func goRecoverThresholdSignature(ids, signatures []string) (string, error) {
+	xs := make([]*big.Int, len(ids))
+	for i, s := range ids {
+		id := &goID{}
+		if err := id.SetHexString(s); err != nil {
+			return "", err
+		}
+		if id.v.Sign() == 0 {
+			return "", errors.New("invalid id: zero")
+		}
+		for _, x := range xs[:i] {
+			if x.Cmp(&id.v) == 0 {
+				return "", errors.New("duplicate id: " + s)
+			}
+		}
+		xs[i] = &id.v
+	}
+
+	var sum bn254.G1
+	for i, s := range signatures {
+		sig := &goSignature{}
+		if err := sig.DeserializeHexStr(s); err != nil {
+			return "", err
+		}
+		// delta_i = prod_{j != i} x_j / (x_j - x_i)
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j, x := range xs {
+			if j == i {
+				continue
+			}
+			num.Mul(num, x)
+			num.Mod(num, bn254.Order)
+			d := new(big.Int).Sub(x, xs[i])
+			den.Mul(den, d)
+			den.Mod(den, bn254.Order)
+		}
+		den.ModInverse(den, bn254.Order)
+		num.Mul(num, den)
+		num.Mod(num, bn254.Order)
+
+		var term bn254.G1
+		term.ScalarMult(&sig.p, num)
+		sum.Add(&sum, &term)
+	}
+	return hex.EncodeToString(sum.Marshal()), nil
+}
+
+func (b *goBls) verifyBatch(sigs []Signature, pks []PublicKey, msgs []string) bool {
+	if len(sigs) == 0 {
+		return true
+	}
+	coeffs, err := batchCoefficients(len(sigs))
+	if err != nil {
+		return false
+	}
+	groups, n := groupByKey(pks)
+	hs := make([]bn254.G1, n)
+	gpks := make([]*bn254.G2, n)
+
+	var sum, t bn254.G1
+	c := new(big.Int)
+	for i, s := range sigs {
+		sig, _ := s.(*goSignature)
+		pub, _ := pks[i].(*goPublicKey)
+		if sig == nil || pub == nil || pub.p.IsZero() {
+			return false
+		}
+		h, err := bn254.HashToG1([]byte(msgs[i]))
+		if err != nil {
+			return false
+		}
+		c.SetBytes(reverseBytes(coeffs[i]))
+		sum.Add(&sum, t.ScalarMult(&sig.p, c))
+		g := groups[i]
+		hs[g].Add(&hs[g], t.ScalarMult(h, c))
+		gpks[g] = &pub.p
+	}
+	return goPairingCheck(&sum, hs, gpks)
+}
+
+func (b *goBls) aggregateVerify(s Signature, pks []PublicKey, msgs []string) bool {
+	sig, _ := s.(*goSignature)
+	if sig == nil {
+		return false
+	}
+	hs := make([]bn254.G1, len(pks))
+	gpks := make([]*bn254.G2, len(pks))
+	for i, pk := range pks {
+		pub, _ := pk.(*goPublicKey)
+		if pub == nil || pub.p.IsZero() {
+			return false
+		}
+		h, err := bn254.HashToG1([]byte(msgs[i]))
+		if err != nil {
+			return false
+		}
+		hs[i], gpks[i] = *h, &pub.p
+	}
+	return goPairingCheck(&sig.p, hs, gpks)
+}
+
+// goPairingCheck reports whether e(sig, g) == prod e(hs[i], pks[i]).
+func goPairingCheck(sig *bn254.G1, hs []bn254.G1, pks []*bn254.G2) bool {
+	g1 := make([]*bn254.G1, 0, len(hs)+1)
+	g2 := make([]*bn254.G2, 0, len(hs)+1)
+	var neg bn254.G1
+	g1 = append(g1, neg.Neg(sig))
+	g2 = append(g2, bn254.G2Generator())
+	for i := range hs {
+		g1 = append(g1, &hs[i])
+		g2 = append(g2, pks[i])
+	}
+	return bn254.PairingCheck(g1, g2)
+}
@@ -0,0 +1,121 @@
+//go:build !js && !wasm && cgo && !purego
+// +build !js,!wasm,cgo,!purego
+
+package zcncrypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/herumi/bls-go-binary/bls"
+	"github.com/stretchr/testify/require"
+)
+
+const testHerumiPublicKeyStr = "1 55769a39902cff1b8ef905f8a6e14f9362192382076a4d43198798b5782ffd 155f18688ea8d817589b493ed27d7fbaff747e8a736da6ee6f82c5c81c9affff e72525d5dda83d7b169653d3a78bd6d6e36cee1f9974d8f30cbfac33a18efb9 19c1c219dbd76990330f778f18d472f10494a6811bb46e36d21bfdf273c03220"
+
+func TestHerumiPublicKeyStr(t *testing.T) {
+	var pk bls.PublicKey
+	require.NoError(t, pk.DeserializeHexStr(testHerumiPublicKey))
+	require.Equal(t, testHerumiPublicKeyStr, pk.GetHexString())
+}
+
+// TestGoBlsMatchesHerumi checks that the pure Go BlsSigner produces the same
+// bytes as the herumi one.
+func TestGoBlsMatchesHerumi(t *testing.T) {
+	herumi, pure := &herumiBls{}, &goBls{}
+
+	seed := make([]byte, 64)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+	herumi.SetRandFunc(bytes.NewReader(seed))
+	pure.SetRandFunc(bytes.NewReader(seed))
+	hsk, gsk := herumi.NewSecretKey(), pure.NewSecretKey()
+	hsk.SetByCSPRNG()
+	gsk.SetByCSPRNG()
+	herumi.SetRandFunc(nil)
+	pure.SetRandFunc(nil)
+	require.Equal(t, hsk.SerializeToHexStr(), gsk.SerializeToHexStr())
+
+	for i := 0; i < 20; i++ {
+		buf := make([]byte, 32)
+		_, err := rand.Read(buf)
+		require.NoError(t, err)
+		if i%2 == 0 {
+			// above the order, masked
+			buf[31] |= 0xc0
+		}
+		hsk, gsk := herumi.NewSecretKey(), pure.NewSecretKey()
+		require.NoError(t, hsk.SetLittleEndian(buf))
+		require.NoError(t, gsk.SetLittleEndian(buf))
+		require.Equal(t, hsk.SerializeToHexStr(), gsk.SerializeToHexStr())
+		require.Equal(t, hsk.GetPublicKey().SerializeToHexStr(), gsk.GetPublicKey().SerializeToHexStr())
+
+		msg := string(buf) + fmt.Sprint(i)
+		hsig, gsig := hsk.Sign(msg), gsk.Sign(msg)
+		require.Equal(t, hsig.SerializeToHexStr(), gsig.SerializeToHexStr())
+
+		// signatures and keys of one implementation verify with the other
+		gpk, hpk := pure.NewPublicKey(), herumi.NewPublicKey()
+		require.NoError(t, gpk.DeserializeHexStr(hsk.GetPublicKey().SerializeToHexStr()))
+		require.NoError(t, hpk.DeserializeHexStr(gsk.GetPublicKey().SerializeToHexStr()))
+		gs, hs := pure.NewSignature(), herumi.NewSignature()
+		require.NoError(t, gs.DeserializeHexStr(hsig.SerializeToHexStr()))
+		require.NoError(t, hs.DeserializeHexStr(gsig.SerializeToHexStr()))
+		require.True(t, gs.Verify(gpk, msg))
+		require.True(t, hs.Verify(hpk, msg))
+		require.False(t, gs.Verify(gpk, msg+"x"))
+	}
+}
+
+func TestGoBlsThreshold(t *testing.T) {
+	herumi, pure := &herumiBls{}, &goBls{}
+	hsk := herumi.NewSecretKey()
+	hsk.SetByCSPRNG()
+	hmsk, err := hsk.GetMasterSecretKey(3)
+	require.NoError(t, err)
+
+	gmsk := make([]SecretKey, len(hmsk))
+	for i, k := range hmsk {
+		gmsk[i] = pure.NewSecretKey()
+		require.NoError(t, gmsk[i].DeserializeHexStr(k.SerializeToHexStr()))
+	}
+
+	msg := "threshold"
+	var ids, sigs []string
+	for i := 1; i <= 4; i++ {
+		hid, gid := herumi.NewID(), pure.NewID()
+		require.NoError(t, hid.SetDecString(fmt.Sprint(i*1000003)))
+		require.NoError(t, gid.SetDecString(fmt.Sprint(i*1000003)))
+		require.Equal(t, hid.GetHexString(), gid.GetHexString())
+
+		hshare, gshare := herumi.NewSecretKey(), pure.NewSecretKey()
+		require.NoError(t, hshare.Set(hmsk, hid))
+		require.NoError(t, gshare.Set(gmsk, gid))
+		require.Equal(t, hshare.SerializeToHexStr(), gshare.SerializeToHexStr())
+
+		ids = append(ids, gid.GetHexString())
+		sigs = append(sigs, gshare.Sign(msg).SerializeToHexStr())
+	}
+
+	want, err := recoverThresholdSignature(ids[1:], sigs[1:])
+	require.NoError(t, err)
+	got, err := goRecoverThresholdSignature(ids[1:], sigs[1:])
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+	require.Equal(t, hsk.Sign(msg).SerializeToHexStr(), got)
+
+	_, err = goRecoverThresholdSignature([]string{ids[0], ids[0]}, sigs[:2])
+	require.Error(t, err)
+
+	hfr, gfr := herumi.NewFr(), pure.NewFr()
+	buf, _ := hex.DecodeString(hsk.SerializeToHexStr())
+	require.NoError(t, hfr.SetLittleEndian(buf))
+	require.NoError(t, gfr.SetLittleEndian(buf))
+	hout, gout := herumi.NewFr(), pure.NewFr()
+	herumi.FrSub(hout, herumi.NewFr(), hfr)
+	pure.FrSub(gout, pure.NewFr(), gfr)
+	require.Equal(t, hout.Serialize(), gout.Serialize())
+}
@@ -1,5 +1,5 @@
-//go:build !js && !wasm
-// +build !js,!wasm
+//go:build !js && !wasm && !purego
+// +build !js,!wasm,!purego
 
 package zcncrypto
 
@@ -208,3 +208,98 @@ func (id *herumiID) GetHexString() string {
 func (id *herumiID) SetDecString(s string) error {
 	return id.ID.SetDecString(s)
 }
+
+func (b *herumiBls) verifyBatch(sigs []Signature, pks []PublicKey, msgs []string) bool {
+	if len(sigs) == 0 {
+		return true
+	}
+	coeffs, err := batchCoefficients(len(sigs))
+	if err != nil {
+		return false
+	}
+	groups, n := groupByKey(pks)
+	hs := make([]bls.G1, n)
+	gpks := make([]bls.G2, n)
+
+	sigVec := make([]bls.G1, len(sigs))
+	frVec := make([]bls.Fr, len(sigs))
+	var h bls.G1
+	for i, s := range sigs {
+		sig, _ := s.(*herumiSignature)
+		pub, _ := pks[i].(*herumiPublicKey)
+		if sig == nil || pub == nil || pub.IsZero() {
+			return false
+		}
+		if err := frVec[i].SetLittleEndian(coeffs[i]); err != nil {
+			return false
+		}
+		if err := h.HashAndMapTo([]byte(msgs[i])); err != nil {
+			return false
+		}
+		sigVec[i] = *bls.CastFromSign(sig.Sign)
+		g := groups[i]
+		bls.G1Mul(&h, &h, &frVec[i])
+		bls.G1Add(&hs[g], &hs[g], &h)
+		gpks[g] = *bls.CastFromPublicKey(pub.PublicKey)
+	}
+	var sum bls.G1
+	bls.G1MulVec(&sum, sigVec, frVec)
+	return herumiPairingCheck(&sum, hs, gpks)
+}
+
+func (b *herumiBls) aggregateVerify(s Signature, pks []PublicKey, msgs []string) bool {
+	sig, _ := s.(*herumiSignature)
+	if sig == nil {
+		return false
+	}
+	hs := make([]bls.G1, len(pks))
+	gpks := make([]bls.G2, len(pks))
+	for i, pk := range pks {
+		pub, _ := pk.(*herumiPublicKey)
+		if pub == nil || pub.IsZero() {
+			return false
+		}
+		if err := hs[i].HashAndMapTo([]byte(msgs[i])); err != nil {
+			return false
+		}
+		gpks[i] = *bls.CastFromPublicKey(pub.PublicKey)
+	}
+	return herumiPairingCheck(bls.CastFromSign(sig.Sign), hs, gpks)
+}
+
+// herumiPairingCheck reports whether e(sig, g) == prod e(hs[i], pks[i]).
+func herumiPairingCheck(sig *bls.G1, hs []bls.G1, pks []bls.G2) bool {
+	var gen bls.PublicKey
+	bls.GetGeneratorOfPublicKey(&gen)
+
+	xs := make([]bls.G1, len(hs)+1)
+	ys := make([]bls.G2, len(hs)+1)
+	bls.G1Neg(&xs[0], sig)
+	ys[0] = *bls.CastFromPublicKey(&gen)
+	copy(xs[1:], hs)
+	copy(ys[1:], pks)
+
+	var e bls.GT
+	bls.MillerLoopVec(&e, xs, ys)
+	bls.FinalExp(&e, &e)
+	return e.IsOne()
+}
+
+func recoverThresholdSignature(ids, signatures []string) (string, error) {
+	idVec := make([]bls.ID, len(ids))
+	sigVec := make([]bls.Sign, len(signatures))
+	for i := range ids {
+		if err := idVec[i].SetHexString(ids[i]); err != nil {
+			return "", err
+		}
+		if err := sigVec[i].DeserializeHexStr(signatures[i]); err != nil {
+			return "", err
+		}
+	}
+
+	var sig bls.Sign
+	if err := sig.Recover(sigVec, idVec); err != nil {
+		return "", err
+	}
+	return sig.SerializeToHexStr(), nil
+}
@@ -0,0 +1,16 @@
+//go:build !js && !wasm && purego
+// +build !js,!wasm,purego
+
+package zcncrypto
+
+// The pure Go implementation is only used when asked for with the purego tag:
+// its arithmetic uses math/big, which isn't constant time, so the time taken
+// to sign or derive keys can leak bits of the secret key to someone timing
+// many operations. Builds without cgo need the tag.
+func init() {
+	BlsSignerInstance = &goBls{}
+}
+
+func recoverThresholdSignature(ids, signatures []string) (string, error) {
+	return goRecoverThresholdSignature(ids, signatures)
+}
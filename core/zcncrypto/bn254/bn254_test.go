@@ -0,0 +1,115 @@
+package bn254
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randScalar(t *testing.T) *big.Int {
+	k, err := rand.Int(rand.Reader, Order)
+	require.NoError(t, err)
+	return k
+}
+
+func TestCurveParameters(t *testing.T) {
+	u := curveU
+	poly := func(c4, c3, c2, c1 int64) *big.Int {
+		z := big.NewInt(0)
+		for _, c := range []int64{c4, c3, c2, c1} {
+			z.Mul(z, u)
+			z.Add(z, big.NewInt(c))
+		}
+		z.Mul(z, u)
+		return z.Add(z, bigOne)
+	}
+	require.Equal(t, 0, P.Cmp(poly(36, 36, 24, 6)))
+	require.Equal(t, 0, Order.Cmp(poly(36, 36, 18, 6)))
+}
+
+func TestField(t *testing.T) {
+	x, _ := rand.Int(rand.Reader, P)
+	y, _ := rand.Int(rand.Reader, P)
+	var a, b, c fp
+	a.setBig(x)
+	b.setBig(y)
+	require.Equal(t, 0, x.Cmp(a.big()))
+
+	want := new(big.Int).Mul(x, y)
+	require.Equal(t, 0, want.Mod(want, P).Cmp(fpMul(&c, &a, &b).big()))
+	want = new(big.Int).Sub(x, y)
+	require.Equal(t, 0, want.Mod(want, P).Cmp(fpSub(&c, &a, &b).big()))
+	want = new(big.Int).Add(x, y)
+	require.Equal(t, 0, want.Mod(want, P).Cmp(fpAdd(&c, &a, &b).big()))
+	fpInv(&c, &a)
+	require.True(t, fpMul(&c, &c, &a).equal(&fpOne))
+
+	var z, s fp2
+	z.a, z.b = a, b
+	fp2Sqr(&s, &z)
+	require.True(t, fp2Sqrt(&s, &s))
+	fp2Sqr(&s, &s)
+	var zz fp2
+	require.True(t, fp2Sqr(&zz, &z).equal(&s))
+}
+
+func TestGroups(t *testing.T) {
+	var x, y fp2
+	x, y = g2Gen.affine()
+	var l, r fp2
+	fp2Sqr(&l, &y)
+	g2Weierstrass(&r, &x)
+	require.True(t, l.equal(&r), "the generator isn't on the twist")
+
+	var o G2
+	require.True(t, o.ScalarMult(G2Generator(), Order).IsZero())
+
+	h, err := HashToG1([]byte("abc"))
+	require.NoError(t, err)
+	var o1 G1
+	require.True(t, o1.ScalarMult(h, Order).IsZero())
+
+	a, b := randScalar(t), randScalar(t)
+	var pa, pb, sum, want G2
+	pa.ScalarMult(G2Generator(), a)
+	pb.ScalarMult(G2Generator(), b)
+	sum.Add(&pa, &pb)
+	want.ScalarMult(G2Generator(), new(big.Int).Add(a, b))
+	require.True(t, sum.Equal(&want))
+	sum.Add(&pa, &pa)
+	want.Double(&pa)
+	require.True(t, sum.Equal(&want))
+
+	var q G2
+	require.NoError(t, q.Unmarshal(pa.Marshal()))
+	require.True(t, q.Equal(&pa))
+
+	var g, g2 G1
+	g.ScalarMult(h, a)
+	require.NoError(t, g2.Unmarshal(g.Marshal()))
+	require.True(t, g.Equal(&g2))
+}
+
+func TestPairing(t *testing.T) {
+	h, err := HashToG1([]byte("message"))
+	require.NoError(t, err)
+	a, b := randScalar(t), randScalar(t)
+
+	var ha, hb G1
+	var qa, qb G2
+	ha.ScalarMult(h, a)
+	hb.ScalarMult(h, b)
+	qa.ScalarMult(G2Generator(), a)
+	qb.ScalarMult(G2Generator(), b)
+
+	// e(a h, b g) == e(b h, a g)
+	var neg G1
+	neg.Neg(&hb)
+	require.True(t, PairingCheck([]*G1{&ha, &neg}, []*G2{&qb, &qa}))
+	require.False(t, PairingCheck([]*G1{&ha, &neg}, []*G2{&qa, &qb}))
+
+	// non-degenerate
+	require.False(t, PairingCheck([]*G1{h}, []*G2{G2Generator()}))
+}
@@ -0,0 +1,279 @@
+// Package bn254 implements the BN254 curve of herumi/mcl, CurveFp254BNb, in
+// pure Go. It is the curve of the bls0chain signature scheme, not the
+// alt_bn128 curve of Ethereum.
+//
+// Points are serialized, and messages hashed to G1, like mcl does, so that
+// keys and signatures are byte-identical to the ones of herumi/bls-go-binary.
+//
+// The arithmetic uses math/big and the scalar multiplications branch on the
+// bits of the scalar: nothing is constant time, and the time of an operation
+// on a secret scalar can leak it. Use it where cgo isn't available and timing
+// attacks aren't a concern.
+package bn254
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// fp is an element of the base field, in Montgomery form, little-endian limbs.
+type fp [4]uint64
+
+var (
+	// P is the characteristic of the base field.
+	P, _ = new(big.Int).SetString("16798108731015832284940804142231733909889187121439069848933715426072753864723", 10)
+
+	pLimbs fp
+	// pInv is -p^-1 mod 2^64.
+	pInv uint64
+	// r2 is R^2 mod p, with R = 2^256, to convert to Montgomery form.
+	r2 fp
+
+	fpOne fp
+
+	pMinus2     *big.Int
+	pPlus1Div4  *big.Int
+	pMinus1Div2 *big.Int
+	pMinus3Div4 *big.Int
+	bigOne      = big.NewInt(1)
+	twoPow256   = new(big.Int).Lsh(bigOne, 256)
+	bigMask64   = new(big.Int).SetUint64(^uint64(0))
+	fpByteSize  = 32
+)
+
+func init() {
+	pLimbs = limbsOf(P)
+
+	// Newton iteration for p^-1 mod 2^64, then negate.
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - pLimbs[0]*inv
+	}
+	pInv = -inv
+
+	r2 = limbsOf(new(big.Int).Mod(new(big.Int).Mul(twoPow256, twoPow256), P))
+	fpOne = limbsOf(new(big.Int).Mod(twoPow256, P))
+
+	pMinus2 = new(big.Int).Sub(P, big.NewInt(2))
+	pPlus1Div4 = new(big.Int).Rsh(new(big.Int).Add(P, bigOne), 2)
+	pMinus1Div2 = new(big.Int).Rsh(new(big.Int).Sub(P, bigOne), 1)
+	pMinus3Div4 = new(big.Int).Rsh(new(big.Int).Sub(P, big.NewInt(3)), 2)
+}
+
+// limbsOf returns the limbs of x, which must be less than 2^256.
+func limbsOf(x *big.Int) fp {
+	var z fp
+	t := new(big.Int).Set(x)
+	for i := range z {
+		z[i] = new(big.Int).And(t, bigMask64).Uint64()
+		t.Rsh(t, 64)
+	}
+	return z
+}
+
+func (z *fp) setBig(x *big.Int) *fp {
+	*z = limbsOf(new(big.Int).Mod(x, P))
+	return fpMul(z, z, &r2)
+}
+
+func (z *fp) setInt64(x int64) *fp {
+	return z.setBig(big.NewInt(x))
+}
+
+// big returns the value of x, out of Montgomery form.
+func (x *fp) big() *big.Int {
+	var n fp
+	fpMul(&n, x, &fp{1})
+	z := new(big.Int)
+	for i := len(n) - 1; i >= 0; i-- {
+		z.Lsh(z, 64)
+		z.Or(z, new(big.Int).SetUint64(n[i]))
+	}
+	return z
+}
+
+func (x *fp) isZero() bool {
+	return x[0]|x[1]|x[2]|x[3] == 0
+}
+
+func (x *fp) equal(y *fp) bool {
+	return *x == *y
+}
+
+// isOdd reports whether x is odd out of Montgomery form, like mcl.
+func (x *fp) isOdd() bool {
+	var n fp
+	fpMul(&n, x, &fp{1})
+	return n[0]&1 == 1
+}
+
+// setBytes sets z to the little-endian x, which must be less than p.
+func (z *fp) setBytes(buf []byte) bool {
+	x := leToBig(buf)
+	if x.Cmp(P) >= 0 {
+		return false
+	}
+	z.setBig(x)
+	return true
+}
+
+// bytes returns x little-endian, on fpByteSize bytes.
+func (x *fp) bytes() []byte {
+	return bigToLE(x.big(), fpByteSize)
+}
+
+func fpAdd(z, x, y *fp) *fp {
+	var c uint64
+	z[0], c = bits.Add64(x[0], y[0], 0)
+	z[1], c = bits.Add64(x[1], y[1], c)
+	z[2], c = bits.Add64(x[2], y[2], c)
+	z[3], _ = bits.Add64(x[3], y[3], c)
+	return fpReduce(z)
+}
+
+func fpSub(z, x, y *fp) *fp {
+	var b uint64
+	z[0], b = bits.Sub64(x[0], y[0], 0)
+	z[1], b = bits.Sub64(x[1], y[1], b)
+	z[2], b = bits.Sub64(x[2], y[2], b)
+	z[3], b = bits.Sub64(x[3], y[3], b)
+	if b != 0 {
+		var c uint64
+		z[0], c = bits.Add64(z[0], pLimbs[0], 0)
+		z[1], c = bits.Add64(z[1], pLimbs[1], c)
+		z[2], c = bits.Add64(z[2], pLimbs[2], c)
+		z[3], _ = bits.Add64(z[3], pLimbs[3], c)
+	}
+	return z
+}
+
+func fpNeg(z, x *fp) *fp {
+	if x.isZero() {
+		*z = fp{}
+		return z
+	}
+	return fpSub(z, &pLimbs, x)
+}
+
+// fpReduce subtracts p from z if z >= p. p < 2^254, so sums don't overflow.
+func fpReduce(z *fp) *fp {
+	var t fp
+	var b uint64
+	t[0], b = bits.Sub64(z[0], pLimbs[0], 0)
+	t[1], b = bits.Sub64(z[1], pLimbs[1], b)
+	t[2], b = bits.Sub64(z[2], pLimbs[2], b)
+	t[3], b = bits.Sub64(z[3], pLimbs[3], b)
+	if b == 0 {
+		*z = t
+	}
+	return z
+}
+
+// fpMul sets z to x*y/R mod p, the Montgomery product.
+func fpMul(z, x, y *fp) *fp {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var c uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[j], y[i])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		var cc uint64
+		t[4], cc = bits.Add64(t[4], c, 0)
+		t[5] = cc
+
+		m := t[0] * pInv
+		hi, lo := bits.Mul64(m, pLimbs[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < 4; j++ {
+			hi, lo := bits.Mul64(m, pLimbs[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[3], cc = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + cc
+	}
+	*z = fp{t[0], t[1], t[2], t[3]}
+	if t[4] != 0 {
+		var b uint64
+		z[0], b = bits.Sub64(z[0], pLimbs[0], 0)
+		z[1], b = bits.Sub64(z[1], pLimbs[1], b)
+		z[2], b = bits.Sub64(z[2], pLimbs[2], b)
+		z[3], _ = bits.Sub64(z[3], pLimbs[3], b)
+		return z
+	}
+	return fpReduce(z)
+}
+
+func fpSqr(z, x *fp) *fp {
+	return fpMul(z, x, x)
+}
+
+func fpExp(z, x *fp, e *big.Int) *fp {
+	r := fpOne
+	base := *x
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		fpSqr(&r, &r)
+		if e.Bit(i) == 1 {
+			fpMul(&r, &r, &base)
+		}
+	}
+	*z = r
+	return z
+}
+
+func fpInv(z, x *fp) *fp {
+	return fpExp(z, x, pMinus2)
+}
+
+// fpSqrt sets z to the square root of x chosen by mcl, x^((p+1)/4) as p = 3
+// mod 4, and reports whether x is a square.
+func fpSqrt(z, x *fp) bool {
+	var r, c fp
+	fpExp(&r, x, pPlus1Div4)
+	if !fpSqr(&c, &r).equal(x) {
+		return false
+	}
+	*z = r
+	return true
+}
+
+// fpLegendre returns 1 if x is a non-zero square, -1 if it isn't a square, 0 if
+// it is zero.
+func fpLegendre(x *fp) int {
+	var r fp
+	fpExp(&r, x, pMinus1Div2)
+	switch {
+	case r.isZero():
+		return 0
+	case r.equal(&fpOne):
+		return 1
+	default:
+		return -1
+	}
+}
+
+func leToBig(buf []byte) *big.Int {
+	be := make([]byte, len(buf))
+	for i, b := range buf {
+		be[len(buf)-1-i] = b
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+func bigToLE(x *big.Int, size int) []byte {
+	be := x.FillBytes(make([]byte, size))
+	for i, j := 0, len(be)-1; i < j; i, j = i+1, j-1 {
+		be[i], be[j] = be[j], be[i]
+	}
+	return be
+}
@@ -0,0 +1,177 @@
+package bn254
+
+import "math/big"
+
+// fp6 is c0 + c1*v + c2*v^2, with v^3 = xi.
+type fp6 struct {
+	c0, c1, c2 fp2
+}
+
+func fp6Add(z, x, y *fp6) *fp6 {
+	fp2Add(&z.c0, &x.c0, &y.c0)
+	fp2Add(&z.c1, &x.c1, &y.c1)
+	fp2Add(&z.c2, &x.c2, &y.c2)
+	return z
+}
+
+func fp6Sub(z, x, y *fp6) *fp6 {
+	fp2Sub(&z.c0, &x.c0, &y.c0)
+	fp2Sub(&z.c1, &x.c1, &y.c1)
+	fp2Sub(&z.c2, &x.c2, &y.c2)
+	return z
+}
+
+func fp6Neg(z, x *fp6) *fp6 {
+	fp2Neg(&z.c0, &x.c0)
+	fp2Neg(&z.c1, &x.c1)
+	fp2Neg(&z.c2, &x.c2)
+	return z
+}
+
+func fp6Mul(z, x, y *fp6) *fp6 {
+	var t0, t1, t2, s, u fp2
+	fp2Mul(&t0, &x.c0, &y.c0)
+	fp2Mul(&t1, &x.c1, &y.c1)
+	fp2Mul(&t2, &x.c2, &y.c2)
+
+	var c0, c1, c2 fp2
+	// c0 = t0 + xi((x1+x2)(y1+y2) - t1 - t2)
+	fp2Add(&s, &x.c1, &x.c2)
+	fp2Add(&u, &y.c1, &y.c2)
+	fp2Mul(&s, &s, &u)
+	fp2Sub(&s, &s, &t1)
+	fp2Sub(&s, &s, &t2)
+	fp2MulXi(&s, &s)
+	fp2Add(&c0, &t0, &s)
+
+	// c1 = (x0+x1)(y0+y1) - t0 - t1 + xi t2
+	fp2Add(&s, &x.c0, &x.c1)
+	fp2Add(&u, &y.c0, &y.c1)
+	fp2Mul(&s, &s, &u)
+	fp2Sub(&s, &s, &t0)
+	fp2Sub(&s, &s, &t1)
+	fp2MulXi(&u, &t2)
+	fp2Add(&c1, &s, &u)
+
+	// c2 = (x0+x2)(y0+y2) - t0 - t2 + t1
+	fp2Add(&s, &x.c0, &x.c2)
+	fp2Add(&u, &y.c0, &y.c2)
+	fp2Mul(&s, &s, &u)
+	fp2Sub(&s, &s, &t0)
+	fp2Sub(&s, &s, &t2)
+	fp2Add(&c2, &s, &t1)
+
+	z.c0, z.c1, z.c2 = c0, c1, c2
+	return z
+}
+
+// fp6MulV multiplies by v.
+func fp6MulV(z, x *fp6) *fp6 {
+	var c0 fp2
+	fp2MulXi(&c0, &x.c2)
+	z.c2 = x.c1
+	z.c1 = x.c0
+	z.c0 = c0
+	return z
+}
+
+func fp6Inv(z, x *fp6) *fp6 {
+	var a, b, c, t, f fp2
+	// a = c0^2 - xi c1 c2
+	fp2Sqr(&a, &x.c0)
+	fp2Mul(&t, &x.c1, &x.c2)
+	fp2MulXi(&t, &t)
+	fp2Sub(&a, &a, &t)
+	// b = xi c2^2 - c0 c1
+	fp2Sqr(&b, &x.c2)
+	fp2MulXi(&b, &b)
+	fp2Mul(&t, &x.c0, &x.c1)
+	fp2Sub(&b, &b, &t)
+	// c = c1^2 - c0 c2
+	fp2Sqr(&c, &x.c1)
+	fp2Mul(&t, &x.c0, &x.c2)
+	fp2Sub(&c, &c, &t)
+	// f = c0 a + xi (c2 b + c1 c)
+	fp2Mul(&f, &x.c2, &b)
+	fp2Mul(&t, &x.c1, &c)
+	fp2Add(&f, &f, &t)
+	fp2MulXi(&f, &f)
+	fp2Mul(&t, &x.c0, &a)
+	fp2Add(&f, &f, &t)
+	fp2Inv(&f, &f)
+
+	fp2Mul(&z.c0, &a, &f)
+	fp2Mul(&z.c1, &b, &f)
+	fp2Mul(&z.c2, &c, &f)
+	return z
+}
+
+// fp12 is x0 + x1*w, with w^2 = v.
+type fp12 struct {
+	x0, x1 fp6
+}
+
+func (z *fp12) setOne() *fp12 {
+	*z = fp12{}
+	z.x0.c0.setOne()
+	return z
+}
+
+func (x *fp12) isOne() bool {
+	var one fp12
+	one.setOne()
+	return *x == one
+}
+
+func fp12Mul(z, x, y *fp12) *fp12 {
+	var t0, t1, s, u fp6
+	fp6Mul(&t0, &x.x0, &y.x0)
+	fp6Mul(&t1, &x.x1, &y.x1)
+	fp6Add(&s, &x.x0, &x.x1)
+	fp6Add(&u, &y.x0, &y.x1)
+	fp6Mul(&s, &s, &u)
+	fp6Sub(&s, &s, &t0)
+	fp6Sub(&z.x1, &s, &t1)
+	fp6MulV(&t1, &t1)
+	fp6Add(&z.x0, &t0, &t1)
+	return z
+}
+
+func fp12Sqr(z, x *fp12) *fp12 {
+	return fp12Mul(z, x, x)
+}
+
+// fp12Conj sets z to x^(p^6), the conjugate of x over fp6.
+func fp12Conj(z, x *fp12) *fp12 {
+	z.x0 = x.x0
+	fp6Neg(&z.x1, &x.x1)
+	return z
+}
+
+func fp12Inv(z, x *fp12) *fp12 {
+	// 1/(x0 + x1 w) = (x0 - x1 w)/(x0^2 - x1^2 v)
+	var t0, t1 fp6
+	fp6Mul(&t0, &x.x0, &x.x0)
+	fp6Mul(&t1, &x.x1, &x.x1)
+	fp6MulV(&t1, &t1)
+	fp6Sub(&t0, &t0, &t1)
+	fp6Inv(&t0, &t0)
+	fp6Mul(&z.x0, &x.x0, &t0)
+	fp6Mul(&t0, &x.x1, &t0)
+	fp6Neg(&z.x1, &t0)
+	return z
+}
+
+func fp12Exp(z, x *fp12, e *big.Int) *fp12 {
+	var r fp12
+	r.setOne()
+	base := *x
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		fp12Sqr(&r, &r)
+		if e.Bit(i) == 1 {
+			fp12Mul(&r, &r, &base)
+		}
+	}
+	*z = r
+	return z
+}
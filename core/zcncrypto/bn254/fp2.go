@@ -0,0 +1,143 @@
+package bn254
+
+import "math/big"
+
+// fp2 is a + b*i, with i^2 = -1.
+type fp2 struct {
+	a, b fp
+}
+
+func (x *fp2) isZero() bool {
+	return x.a.isZero() && x.b.isZero()
+}
+
+func (x *fp2) equal(y *fp2) bool {
+	return x.a.equal(&y.a) && x.b.equal(&y.b)
+}
+
+func (z *fp2) setOne() *fp2 {
+	z.a, z.b = fpOne, fp{}
+	return z
+}
+
+func fp2Add(z, x, y *fp2) *fp2 {
+	fpAdd(&z.a, &x.a, &y.a)
+	fpAdd(&z.b, &x.b, &y.b)
+	return z
+}
+
+func fp2Sub(z, x, y *fp2) *fp2 {
+	fpSub(&z.a, &x.a, &y.a)
+	fpSub(&z.b, &x.b, &y.b)
+	return z
+}
+
+func fp2Neg(z, x *fp2) *fp2 {
+	fpNeg(&z.a, &x.a)
+	fpNeg(&z.b, &x.b)
+	return z
+}
+
+func fp2Conj(z, x *fp2) *fp2 {
+	z.a = x.a
+	fpNeg(&z.b, &x.b)
+	return z
+}
+
+func fp2Mul(z, x, y *fp2) *fp2 {
+	var t0, t1, s0, s1 fp
+	fpMul(&t0, &x.a, &y.a)
+	fpMul(&t1, &x.b, &y.b)
+	fpAdd(&s0, &x.a, &x.b)
+	fpAdd(&s1, &y.a, &y.b)
+	fpMul(&s0, &s0, &s1)
+	fpSub(&s0, &s0, &t0)
+	fpSub(&z.b, &s0, &t1)
+	fpSub(&z.a, &t0, &t1)
+	return z
+}
+
+func fp2MulFp(z, x *fp2, y *fp) *fp2 {
+	fpMul(&z.a, &x.a, y)
+	fpMul(&z.b, &x.b, y)
+	return z
+}
+
+func fp2Sqr(z, x *fp2) *fp2 {
+	var s, d, ab fp
+	fpAdd(&s, &x.a, &x.b)
+	fpSub(&d, &x.a, &x.b)
+	fpMul(&ab, &x.a, &x.b)
+	fpMul(&z.a, &s, &d)
+	fpAdd(&z.b, &ab, &ab)
+	return z
+}
+
+// fp2MulXi multiplies by xi = 1 + i, the non-residue of the tower.
+func fp2MulXi(z, x *fp2) *fp2 {
+	var a, b fp
+	fpSub(&a, &x.a, &x.b)
+	fpAdd(&b, &x.a, &x.b)
+	z.a, z.b = a, b
+	return z
+}
+
+func fp2Inv(z, x *fp2) *fp2 {
+	var t, u fp
+	fpSqr(&t, &x.a)
+	fpSqr(&u, &x.b)
+	fpAdd(&t, &t, &u)
+	fpInv(&t, &t)
+	fpMul(&z.a, &x.a, &t)
+	fpMul(&t, &x.b, &t)
+	fpNeg(&z.b, &t)
+	return z
+}
+
+// fp2Sqrt sets z to a square root of x and reports whether x is a square, with
+// the algorithm 9 of "Square root computation over even extension fields",
+// Adj and Rodríguez-Henríquez, for p = 3 mod 4.
+func fp2Sqrt(z, x *fp2) bool {
+	if x.isZero() {
+		*z = fp2{}
+		return true
+	}
+	var a1, alpha, x0, c fp2
+	fp2Exp(&a1, x, pMinus3Div4)
+	fp2Mul(&x0, &a1, x)
+	fp2Mul(&alpha, &a1, &x0)
+
+	var minusOne fp2
+	fp2Neg(&minusOne, new(fp2).setOne())
+	if alpha.equal(&minusOne) {
+		// x0 * i
+		var r fp2
+		fpNeg(&r.a, &x0.b)
+		r.b = x0.a
+		x0 = r
+	} else {
+		var b fp2
+		fp2Add(&b, &alpha, new(fp2).setOne())
+		fp2Exp(&b, &b, pMinus1Div2)
+		fp2Mul(&x0, &b, &x0)
+	}
+	if !fp2Sqr(&c, &x0).equal(x) {
+		return false
+	}
+	*z = x0
+	return true
+}
+
+func fp2Exp(z, x *fp2, e *big.Int) *fp2 {
+	var r fp2
+	r.setOne()
+	base := *x
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		fp2Sqr(&r, &r)
+		if e.Bit(i) == 1 {
+			fp2Mul(&r, &r, &base)
+		}
+	}
+	*z = r
+	return z
+}
@@ -0,0 +1,341 @@
+package bn254
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// G1Size is the size of a serialized point of G1.
+const G1Size = 32
+
+var (
+	// Order is the order of G1 and G2, the modulus of the scalars.
+	Order, _ = new(big.Int).SetString("16798108731015832284940804142231733909759579603404752749028378864165570215949", 10)
+
+	errInvalidPoint = errors.New("bn254: invalid point")
+
+	curveB fp
+	// sqrt(-3) and (sqrt(-3) - 1)/2, to hash to the curve.
+	mapC1, mapC2 fp
+)
+
+func init() {
+	curveB.setInt64(2)
+
+	var minus3 fp
+	minus3.setInt64(-3)
+	if !fpSqrt(&mapC1, &minus3) {
+		panic("bn254: -3 is not a square")
+	}
+	var half fp
+	half.setInt64(2)
+	fpInv(&half, &half)
+	fpSub(&mapC2, &mapC1, &fpOne)
+	fpMul(&mapC2, &mapC2, &half)
+}
+
+// G1 is a point of y^2 = x^3 + 2 over the base field, in Jacobian
+// coordinates. The zero value is the point at infinity.
+type G1 struct {
+	x, y, z fp
+}
+
+// IsZero reports whether p is the point at infinity.
+func (p *G1) IsZero() bool {
+	return p.z.isZero()
+}
+
+// Equal reports whether p and q are the same point.
+func (p *G1) Equal(q *G1) bool {
+	if p.IsZero() || q.IsZero() {
+		return p.IsZero() == q.IsZero()
+	}
+	// x1 z2^2 == x2 z1^2 and y1 z2^3 == y2 z1^3
+	var z1z1, z2z2, u1, u2, s1, s2 fp
+	fpSqr(&z1z1, &p.z)
+	fpSqr(&z2z2, &q.z)
+	fpMul(&u1, &p.x, &z2z2)
+	fpMul(&u2, &q.x, &z1z1)
+	fpMul(&s1, &p.y, &z2z2)
+	fpMul(&s1, &s1, &q.z)
+	fpMul(&s2, &q.y, &z1z1)
+	fpMul(&s2, &s2, &p.z)
+	return u1.equal(&u2) && s1.equal(&s2)
+}
+
+func (p *G1) setAffine(x, y *fp) *G1 {
+	p.x, p.y, p.z = *x, *y, fpOne
+	return p
+}
+
+// affine returns the affine coordinates of p, which must not be zero.
+func (p *G1) affine() (x, y fp) {
+	var zi, zi2 fp
+	fpInv(&zi, &p.z)
+	fpSqr(&zi2, &zi)
+	fpMul(&x, &p.x, &zi2)
+	fpMul(&zi2, &zi2, &zi)
+	fpMul(&y, &p.y, &zi2)
+	return
+}
+
+// isOnCurve reports whether the affine point (x, y) is on the curve.
+func g1IsOnCurve(x, y *fp) bool {
+	var l, r fp
+	fpSqr(&l, y)
+	g1Weierstrass(&r, x)
+	return l.equal(&r)
+}
+
+// g1Weierstrass sets z to x^3 + b.
+func g1Weierstrass(z, x *fp) *fp {
+	var t fp
+	fpSqr(&t, x)
+	fpMul(&t, &t, x)
+	return fpAdd(z, &t, &curveB)
+}
+
+// Neg sets p to -q.
+func (p *G1) Neg(q *G1) *G1 {
+	p.x = q.x
+	fpNeg(&p.y, &q.y)
+	p.z = q.z
+	return p
+}
+
+// Double sets p to 2q.
+func (p *G1) Double(q *G1) *G1 {
+	if q.IsZero() {
+		*p = G1{}
+		return p
+	}
+	var a, b, c, d, e, f, t fp
+	fpSqr(&a, &q.x)
+	fpSqr(&b, &q.y)
+	fpSqr(&c, &b)
+	fpAdd(&d, &q.x, &b)
+	fpSqr(&d, &d)
+	fpSub(&d, &d, &a)
+	fpSub(&d, &d, &c)
+	fpAdd(&d, &d, &d)
+	fpAdd(&e, &a, &a)
+	fpAdd(&e, &e, &a)
+	fpSqr(&f, &e)
+
+	var x3, y3, z3 fp
+	fpSub(&x3, &f, &d)
+	fpSub(&x3, &x3, &d)
+	fpSub(&t, &d, &x3)
+	fpMul(&y3, &e, &t)
+	fpAdd(&c, &c, &c)
+	fpAdd(&c, &c, &c)
+	fpAdd(&c, &c, &c)
+	fpSub(&y3, &y3, &c)
+	fpMul(&z3, &q.y, &q.z)
+	fpAdd(&z3, &z3, &z3)
+	p.x, p.y, p.z = x3, y3, z3
+	return p
+}
+
+// Add sets p to a + b.
+func (p *G1) Add(a, b *G1) *G1 {
+	if a.IsZero() {
+		*p = *b
+		return p
+	}
+	if b.IsZero() {
+		*p = *a
+		return p
+	}
+	var z1z1, z2z2, u1, u2, s1, s2, h, i, j, r, v, t fp
+	fpSqr(&z1z1, &a.z)
+	fpSqr(&z2z2, &b.z)
+	fpMul(&u1, &a.x, &z2z2)
+	fpMul(&u2, &b.x, &z1z1)
+	fpMul(&s1, &a.y, &b.z)
+	fpMul(&s1, &s1, &z2z2)
+	fpMul(&s2, &b.y, &a.z)
+	fpMul(&s2, &s2, &z1z1)
+	fpSub(&h, &u2, &u1)
+	fpSub(&r, &s2, &s1)
+	if h.isZero() {
+		if r.isZero() {
+			return p.Double(a)
+		}
+		*p = G1{}
+		return p
+	}
+	fpAdd(&i, &h, &h)
+	fpSqr(&i, &i)
+	fpMul(&j, &h, &i)
+	fpAdd(&r, &r, &r)
+	fpMul(&v, &u1, &i)
+
+	var x3, y3, z3 fp
+	fpSqr(&x3, &r)
+	fpSub(&x3, &x3, &j)
+	fpSub(&x3, &x3, &v)
+	fpSub(&x3, &x3, &v)
+	fpSub(&t, &v, &x3)
+	fpMul(&y3, &r, &t)
+	fpMul(&t, &s1, &j)
+	fpAdd(&t, &t, &t)
+	fpSub(&y3, &y3, &t)
+	fpAdd(&z3, &a.z, &b.z)
+	fpSqr(&z3, &z3)
+	fpSub(&z3, &z3, &z1z1)
+	fpSub(&z3, &z3, &z2z2)
+	fpMul(&z3, &z3, &h)
+	p.x, p.y, p.z = x3, y3, z3
+	return p
+}
+
+// ScalarMult sets p to k*q. It isn't constant time.
+func (p *G1) ScalarMult(q *G1, k *big.Int) *G1 {
+	var r G1
+	base := *q
+	if k.Sign() < 0 {
+		base.Neg(&base)
+		k = new(big.Int).Neg(k)
+	}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		r.Double(&r)
+		if k.Bit(i) == 1 {
+			r.Add(&r, &base)
+		}
+	}
+	*p = r
+	return p
+}
+
+// Marshal serializes p like mcl: x little-endian, with the top bit of the last
+// byte set if y is odd. The point at infinity is all zeros.
+func (p *G1) Marshal() []byte {
+	if p.IsZero() {
+		return make([]byte, G1Size)
+	}
+	x, y := p.affine()
+	buf := x.bytes()
+	if y.isOdd() {
+		buf[G1Size-1] |= 0x80
+	}
+	return buf
+}
+
+// Unmarshal sets p to the point serialized by Marshal.
+func (p *G1) Unmarshal(buf []byte) error {
+	if len(buf) != G1Size {
+		return errInvalidPoint
+	}
+	if isZeroBytes(buf) {
+		*p = G1{}
+		return nil
+	}
+	b := append([]byte(nil), buf...)
+	odd := b[G1Size-1]&0x80 != 0
+	b[G1Size-1] &^= 0x80
+
+	var x, y fp
+	if !x.setBytes(b) {
+		return errInvalidPoint
+	}
+	g1Weierstrass(&y, &x)
+	if !fpSqrt(&y, &y) {
+		return errInvalidPoint
+	}
+	if y.isOdd() != odd {
+		fpNeg(&y, &y)
+	}
+	// the cofactor of G1 is 1, any point of the curve is in G1
+	p.setAffine(&x, &y)
+	return nil
+}
+
+// HashToG1 hashes msg to a point of G1 like mcl does for the bls0chain scheme:
+// the sha256 of msg is mapped to the curve with the method of Fouque and
+// Tibouchi, "Indifferentiable hashing to Barreto-Naehrig curves".
+func HashToG1(msg []byte) (*G1, error) {
+	sum := sha256.Sum256(msg)
+	var t fp
+	t.setMasked(sum[:])
+	return mapToG1(&t)
+}
+
+// setMasked sets z to the little-endian buf masked to the size of p, and to one
+// bit less if still not less than p, like mcl's setArrayMask.
+func (z *fp) setMasked(buf []byte) *fp {
+	*z = limbsOf(maskLE(buf, P))
+	return fpMul(z, z, &r2)
+}
+
+// maskLE returns the little-endian buf truncated to the bit length of m, and to
+// one bit less if still not less than m.
+func maskLE(buf []byte, m *big.Int) *big.Int {
+	size := (m.BitLen() + 7) / 8
+	if len(buf) > size {
+		buf = buf[:size]
+	}
+	x := leToBig(buf)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(bigOne, uint(m.BitLen())), bigOne)
+	x.And(x, mask)
+	if x.Cmp(m) >= 0 {
+		x.And(x, mask.Rsh(mask, 1))
+	}
+	return x
+}
+
+func mapToG1(t *fp) (*G1, error) {
+	negative := fpLegendre(t) < 0
+	if t.isZero() {
+		return nil, errInvalidPoint
+	}
+
+	// w = sqrt(-3) t / (1 + b + t^2)
+	var w fp
+	fpSqr(&w, t)
+	fpAdd(&w, &w, &curveB)
+	fpAdd(&w, &w, &fpOne)
+	if w.isZero() {
+		return nil, errInvalidPoint
+	}
+	fpInv(&w, &w)
+	fpMul(&w, &w, &mapC1)
+	fpMul(&w, &w, t)
+
+	var x, y fp
+	for i := 0; i < 3; i++ {
+		switch i {
+		case 0:
+			// x = c2 - t w
+			fpMul(&x, t, &w)
+			fpSub(&x, &mapC2, &x)
+		case 1:
+			// x = -1 - x
+			fpNeg(&x, &x)
+			fpSub(&x, &x, &fpOne)
+		case 2:
+			// x = 1 + 1/w^2
+			fpSqr(&x, &w)
+			fpInv(&x, &x)
+			fpAdd(&x, &x, &fpOne)
+		}
+		g1Weierstrass(&y, &x)
+		if fpSqrt(&y, &y) {
+			if negative {
+				fpNeg(&y, &y)
+			}
+			return new(G1).setAffine(&x, &y), nil
+		}
+	}
+	return nil, errInvalidPoint
+}
+
+func isZeroBytes(buf []byte) bool {
+	for _, b := range buf {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,262 @@
+package bn254
+
+import (
+	"math/big"
+)
+
+// G2Size is the size of a serialized point of G2.
+const G2Size = 2 * G1Size
+
+var (
+	// twistB is b/xi, the constant of the twist y^2 = x^3 + b/xi.
+	twistB fp2
+
+	g2Gen G2
+)
+
+func init() {
+	var xi fp2
+	xi.a, xi.b = fpOne, fpOne
+	fp2Inv(&twistB, &xi)
+	fp2MulFp(&twistB, &twistB, &curveB)
+
+	// the generator of the bls0chain scheme, mcl's mapToG2(1)
+	var x, y fp2
+	x.a.setBig(hexBig("11ccb44e77ac2c5dc32a6009594dbe331ec85a61290d6bbac8cc7ebb2dceb128"))
+	x.b.setBig(hexBig("0f204a14bbdac4a05be9a25176de827f2e60085668becdd4fc5fa914c9ee0d9a"))
+	y.a.setBig(hexBig("07c13d8487903ee3c1c5ea327a3a52b6cc74796b1760d5ba20ed802624ed19c8"))
+	y.b.setBig(hexBig("008f9642bbaacb73d8c89492528f58932f2de9ac3e80c7b0e41f1a84f1c40182"))
+	g2Gen.setAffine(&x, &y)
+}
+
+func hexBig(s string) *big.Int {
+	x, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("bn254: invalid constant " + s)
+	}
+	return x
+}
+
+// G2 is a point of the twist y^2 = x^3 + 2/(1+i) over fp2, in Jacobian
+// coordinates. The zero value is the point at infinity.
+type G2 struct {
+	x, y, z fp2
+}
+
+// G2Generator returns the generator of G2 the public keys of the bls0chain
+// scheme are multiples of.
+func G2Generator() *G2 {
+	g := g2Gen
+	return &g
+}
+
+// IsZero reports whether p is the point at infinity.
+func (p *G2) IsZero() bool {
+	return p.z.isZero()
+}
+
+// Equal reports whether p and q are the same point.
+func (p *G2) Equal(q *G2) bool {
+	if p.IsZero() || q.IsZero() {
+		return p.IsZero() == q.IsZero()
+	}
+	var z1z1, z2z2, u1, u2, s1, s2 fp2
+	fp2Sqr(&z1z1, &p.z)
+	fp2Sqr(&z2z2, &q.z)
+	fp2Mul(&u1, &p.x, &z2z2)
+	fp2Mul(&u2, &q.x, &z1z1)
+	fp2Mul(&s1, &p.y, &z2z2)
+	fp2Mul(&s1, &s1, &q.z)
+	fp2Mul(&s2, &q.y, &z1z1)
+	fp2Mul(&s2, &s2, &p.z)
+	return u1.equal(&u2) && s1.equal(&s2)
+}
+
+func (p *G2) setAffine(x, y *fp2) *G2 {
+	p.x, p.y = *x, *y
+	p.z.setOne()
+	return p
+}
+
+// affine returns the affine coordinates of p, which must not be zero.
+func (p *G2) affine() (x, y fp2) {
+	var zi, zi2 fp2
+	fp2Inv(&zi, &p.z)
+	fp2Sqr(&zi2, &zi)
+	fp2Mul(&x, &p.x, &zi2)
+	fp2Mul(&zi2, &zi2, &zi)
+	fp2Mul(&y, &p.y, &zi2)
+	return
+}
+
+// g2Weierstrass sets z to x^3 + b/xi.
+func g2Weierstrass(z, x *fp2) *fp2 {
+	var t fp2
+	fp2Sqr(&t, x)
+	fp2Mul(&t, &t, x)
+	return fp2Add(z, &t, &twistB)
+}
+
+// Neg sets p to -q.
+func (p *G2) Neg(q *G2) *G2 {
+	p.x = q.x
+	fp2Neg(&p.y, &q.y)
+	p.z = q.z
+	return p
+}
+
+// Double sets p to 2q.
+func (p *G2) Double(q *G2) *G2 {
+	if q.IsZero() {
+		*p = G2{}
+		return p
+	}
+	var a, b, c, d, e, f, t fp2
+	fp2Sqr(&a, &q.x)
+	fp2Sqr(&b, &q.y)
+	fp2Sqr(&c, &b)
+	fp2Add(&d, &q.x, &b)
+	fp2Sqr(&d, &d)
+	fp2Sub(&d, &d, &a)
+	fp2Sub(&d, &d, &c)
+	fp2Add(&d, &d, &d)
+	fp2Add(&e, &a, &a)
+	fp2Add(&e, &e, &a)
+	fp2Sqr(&f, &e)
+
+	var x3, y3, z3 fp2
+	fp2Sub(&x3, &f, &d)
+	fp2Sub(&x3, &x3, &d)
+	fp2Sub(&t, &d, &x3)
+	fp2Mul(&y3, &e, &t)
+	fp2Add(&c, &c, &c)
+	fp2Add(&c, &c, &c)
+	fp2Add(&c, &c, &c)
+	fp2Sub(&y3, &y3, &c)
+	fp2Mul(&z3, &q.y, &q.z)
+	fp2Add(&z3, &z3, &z3)
+	p.x, p.y, p.z = x3, y3, z3
+	return p
+}
+
+// Add sets p to a + b.
+func (p *G2) Add(a, b *G2) *G2 {
+	if a.IsZero() {
+		*p = *b
+		return p
+	}
+	if b.IsZero() {
+		*p = *a
+		return p
+	}
+	var z1z1, z2z2, u1, u2, s1, s2, h, i, j, r, v, t fp2
+	fp2Sqr(&z1z1, &a.z)
+	fp2Sqr(&z2z2, &b.z)
+	fp2Mul(&u1, &a.x, &z2z2)
+	fp2Mul(&u2, &b.x, &z1z1)
+	fp2Mul(&s1, &a.y, &b.z)
+	fp2Mul(&s1, &s1, &z2z2)
+	fp2Mul(&s2, &b.y, &a.z)
+	fp2Mul(&s2, &s2, &z1z1)
+	fp2Sub(&h, &u2, &u1)
+	fp2Sub(&r, &s2, &s1)
+	if h.isZero() {
+		if r.isZero() {
+			return p.Double(a)
+		}
+		*p = G2{}
+		return p
+	}
+	fp2Add(&i, &h, &h)
+	fp2Sqr(&i, &i)
+	fp2Mul(&j, &h, &i)
+	fp2Add(&r, &r, &r)
+	fp2Mul(&v, &u1, &i)
+
+	var x3, y3, z3 fp2
+	fp2Sqr(&x3, &r)
+	fp2Sub(&x3, &x3, &j)
+	fp2Sub(&x3, &x3, &v)
+	fp2Sub(&x3, &x3, &v)
+	fp2Sub(&t, &v, &x3)
+	fp2Mul(&y3, &r, &t)
+	fp2Mul(&t, &s1, &j)
+	fp2Add(&t, &t, &t)
+	fp2Sub(&y3, &y3, &t)
+	fp2Add(&z3, &a.z, &b.z)
+	fp2Sqr(&z3, &z3)
+	fp2Sub(&z3, &z3, &z1z1)
+	fp2Sub(&z3, &z3, &z2z2)
+	fp2Mul(&z3, &z3, &h)
+	p.x, p.y, p.z = x3, y3, z3
+	return p
+}
+
+// ScalarMult sets p to k*q. It isn't constant time.
+func (p *G2) ScalarMult(q *G2, k *big.Int) *G2 {
+	var r G2
+	base := *q
+	if k.Sign() < 0 {
+		base.Neg(&base)
+		k = new(big.Int).Neg(k)
+	}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		r.Double(&r)
+		if k.Bit(i) == 1 {
+			r.Add(&r, &base)
+		}
+	}
+	*p = r
+	return p
+}
+
+// Marshal serializes p like mcl: x.a then x.b little-endian, with the top bit
+// of the last byte set if y.a is odd. The point at infinity is all zeros.
+func (p *G2) Marshal() []byte {
+	buf := make([]byte, G2Size)
+	if p.IsZero() {
+		return buf
+	}
+	x, y := p.affine()
+	copy(buf, x.a.bytes())
+	copy(buf[G1Size:], x.b.bytes())
+	if y.a.isOdd() {
+		buf[G2Size-1] |= 0x80
+	}
+	return buf
+}
+
+// Unmarshal sets p to the point serialized by Marshal. The point must be in
+// G2, not only on the twist.
+func (p *G2) Unmarshal(buf []byte) error {
+	if len(buf) != G2Size {
+		return errInvalidPoint
+	}
+	if isZeroBytes(buf) {
+		*p = G2{}
+		return nil
+	}
+	b := append([]byte(nil), buf...)
+	odd := b[G2Size-1]&0x80 != 0
+	b[G2Size-1] &^= 0x80
+
+	var x, y fp2
+	if !x.a.setBytes(b[:G1Size]) || !x.b.setBytes(b[G1Size:]) {
+		return errInvalidPoint
+	}
+	g2Weierstrass(&y, &x)
+	if !fp2Sqrt(&y, &y) {
+		return errInvalidPoint
+	}
+	if y.a.isOdd() != odd {
+		fp2Neg(&y, &y)
+	}
+
+	var q, check G2
+	q.setAffine(&x, &y)
+	if !check.ScalarMult(&q, Order).IsZero() {
+		return errInvalidPoint
+	}
+	*p = q
+	return nil
+}
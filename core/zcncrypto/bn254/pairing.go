@@ -0,0 +1,133 @@
+package bn254
+
+import "math/big"
+
+var (
+	// curveU is the parameter of the curve: p = 36u^4 + 36u^3 + 24u^2 + 6u + 1.
+	curveU = new(big.Int).Neg(new(big.Int).SetUint64(1<<62 + 1<<55 + 1))
+
+	// ateLoop is t - 1 = 6u^2, the length of the Miller loop of the ate
+	// pairing.
+	ateLoop *big.Int
+
+	// finalExp is (p^2 + 1)(p^4 - p^2 + 1)/r, the exponent of the final
+	// exponentiation after the easy p^6 - 1 part.
+	finalExp *big.Int
+)
+
+func init() {
+	ateLoop = new(big.Int).Mul(curveU, curveU)
+	ateLoop.Mul(ateLoop, big.NewInt(6))
+
+	p2 := new(big.Int).Mul(P, P)
+	p4 := new(big.Int).Mul(p2, p2)
+	hard := new(big.Int).Sub(p4, p2)
+	hard.Add(hard, bigOne)
+	hard, rem := hard.QuoRem(hard, Order, new(big.Int))
+	if rem.Sign() != 0 {
+		panic("bn254: r doesn't divide p^4 - p^2 + 1")
+	}
+	finalExp = new(big.Int).Add(p2, bigOne)
+	finalExp.Mul(finalExp, hard)
+}
+
+// PairingCheck reports whether the product of the pairings e(g1[i], g2[i]) is
+// one, e(a, b) == e(c, d) being PairingCheck([a, -c], [b, d]).
+func PairingCheck(g1 []*G1, g2 []*G2) bool {
+	if len(g1) != len(g2) {
+		return false
+	}
+	var f fp12
+	f.setOne()
+	for i := range g1 {
+		if g1[i].IsZero() || g2[i].IsZero() {
+			continue
+		}
+		m, ok := millerLoop(g2[i], g1[i])
+		if !ok {
+			return false
+		}
+		fp12Mul(&f, &f, &m)
+	}
+	finalExponentiation(&f, &f)
+	return f.isOne()
+}
+
+// millerLoop computes f_{t-1,q}(p) of the ate pairing, with affine coordinates
+// on the twist. It fails only for points of an order less than r.
+func millerLoop(q *G2, p *G1) (fp12, bool) {
+	xq, yq := q.affine()
+	xp, yp := p.affine()
+
+	var f, l fp12
+	f.setOne()
+	tx, ty := xq, yq
+	var lambda, num, den, t fp2
+	for i := ateLoop.BitLen() - 2; i >= 0; i-- {
+		// lambda = 3 tx^2 / 2 ty
+		if ty.isZero() {
+			return f, false
+		}
+		fp2Sqr(&num, &tx)
+		fp2Add(&t, &num, &num)
+		fp2Add(&num, &t, &num)
+		fp2Add(&den, &ty, &ty)
+		fp2Inv(&den, &den)
+		fp2Mul(&lambda, &num, &den)
+
+		lineEval(&l, &lambda, &tx, &ty, &xp, &yp)
+		fp12Sqr(&f, &f)
+		fp12Mul(&f, &f, &l)
+		tx, ty = lineStep(&lambda, &tx, &ty, &tx)
+
+		if ateLoop.Bit(i) == 1 {
+			// lambda = (yq - ty) / (xq - tx)
+			fp2Sub(&den, &xq, &tx)
+			if den.isZero() {
+				return f, false
+			}
+			fp2Inv(&den, &den)
+			fp2Sub(&num, &yq, &ty)
+			fp2Mul(&lambda, &num, &den)
+
+			lineEval(&l, &lambda, &tx, &ty, &xp, &yp)
+			fp12Mul(&f, &f, &l)
+			tx, ty = lineStep(&lambda, &tx, &ty, &xq)
+		}
+	}
+	return f, true
+}
+
+// lineEval sets l to the line of slope lambda through the untwisted (tx, ty),
+// evaluated at (xp, yp). With the untwist (x, y) -> (x w^2, y w^3), it is
+// yp - lambda xp w + (lambda tx - ty) w^3.
+func lineEval(l *fp12, lambda, tx, ty *fp2, xp, yp *fp) {
+	*l = fp12{}
+	l.x0.c0.a = *yp
+	fp2MulFp(&l.x1.c0, lambda, xp)
+	fp2Neg(&l.x1.c0, &l.x1.c0)
+	fp2Mul(&l.x1.c1, lambda, tx)
+	fp2Sub(&l.x1.c1, &l.x1.c1, ty)
+}
+
+// lineStep returns the third point of the line of slope lambda through (tx, ty)
+// and a point of abscissa x2, negated.
+func lineStep(lambda, tx, ty, x2 *fp2) (x3, y3 fp2) {
+	var t fp2
+	fp2Sqr(&x3, lambda)
+	fp2Sub(&x3, &x3, tx)
+	fp2Sub(&x3, &x3, x2)
+	fp2Sub(&t, tx, &x3)
+	fp2Mul(&y3, lambda, &t)
+	fp2Sub(&y3, &y3, ty)
+	return
+}
+
+// finalExponentiation sets z to x^((p^12 - 1)/r).
+func finalExponentiation(z, x *fp12) *fp12 {
+	var t fp12
+	fp12Inv(&t, x)
+	fp12Conj(z, x)
+	fp12Mul(z, z, &t)
+	return fp12Exp(z, z, finalExp)
+}
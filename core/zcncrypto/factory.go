@@ -111,3 +111,17 @@ func GenerateThresholdKeyShares(t, n int, originalKey SignatureScheme) ([]Signat
 
 	return shares, nil
 }
+
+// RecoverThresholdSignature recovers the signature of the original key from the
+// signatures of at least t of its key shares, see GenerateThresholdKeyShares.
+// ids are the ids of the shares, in hex, in the order of the signatures.
+func RecoverThresholdSignature(ids, signatures []string) (string, error) {
+	if len(ids) == 0 || len(ids) != len(signatures) {
+		return "", errors.New("recover_threshold_signature", "one id per signature is required")
+	}
+	sig, err := recoverThresholdSignature(ids, signatures)
+	if err != nil {
+		return "", errors.Wrap(err, "recover_threshold_signature")
+	}
+	return sig, nil
+}
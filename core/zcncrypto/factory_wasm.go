@@ -63,3 +63,9 @@ func GenerateThresholdKeyShares(t, n int, originalKey SignatureScheme) ([]Signat
 
 	return nil, errors.New("wasm_not_supported", "GenerateThresholdKeyShares")
 }
+
+// RecoverThresholdSignature recovers the signature of the original key from the
+// signatures of at least t of its key shares.
+func RecoverThresholdSignature(ids, signatures []string) (string, error) {
+	return "", errors.New("wasm_not_supported", "RecoverThresholdSignature")
+}
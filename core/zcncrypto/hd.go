@@ -0,0 +1,185 @@
+package zcncrypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/encryption"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/ed25519"
+)
+
+// HardenedKeyStart is the index of the first hardened child key.
+const HardenedKeyStart uint32 = 0x80000000
+
+// ZCNCoinType is the coin type of 0chain keys in derivation paths.
+const ZCNCoinType uint32 = 36363
+
+// DefaultBaseDerivationPath is the path of the accounts derived by HDWallet:
+// the account i is at m/44'/36363'/0'/0'/i'.
+var DefaultBaseDerivationPath = DerivationPath{
+	HardenedKeyStart + 44,
+	HardenedKeyStart + ZCNCoinType,
+	HardenedKeyStart,
+	HardenedKeyStart,
+}
+
+// DerivationPath is the path of a child key from the master key of a seed.
+// Only hardened derivation is supported, by both schemes.
+type DerivationPath []uint32
+
+// ParseDerivationPath parses a path like m/44'/36363'/0'/0'/1', hardened
+// components are marked with ' or h.
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	components := strings.Split(strings.TrimSpace(path), "/")
+	if len(components) == 0 || components[0] != "m" {
+		return nil, errors.New("parse_derivation_path", "the path must start with m: "+path)
+	}
+
+	var p DerivationPath
+	for _, c := range components[1:] {
+		c = strings.TrimSpace(c)
+		hardened := strings.HasSuffix(c, "'") || strings.HasSuffix(c, "h")
+		if !hardened {
+			return nil, errors.New("parse_derivation_path", "only hardened derivation is supported: "+path)
+		}
+		index, err := strconv.ParseUint(c[:len(c)-1], 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, errors.New("parse_derivation_path", "invalid component "+c+": "+path)
+		}
+		p = append(p, HardenedKeyStart+uint32(index))
+	}
+	return p, nil
+}
+
+// String returns the path in the format parsed by ParseDerivationPath.
+func (p DerivationPath) String() string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, index := range p {
+		fmt.Fprintf(&sb, "/%d'", index-HardenedKeyStart)
+	}
+	return sb.String()
+}
+
+// HDWallet derives many wallets from one mnemonic. The derivation follows
+// SLIP-0010: for ed25519 the keys are the ones of any SLIP-0010 wallet, for
+// bls0chain the key material of a child is used as a BLS secret key.
+//
+//	hd, _ := NewHDWallet("bls0chain", mnemonic, "")
+//	w0, _ := hd.Account(0)
+//	w1, _ := hd.Account(1)
+type HDWallet struct {
+	scheme string
+	master hdKey
+}
+
+type hdKey struct {
+	key       []byte
+	chainCode []byte
+}
+
+// NewHDWallet creates the HDWallet of the mnemonic, protected by the optional
+// password.
+func NewHDWallet(scheme, mnemonic, password string) (*HDWallet, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.New("hd_wallet", "invalid mnemonic")
+	}
+	return newHDWalletFromSeed(scheme, bip39.NewSeed(mnemonic, password))
+}
+
+func newHDWalletFromSeed(scheme string, seed []byte) (*HDWallet, error) {
+	var curve string
+	switch scheme {
+	case "ed25519":
+		curve = "ed25519 seed"
+	case "bls0chain":
+		curve = "0chain bls0chain seed"
+	default:
+		return nil, errors.New("hd_wallet", "unknown signature scheme: "+scheme)
+	}
+
+	mac := hmac.New(sha512.New, []byte(curve))
+	mac.Write(seed) //nolint: errcheck
+	sum := mac.Sum(nil)
+	return &HDWallet{
+		scheme: scheme,
+		master: hdKey{key: sum[:32], chainCode: sum[32:]},
+	}, nil
+}
+
+// AccountPath returns the derivation path of the account at index, under
+// DefaultBaseDerivationPath.
+func AccountPath(index uint32) DerivationPath {
+	path := make(DerivationPath, len(DefaultBaseDerivationPath), len(DefaultBaseDerivationPath)+1)
+	copy(path, DefaultBaseDerivationPath)
+	return append(path, HardenedKeyStart+index)
+}
+
+// Account derives the wallet of the account at AccountPath(index).
+func (hd *HDWallet) Account(index uint32) (*Wallet, error) {
+	if index >= HardenedKeyStart {
+		return nil, errors.New("hd_wallet", "invalid account index")
+	}
+	return hd.Derive(AccountPath(index))
+}
+
+// Derive derives the wallet at path.
+func (hd *HDWallet) Derive(path DerivationPath) (*Wallet, error) {
+	k := hd.master
+	for _, index := range path {
+		if index < HardenedKeyStart {
+			return nil, errors.New("hd_wallet", "only hardened derivation is supported")
+		}
+		k = k.child(index)
+	}
+
+	w := &Wallet{
+		Keys:        make([]KeyPair, 1),
+		Version:     CryptoVersion,
+		DateCreated: time.Now().Format(time.RFC3339),
+	}
+	var publicKey []byte
+	switch hd.scheme {
+	case "ed25519":
+		private := ed25519.NewKeyFromSeed(k.key)
+		public := private.Public().(ed25519.PublicKey)
+		w.Keys[0].PrivateKey = hex.EncodeToString(private)
+		w.Keys[0].PublicKey = hex.EncodeToString(public)
+		publicKey = public
+	case "bls0chain":
+		if BlsSignerInstance == nil {
+			return nil, errors.New("hd_wallet", "bls is not available")
+		}
+		sk := BlsSignerInstance.NewSecretKey()
+		if err := sk.SetLittleEndian(k.key); err != nil {
+			return nil, errors.Wrap(err, "hd_wallet: invalid child key")
+		}
+		pub := sk.GetPublicKey()
+		w.Keys[0].PrivateKey = sk.SerializeToHexStr()
+		w.Keys[0].PublicKey = pub.SerializeToHexStr()
+		publicKey = pub.Serialize()
+	}
+	w.ClientKey = w.Keys[0].PublicKey
+	w.ClientID = encryption.Hash(publicKey)
+	return w, nil
+}
+
+// child derives the hardened child key at index.
+func (k hdKey) child(index uint32) hdKey {
+	data := make([]byte, 1+len(k.key)+4)
+	copy(data[1:], k.key)
+	binary.BigEndian.PutUint32(data[1+len(k.key):], index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data) //nolint: errcheck
+	sum := mac.Sum(nil)
+	return hdKey{key: sum[:32], chainCode: sum[32:]}
+}
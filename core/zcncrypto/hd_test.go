@@ -0,0 +1,102 @@
+package zcncrypto
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDerivationPath(t *testing.T) {
+	p, err := ParseDerivationPath("m/44'/36363'/0h/1'")
+	require.NoError(t, err)
+	require.Equal(t, DerivationPath{HardenedKeyStart + 44, HardenedKeyStart + 36363, HardenedKeyStart, HardenedKeyStart + 1}, p)
+	require.Equal(t, "m/44'/36363'/0'/1'", p.String())
+
+	for _, path := range []string{"", "44'/0'", "m/44'/0", "m/x'", "m/2147483648'"} {
+		_, err := ParseDerivationPath(path)
+		require.Error(t, err, path)
+	}
+}
+
+// SLIP-0010 test vector 1 for ed25519
+func TestHDWalletSLIP10(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	hd, err := newHDWalletFromSeed("ed25519", seed)
+	require.NoError(t, err)
+	require.Equal(t, "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7", hex.EncodeToString(hd.master.key))
+
+	p, err := ParseDerivationPath("m/0'/1'")
+	require.NoError(t, err)
+	w, err := hd.Derive(p)
+	require.NoError(t, err)
+	require.Equal(t, "1932a5270f335bed617d5b935c80aedb1a35bd9fc1e31acafd5372c30f5c1187", w.Keys[0].PublicKey[:64])
+	require.Equal(t, "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2", w.Keys[0].PrivateKey[:64])
+}
+
+// fixed vectors, so that the bls0chain derivation can't change silently and
+// lose the accounts derived with it
+func TestHDWalletBLS0ChainVector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	hd, err := newHDWalletFromSeed("bls0chain", seed)
+	require.NoError(t, err)
+	p, err := ParseDerivationPath("m/0'/1'")
+	require.NoError(t, err)
+	w, err := hd.Derive(p)
+	require.NoError(t, err)
+	require.Equal(t, "6e52659cc55f022205bd98e4c1c4da0c565e7aa39e9ae4ad9de55743ce0dca11", w.Keys[0].PrivateKey)
+	require.Equal(t, "bb39f8a07b6a3d3aef42b8436d0d8268d7148e2133948d35c4e78e6776868602de25fc4352c6238344b78bf3ebd394f4b8d0fdf759077d69bde9b4f215887c87", w.Keys[0].PublicKey)
+	require.Equal(t, "e839f736cca3b5977884cdc89b1ac7f62cd8727e0fe4592a6acd5a7b0a985d60", w.ClientID)
+
+	hd, err = NewHDWallet("bls0chain", "glare mistake gun joke bid spare across diagram wrap cube swear cactus cave repeat you brave few best wild lion pitch pole original wasp", "")
+	require.NoError(t, err)
+	w, err = hd.Account(0)
+	require.NoError(t, err)
+	require.Equal(t, "421635f5a3ec2f2b7a3385578400073ea1cc45cd4ed35ea8eeef1faaeb005721", w.Keys[0].PrivateKey)
+	require.Equal(t, "d8ff5d45a5729c306c4825c866ab56f5d589b865733abc50a816286d94f26073", w.ClientID)
+}
+
+func TestHDWallet(t *testing.T) {
+	mnemonic := "glare mistake gun joke bid spare across diagram wrap cube swear cactus cave repeat you brave few best wild lion pitch pole original wasp"
+	for _, scheme := range []string{"bls0chain", "ed25519"} {
+		t.Run(scheme, func(t *testing.T) {
+			hd, err := NewHDWallet(scheme, mnemonic, "")
+			require.NoError(t, err)
+			w0, err := hd.Account(0)
+			require.NoError(t, err)
+			w1, err := hd.Account(1)
+			require.NoError(t, err)
+			require.NotEqual(t, w0.ClientID, w1.ClientID)
+
+			// the derivation is deterministic
+			again, err := NewHDWallet(scheme, mnemonic, "")
+			require.NoError(t, err)
+			w, err := again.Account(1)
+			require.NoError(t, err)
+			require.Equal(t, w1.Keys, w.Keys)
+			require.Equal(t, w1.ClientID, w.ClientID)
+
+			// the password protects the seed
+			other, err := NewHDWallet(scheme, mnemonic, "password")
+			require.NoError(t, err)
+			w, err = other.Account(1)
+			require.NoError(t, err)
+			require.NotEqual(t, w1.ClientID, w.ClientID)
+
+			// the derived keys sign
+			hash := Sha3Sum256(data)
+			signer := NewSignatureScheme(scheme)
+			require.NoError(t, signer.SetPrivateKey(w1.Keys[0].PrivateKey))
+			sig, err := signer.Sign(hash)
+			require.NoError(t, err)
+			verifier := NewSignatureScheme(scheme)
+			require.NoError(t, verifier.SetPublicKey(w1.ClientKey))
+			ok, err := verifier.Verify(sig, hash)
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+	}
+
+	_, err := NewHDWallet("bls0chain", "not a mnemonic", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,10 @@
+package zcncrypto
+
+import (
+	"errors"
+)
+
+var (
+	// ErrBLSNotAvailable bls is not available on the platform, like webassembly where the keys live in js
+	ErrBLSNotAvailable = errors.New("[zcncrypto] bls is not available")
+)
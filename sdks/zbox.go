@@ -12,7 +12,6 @@ import (
 	"github.com/0chain/gosdk/constants"
 	"github.com/0chain/gosdk/core/encryption"
 	"github.com/0chain/gosdk/core/resty"
-	"github.com/0chain/gosdk/core/sys"
 	"github.com/0chain/gosdk/core/zcncrypto"
 	"github.com/0chain/gosdk/zboxcore/client"
 )
@@ -63,7 +62,7 @@ func (z *ZBox) SignRequest(req *http.Request, allocationID string) error {
 
 	hash := encryption.Hash(allocationID)
 
-	sign, err := sys.Sign(hash, z.SignatureScheme, client.GetClientSysKeys())
+	sign, err := client.Sign(hash)
 	if err != nil {
 		return err
 	}
@@ -2,7 +2,9 @@ package client
 
 import (
 	"encoding/json"
+	"sync"
 
+	"github.com/0chain/gosdk/core/signer"
 	"github.com/0chain/gosdk/core/sys"
 	"github.com/0chain/gosdk/core/zcncrypto"
 )
@@ -19,6 +21,11 @@ var (
 	client  *Client
 	clients []*Client
 	Sign    SignFunc
+
+	// clientMu guards clientSigner and the keys of client, set by SetSigner
+	// while the transfers read them.
+	clientMu     sync.RWMutex
+	clientSigner signer.Signer
 )
 
 func init() {
@@ -28,16 +35,38 @@ func init() {
 
 	sys.Sign = signHash
 	// initialize SignFunc as default implementation
-	Sign = func(hash string) (string, error) {
-		return sys.Sign(hash, client.SignatureScheme, GetClientSysKeys())
-	}
+	Sign = signWithSignerOrKeys
 
 	sys.Verify = VerifySignature
 	sys.VerifyWith = VerifySignatureWith
 }
 
+// SetSigner makes the default Sign delegate to s, a remote signer for example,
+// instead of the private keys of the client; the client id and public key are
+// set from s. A nil s restores signing with the keys.
+func SetSigner(s signer.Signer) {
+	clientMu.Lock()
+	defer clientMu.Unlock()
+	clientSigner = s
+	if s == nil {
+		return
+	}
+	client.ClientID = s.ClientID()
+	client.ClientKey = s.PublicKey()
+	client.SignatureScheme = s.SignatureScheme()
+}
+
+// GetSigner returns the signer set with SetSigner, nil if none.
+func GetSigner() signer.Signer {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
+	return clientSigner
+}
+
 // PopulateClient populates single client
 func PopulateClient(clientjson string, signatureScheme string) error {
+	clientMu.Lock()
+	defer clientMu.Unlock()
 	err := json.Unmarshal([]byte(clientjson), &client)
 	client.SignatureScheme = signatureScheme
 	return err
@@ -79,14 +108,20 @@ func GetClients() []*Client {
 }
 
 func GetClientID() string {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
 	return client.ClientID
 }
 
 func GetClientPublicKey() string {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
 	return client.ClientKey
 }
 
 func GetClientPrivateKey() string {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
 	for _, kv := range client.Keys {
 		return kv.PrivateKey
 	}
@@ -96,6 +131,8 @@ func GetClientPrivateKey() string {
 
 // GetClientSysKeys convert client.KeyPair to sys.KeyPair
 func GetClientSysKeys() []sys.KeyPair {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
 	var keys []sys.KeyPair
 	if client != nil {
 		for _, kv := range client.Keys {
@@ -109,6 +146,20 @@ func GetClientSysKeys() []sys.KeyPair {
 	return keys
 }
 
+func signWithSignerOrKeys(hash string) (string, error) {
+	if s := GetSigner(); s != nil {
+		return s.Sign(hash)
+	}
+	return signWithClientKeys(hash)
+}
+
+func signWithClientKeys(hash string) (string, error) {
+	clientMu.RLock()
+	scheme := client.SignatureScheme
+	clientMu.RUnlock()
+	return sys.Sign(hash, scheme, GetClientSysKeys())
+}
+
 func signHash(hash string, signatureScheme string, keys []sys.KeyPair) (string, error) {
 	retSignature := ""
 	for _, kv := range keys {
@@ -131,8 +182,11 @@ func signHash(hash string, signatureScheme string, keys []sys.KeyPair) (string,
 }
 
 func VerifySignature(signature string, msg string) (bool, error) {
-	ss := zcncrypto.NewSignatureScheme(client.SignatureScheme)
-	if err := ss.SetPublicKey(client.ClientKey); err != nil {
+	clientMu.RLock()
+	scheme, key := client.SignatureScheme, client.ClientKey
+	clientMu.RUnlock()
+	ss := zcncrypto.NewSignatureScheme(scheme)
+	if err := ss.SetPublicKey(key); err != nil {
 		return false, err
 	}
 
@@ -140,7 +194,10 @@ func VerifySignature(signature string, msg string) (bool, error) {
 }
 
 func VerifySignatureWith(pubKey, signature, hash string) (bool, error) {
-	sch := zcncrypto.NewSignatureScheme(client.SignatureScheme)
+	clientMu.RLock()
+	scheme := client.SignatureScheme
+	clientMu.RUnlock()
+	sch := zcncrypto.NewSignatureScheme(scheme)
 	err := sch.SetPublicKey(pubKey)
 	if err != nil {
 		return false, err
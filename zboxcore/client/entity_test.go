@@ -0,0 +1,58 @@
+package client
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/0chain/gosdk/core/encryption"
+	"github.com/0chain/gosdk/core/signer"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSigner(t *testing.T) {
+	previous, err := json.Marshal(client)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		SetSigner(nil)
+		require.NoError(t, PopulateClient(string(previous), client.SignatureScheme))
+	})
+
+	w, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	walletJSON, err := w.Marshal()
+	require.NoError(t, err)
+	require.NoError(t, PopulateClient(walletJSON, "bls0chain"))
+	remote, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	s, err := signer.NewLocalSigner("bls0chain", remote)
+	require.NoError(t, err)
+
+	// the transfers sign while the signer changes
+	hash := encryption.Hash("message")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := Sign(hash)
+				require.NoError(t, err)
+				_ = GetClientID()
+			}
+		}()
+	}
+	SetSigner(s)
+	wg.Wait()
+
+	require.Equal(t, remote.ClientID, GetClientID())
+	sig, err := Sign(hash)
+	require.NoError(t, err)
+	ok, err := VerifySignature(sig, hash)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	SetSigner(nil)
+	require.Nil(t, GetSigner())
+}
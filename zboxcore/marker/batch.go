@@ -0,0 +1,32 @@
+package marker
+
+import (
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/sys"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/0chain/gosdk/zboxcore/client"
+)
+
+// invalidSignatures returns the indexes of the items whose signature isn't
+// valid, checked in batches when bls is available on the platform and one by
+// one with sys.VerifyWith otherwise. The signature scheme is the one of the
+// client, bls0chain if the client isn't set.
+func invalidSignatures(items []zcncrypto.SignedHash) ([]int, error) {
+	scheme := client.GetClient().SignatureScheme
+	if scheme == "" {
+		scheme = "bls0chain"
+	}
+	invalid, err := zcncrypto.InvalidSignatures(scheme, items)
+	if !errors.Is(err, zcncrypto.ErrBLSNotAvailable) {
+		return invalid, err
+	}
+
+	invalid = nil
+	for i, it := range items {
+		ok, err := sys.VerifyWith(it.PublicKey, it.Signature, it.Hash)
+		if err != nil || !ok {
+			invalid = append(invalid, i)
+		}
+	}
+	return invalid, nil
+}
@@ -0,0 +1,72 @@
+package marker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/0chain/gosdk/zboxcore/client"
+	"github.com/stretchr/testify/require"
+)
+
+func newMarkerSigner(t *testing.T) (*zcncrypto.Wallet, func(hash string) string) {
+	w, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	return w, func(hash string) string {
+		sig, err := w.Sign(hash, "bls0chain")
+		require.NoError(t, err)
+		return sig
+	}
+}
+
+func TestVerifyWriteMarkers(t *testing.T) {
+	// the sdk isn't initialized, the client has no signature scheme
+	require.Empty(t, client.GetClient().SignatureScheme)
+	invalid, err := VerifyWriteMarkers("pk", []*WriteMarker{{Signature: "00"}})
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, invalid)
+
+	w, sign := newMarkerSigner(t)
+	wms := make([]*WriteMarker, 5)
+	for i := range wms {
+		wms[i] = &WriteMarker{AllocationID: "alloc", BlobberID: fmt.Sprint("blobber", i), ClientID: w.ClientID, Size: int64(i)}
+		wms[i].Signature = sign(wms[i].GetHash())
+	}
+	invalid, err = VerifyWriteMarkers(w.ClientKey, wms)
+	require.NoError(t, err)
+	require.Empty(t, invalid)
+
+	wms[1].Size = 100
+	wms[3].Signature = wms[2].Signature
+	invalid, err = VerifyWriteMarkers(w.ClientKey, wms)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 3}, invalid)
+
+	_, other := newMarkerSigner(t)
+	invalid, err = VerifyWriteMarkers(w.ClientKey, []*WriteMarker{{Signature: other(wms[0].GetHash())}})
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, invalid)
+}
+
+func TestVerifyReadMarkers(t *testing.T) {
+	w1, sign1 := newMarkerSigner(t)
+	w2, sign2 := newMarkerSigner(t)
+	rms := []*ReadMarker{
+		{ClientID: w1.ClientID, ClientPublicKey: w1.ClientKey, BlobberID: "blobber", ReadCounter: 1},
+		{ClientID: w2.ClientID, ClientPublicKey: w2.ClientKey, BlobberID: "blobber", ReadCounter: 2},
+		{ClientID: w1.ClientID, ClientPublicKey: w1.ClientKey, BlobberID: "blobber", ReadCounter: 3},
+	}
+	rms[0].Signature = sign1(rms[0].GetHash())
+	rms[1].Signature = sign2(rms[1].GetHash())
+	// signed by the other client
+	rms[2].Signature = sign2(rms[2].GetHash())
+
+	invalid, err := VerifyReadMarkers(rms)
+	require.NoError(t, err)
+	require.Equal(t, []int{2}, invalid)
+
+	rms[2].Signature = "not a signature"
+	invalid, err = VerifyReadMarkers(rms)
+	require.NoError(t, err)
+	require.Equal(t, []int{2}, invalid)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/0chain/gosdk/core/common"
 	"github.com/0chain/gosdk/core/encryption"
 	"github.com/0chain/gosdk/core/sys"
+	"github.com/0chain/gosdk/core/zcncrypto"
 	"github.com/0chain/gosdk/zboxcore/client"
 )
 
@@ -53,3 +54,22 @@ func (rm *ReadMarker) ValidateWithOtherRM(rm1 *ReadMarker) error {
 	}
 	return nil
 }
+
+// VerifyReadMarkers checks the signatures of rms, each signed by the owner of
+// its ClientPublicKey, at once. It returns the indexes of the markers whose
+// signature isn't valid.
+func VerifyReadMarkers(rms []*ReadMarker) ([]int, error) {
+	items := make([]zcncrypto.SignedHash, len(rms))
+	for i, rm := range rms {
+		items[i] = zcncrypto.SignedHash{
+			PublicKey: rm.ClientPublicKey,
+			Signature: rm.Signature,
+			Hash:      rm.GetHash(),
+		}
+	}
+	invalid, err := invalidSignatures(items)
+	if err != nil {
+		return nil, errors.New("validate_rm", err.Error())
+	}
+	return invalid, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/0chain/errors"
 	"github.com/0chain/gosdk/core/encryption"
 	"github.com/0chain/gosdk/core/sys"
+	"github.com/0chain/gosdk/core/zcncrypto"
 	"github.com/0chain/gosdk/zboxcore/client"
 )
 
@@ -45,7 +46,7 @@ func (wm *WriteMarker) Sign() error {
 func (wm *WriteMarker) VerifySignature(clientPublicKey string) error {
 	hashData := wm.GetHashData()
 	signatureHash := encryption.Hash(hashData)
-	sigOK, err := sys.Verify(wm.Signature, signatureHash)
+	sigOK, err := sys.VerifyWith(clientPublicKey, wm.Signature, signatureHash)
 	if err != nil {
 		return errors.New("write_marker_validation_failed", "Error during verifying signature. "+err.Error())
 	}
@@ -54,3 +55,22 @@ func (wm *WriteMarker) VerifySignature(clientPublicKey string) error {
 	}
 	return nil
 }
+
+// VerifyWriteMarkers checks the signatures of wms, all signed by the owner of
+// clientPublicKey, at once. It returns the indexes of the markers whose
+// signature isn't valid.
+func VerifyWriteMarkers(clientPublicKey string, wms []*WriteMarker) ([]int, error) {
+	items := make([]zcncrypto.SignedHash, len(wms))
+	for i, wm := range wms {
+		items[i] = zcncrypto.SignedHash{
+			PublicKey: clientPublicKey,
+			Signature: wm.Signature,
+			Hash:      wm.GetHash(),
+		}
+	}
+	invalid, err := invalidSignatures(items)
+	if err != nil {
+		return nil, errors.New("write_marker_validation_failed", "Error during verifying signatures. "+err.Error())
+	}
+	return invalid, nil
+}
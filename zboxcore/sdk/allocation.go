@@ -28,6 +28,7 @@ import (
 	"github.com/0chain/gosdk/core/common"
 	"github.com/0chain/gosdk/core/pathutil"
 	"github.com/0chain/gosdk/core/sys"
+	"github.com/0chain/gosdk/core/telemetry"
 	"github.com/0chain/gosdk/zboxcore/blockchain"
 	"github.com/0chain/gosdk/zboxcore/fileref"
 	"github.com/0chain/gosdk/zboxcore/logger"
@@ -201,6 +202,8 @@ type Allocation struct {
 	downloadRequests        []*DownloadRequest
 	repairRequestInProgress *RepairRequest
 	initialized             bool
+	// traffic is created by trafficShaper, guarded by trafficMu
+	traffic *trafficShaper
 
 	// conseususes
 	consensusThreshold int
@@ -359,10 +362,23 @@ func (a *Allocation) UpdateFile(workdir, localpath string, remotepath string,
 func (a *Allocation) UploadFile(workdir, localpath string, remotepath string,
 	status StatusCallback) error {
 
-	return a.StartChunkedUpload(workdir, localpath, remotepath, status, false, false, "", false, false)
+	return a.UploadFileContext(a.ctx, workdir, localpath, remotepath, status)
+}
+
+// UploadFileContext is UploadFile bound to ctx: canceling ctx cancels the
+// upload.
+func (a *Allocation) UploadFileContext(ctx context.Context, workdir, localpath string, remotepath string,
+	status StatusCallback) error {
+
+	return a.StartChunkedUpload(workdir, localpath, remotepath, status, false, false, "", false, false, WithContext(ctx))
 }
 
 func (a *Allocation) CreateDir(remotePath string) error {
+	return a.CreateDirContext(a.ctx, remotePath)
+}
+
+// CreateDirContext is CreateDir bound to ctx.
+func (a *Allocation) CreateDirContext(ctx context.Context, remotePath string) error {
 	if !a.isInitialized() {
 		return notInitialized
 	}
@@ -395,7 +411,7 @@ func (a *Allocation) CreateDir(remotePath string) error {
 			fullconsensus:   a.fullconsensus,
 		},
 	}
-	req.ctx, req.ctxCncl = context.WithCancel(a.ctx)
+	req.ctx, req.ctxCncl = context.WithCancel(ctx)
 
 	err := req.ProcessDir(a)
 	return err
@@ -787,6 +803,11 @@ func (a *Allocation) GetCurrentVersion() (bool, error) {
 }
 
 func (a *Allocation) RepairRequired(remotepath string) (zboxutil.Uint128, zboxutil.Uint128, bool, *fileref.FileRef, error) {
+	return a.RepairRequiredContext(a.ctx, remotepath)
+}
+
+// RepairRequiredContext is RepairRequired bound to ctx.
+func (a *Allocation) RepairRequiredContext(ctx context.Context, remotepath string) (zboxutil.Uint128, zboxutil.Uint128, bool, *fileref.FileRef, error) {
 	if !a.isInitialized() {
 		return zboxutil.Uint128{}, zboxutil.Uint128{}, false, nil, notInitialized
 	}
@@ -797,13 +818,13 @@ func (a *Allocation) RepairRequired(remotepath string) (zboxutil.Uint128, zboxut
 	listReq.blobbers = a.Blobbers
 	listReq.fullconsensus = a.fullconsensus
 	listReq.consensusThresh = a.DataShards
-	listReq.ctx = a.ctx
+	listReq.ctx = ctx
 	listReq.remotefilepath = remotepath
 	found, deleteMask, fileRef, _ := listReq.getFileConsensusFromBlobbers()
 	if fileRef == nil {
 		var repairErr error
 		if deleteMask.Equals(zboxutil.NewUint128(0)) {
-			repairErr = errors.New("", "File not found for the given remotepath")
+			repairErr = &NotFoundError{Path: remotepath, Msg: "File not found for the given remotepath"}
 		}
 		return found, deleteMask, false, fileRef, repairErr
 	}
@@ -814,12 +835,22 @@ func (a *Allocation) RepairRequired(remotepath string) (zboxutil.Uint128, zboxut
 }
 
 func (a *Allocation) DoMultiOperation(operations []OperationRequest) error {
+	return a.DoMultiOperationContext(a.ctx, operations)
+}
+
+// DoMultiOperationContext is DoMultiOperation bound to ctx.
+func (a *Allocation) DoMultiOperationContext(ctx context.Context, operations []OperationRequest) (err error) {
 	if len(operations) == 0 {
 		return nil
 	}
 	if !a.isInitialized() {
 		return notInitialized
 	}
+	ctx, span := telemetry.StartSpan(ctx, "allocation.multi_operation")
+	span.SetAttribute("allocation.id", a.ID)
+	span.SetAttribute("operations", len(operations))
+	defer func() { span.End(err) }()
+
 	connectionID := zboxutil.NewConnectionId()
 
 	for i := 0; i < len(operations); {
@@ -829,7 +860,7 @@ func (a *Allocation) DoMultiOperation(operations []OperationRequest) error {
 		mo.operationMask = zboxutil.NewUint128(0)
 		mo.maskMU = &sync.Mutex{}
 		mo.connectionID = connectionID
-		mo.ctx, mo.ctxCncl = context.WithCancel(a.ctx)
+		mo.ctx, mo.ctxCncl = context.WithCancel(ctx)
 		mo.Consensus = Consensus{
 			RWMutex:         &sync.RWMutex{},
 			consensusThresh: a.consensusThreshold,
@@ -856,13 +887,15 @@ func (a *Allocation) DoMultiOperation(operations []OperationRequest) error {
 		if mo.operationMask.CountOnes() < mo.consensusThresh {
 			majorErr := zboxutil.MajorError(connectionErrors)
 			if majorErr != nil {
-				return errors.New("consensus_not_met",
+				return newConsensusError(ConsensusNotMet,
 					fmt.Sprintf("Multioperation: create connection failed. Required consensus %d got %d. Major error: %s",
-						mo.consensusThresh, mo.operationMask.CountOnes(), majorErr.Error()))
+						mo.consensusThresh, mo.operationMask.CountOnes(), majorErr.Error()),
+					mo.consensusThresh, mo.operationMask.CountOnes(), mo.allocationObj.Blobbers, connectionErrors)
 			}
-			return errors.New("consensus_not_met",
+			return newConsensusError(ConsensusNotMet,
 				fmt.Sprintf("Multioperation: create connection failed. Required consensus %d got %d",
-					mo.consensusThresh, mo.operationMask.CountOnes()))
+					mo.consensusThresh, mo.operationMask.CountOnes()),
+				mo.consensusThresh, mo.operationMask.CountOnes(), nil, nil)
 		}
 
 		for ; i < len(operations); i++ {
@@ -880,6 +913,8 @@ func (a *Allocation) DoMultiOperation(operations []OperationRequest) error {
 				err             error
 				newConnectionID string
 			)
+			// the upload options of the operation take precedence over mo.ctx
+			uploadOpts := append([]ChunkedUploadOption{WithContext(mo.ctx)}, op.Opts...)
 
 			switch op.OperationType {
 			case constants.FileOperationRename:
@@ -892,13 +927,13 @@ func (a *Allocation) DoMultiOperation(operations []OperationRequest) error {
 				operation = NewMoveOperation(op.RemotePath, op.DestPath, mo.operationMask, mo.maskMU, mo.consensusThresh, mo.fullconsensus, mo.ctx)
 
 			case constants.FileOperationInsert:
-				operation, newConnectionID, err = NewUploadOperation(op.Workdir, mo.allocationObj, mo.connectionID, op.FileMeta, op.FileReader, false, op.IsWebstreaming, op.Opts...)
+				operation, newConnectionID, err = NewUploadOperation(op.Workdir, mo.allocationObj, mo.connectionID, op.FileMeta, op.FileReader, false, op.IsWebstreaming, uploadOpts...)
 
 			case constants.FileOperationDelete:
 				operation = NewDeleteOperation(op.RemotePath, mo.operationMask, mo.maskMU, mo.consensusThresh, mo.fullconsensus, mo.ctx)
 
 			case constants.FileOperationUpdate:
-				operation, newConnectionID, err = NewUploadOperation(op.Workdir, mo.allocationObj, mo.connectionID, op.FileMeta, op.FileReader, true, op.IsWebstreaming, op.Opts...)
+				operation, newConnectionID, err = NewUploadOperation(op.Workdir, mo.allocationObj, mo.connectionID, op.FileMeta, op.FileReader, true, op.IsWebstreaming, uploadOpts...)
 
 			case constants.FileOperationCreateDir:
 				operation = NewDirOperation(op.RemotePath, mo.operationMask, mo.maskMU, mo.consensusThresh, mo.fullconsensus, mo.ctx)
@@ -956,7 +991,20 @@ func (a *Allocation) DownloadFileToFileHandler(
 	status StatusCallback,
 	isFinal bool,
 ) error {
-	return a.addAndGenerateDownloadRequest(fileHandler, remotePath, DOWNLOAD_CONTENT_FULL, 1, 0,
+	return a.DownloadFileToFileHandlerContext(a.ctx, fileHandler, remotePath, verifyDownload, status, isFinal)
+}
+
+// DownloadFileToFileHandlerContext is DownloadFileToFileHandler bound to ctx:
+// canceling ctx cancels the download.
+func (a *Allocation) DownloadFileToFileHandlerContext(
+	ctx context.Context,
+	fileHandler sys.File,
+	remotePath string,
+	verifyDownload bool,
+	status StatusCallback,
+	isFinal bool,
+) error {
+	return a.addAndGenerateDownloadRequestContext(ctx, fileHandler, remotePath, DOWNLOAD_CONTENT_FULL, 1, 0,
 		numBlockDownloads, verifyDownload, status, isFinal, "")
 }
 
@@ -969,7 +1017,23 @@ func (a *Allocation) DownloadByBlocksToFileHandler(
 	status StatusCallback,
 	isFinal bool,
 ) error {
-	return a.addAndGenerateDownloadRequest(fileHandler, remotePath, DOWNLOAD_CONTENT_FULL, startBlock, endBlock,
+	return a.DownloadByBlocksToFileHandlerContext(a.ctx, fileHandler, remotePath, startBlock, endBlock,
+		numBlocks, verifyDownload, status, isFinal)
+}
+
+// DownloadByBlocksToFileHandlerContext is DownloadByBlocksToFileHandler bound
+// to ctx: canceling ctx cancels the download.
+func (a *Allocation) DownloadByBlocksToFileHandlerContext(
+	ctx context.Context,
+	fileHandler sys.File,
+	remotePath string,
+	startBlock, endBlock int64,
+	numBlocks int,
+	verifyDownload bool,
+	status StatusCallback,
+	isFinal bool,
+) error {
+	return a.addAndGenerateDownloadRequestContext(ctx, fileHandler, remotePath, DOWNLOAD_CONTENT_FULL, startBlock, endBlock,
 		numBlocks, verifyDownload, status, isFinal, "")
 }
 
@@ -985,12 +1049,18 @@ func (a *Allocation) DownloadThumbnailToFileHandler(
 }
 
 func (a *Allocation) DownloadFile(localPath string, remotePath string, verifyDownload bool, status StatusCallback, isFinal bool) error {
+	return a.DownloadFileContext(a.ctx, localPath, remotePath, verifyDownload, status, isFinal)
+}
+
+// DownloadFileContext is DownloadFile bound to ctx: canceling ctx cancels
+// the download.
+func (a *Allocation) DownloadFileContext(ctx context.Context, localPath string, remotePath string, verifyDownload bool, status StatusCallback, isFinal bool) error {
 	f, localFilePath, toKeep, err := a.prepareAndOpenLocalFile(localPath, remotePath)
 	if err != nil {
 		return err
 	}
 
-	err = a.addAndGenerateDownloadRequest(f, remotePath, DOWNLOAD_CONTENT_FULL, 1, 0,
+	err = a.addAndGenerateDownloadRequestContext(ctx, f, remotePath, DOWNLOAD_CONTENT_FULL, 1, 0,
 		numBlockDownloads, verifyDownload, status, isFinal, localFilePath)
 	if err != nil {
 		if !toKeep {
@@ -1004,6 +1074,15 @@ func (a *Allocation) DownloadFile(localPath string, remotePath string, verifyDow
 
 // TODO: Use a map to store the download request and use flag isFinal to start the download, calculate readCount in parallel if possible
 func (a *Allocation) DownloadFileByBlock(
+	localPath string, remotePath string, startBlock int64, endBlock int64,
+	numBlocks int, verifyDownload bool, status StatusCallback, isFinal bool) error {
+	return a.DownloadFileByBlockContext(a.ctx, localPath, remotePath, startBlock, endBlock,
+		numBlocks, verifyDownload, status, isFinal)
+}
+
+// DownloadFileByBlockContext is DownloadFileByBlock bound to ctx: canceling
+// ctx cancels the download.
+func (a *Allocation) DownloadFileByBlockContext(ctx context.Context,
 	localPath string, remotePath string, startBlock int64, endBlock int64,
 	numBlocks int, verifyDownload bool, status StatusCallback, isFinal bool) error {
 	f, localFilePath, toKeep, err := a.prepareAndOpenLocalFile(localPath, remotePath)
@@ -1011,7 +1090,7 @@ func (a *Allocation) DownloadFileByBlock(
 		return err
 	}
 
-	err = a.addAndGenerateDownloadRequest(f, remotePath, DOWNLOAD_CONTENT_FULL, startBlock, endBlock,
+	err = a.addAndGenerateDownloadRequestContext(ctx, f, remotePath, DOWNLOAD_CONTENT_FULL, startBlock, endBlock,
 		numBlockDownloads, verifyDownload, status, isFinal, localFilePath)
 	if err != nil {
 		if !toKeep {
@@ -1024,12 +1103,18 @@ func (a *Allocation) DownloadFileByBlock(
 }
 
 func (a *Allocation) DownloadThumbnail(localPath string, remotePath string, verifyDownload bool, status StatusCallback, isFinal bool) error {
+	return a.DownloadThumbnailContext(a.ctx, localPath, remotePath, verifyDownload, status, isFinal)
+}
+
+// DownloadThumbnailContext is DownloadThumbnail bound to ctx: canceling ctx
+// cancels the download.
+func (a *Allocation) DownloadThumbnailContext(ctx context.Context, localPath string, remotePath string, verifyDownload bool, status StatusCallback, isFinal bool) error {
 	f, localFilePath, toKeep, err := a.prepareAndOpenLocalFile(localPath, remotePath)
 	if err != nil {
 		return err
 	}
 
-	err = a.addAndGenerateDownloadRequest(f, remotePath, DOWNLOAD_CONTENT_THUMB, 1, 0,
+	err = a.addAndGenerateDownloadRequestContext(ctx, f, remotePath, DOWNLOAD_CONTENT_THUMB, 1, 0,
 		numBlockDownloads, verifyDownload, status, isFinal, localFilePath)
 	if err != nil {
 		if !toKeep {
@@ -1042,6 +1127,7 @@ func (a *Allocation) DownloadThumbnail(localPath string, remotePath string, veri
 }
 
 func (a *Allocation) generateDownloadRequest(
+	ctx context.Context,
 	fileHandler sys.File,
 	remotePath string,
 	contentMode string,
@@ -1062,7 +1148,7 @@ func (a *Allocation) generateDownloadRequest(
 	downloadReq.allocationTx = a.Tx
 	downloadReq.allocOwnerID = a.Owner
 	downloadReq.allocOwnerPubKey = a.OwnerPublicKey
-	downloadReq.ctx, downloadReq.ctxCncl = context.WithCancel(a.ctx)
+	downloadReq.ctx, downloadReq.ctxCncl = context.WithCancel(ctx)
 	downloadReq.fileHandler = fileHandler
 	downloadReq.localFilePath = localFilePath
 	downloadReq.remotefilepath = remotePath
@@ -1089,6 +1175,11 @@ func (a *Allocation) generateDownloadRequest(
 	}
 	downloadReq.contentMode = contentMode
 	downloadReq.connectionID = connectionID
+	downloadReq.traffic = a.trafficShaper()
+	// downloads started by a repair yield to the user's ones
+	if _, ok := status.(*RepairStatusCB); ok {
+		downloadReq.priority = PriorityRepair
+	}
 
 	return downloadReq, nil
 }
@@ -1102,9 +1193,24 @@ func (a *Allocation) addAndGenerateDownloadRequest(
 	status StatusCallback,
 	isFinal bool,
 	localFilePath string,
+) error {
+	return a.addAndGenerateDownloadRequestContext(a.ctx, fileHandler, remotePath, contentMode,
+		startBlock, endBlock, numBlocks, verifyDownload, status, isFinal, localFilePath)
+}
+
+func (a *Allocation) addAndGenerateDownloadRequestContext(
+	ctx context.Context,
+	fileHandler sys.File,
+	remotePath, contentMode string,
+	startBlock, endBlock int64,
+	numBlocks int,
+	verifyDownload bool,
+	status StatusCallback,
+	isFinal bool,
+	localFilePath string,
 ) error {
 	downloadReq, err := a.generateDownloadRequest(
-		fileHandler, remotePath, contentMode, startBlock, endBlock,
+		ctx, fileHandler, remotePath, contentMode, startBlock, endBlock,
 		numBlocks, verifyDownload, status, "", localFilePath)
 	if err != nil {
 		return err
@@ -1251,6 +1357,11 @@ func (a *Allocation) prepareAndOpenLocalFile(localPath string, remotePath string
 }
 
 func (a *Allocation) ListDirFromAuthTicket(authTicket string, lookupHash string) (*ListResult, error) {
+	return a.ListDirFromAuthTicketContext(a.ctx, authTicket, lookupHash)
+}
+
+// ListDirFromAuthTicketContext is ListDirFromAuthTicket bound to ctx.
+func (a *Allocation) ListDirFromAuthTicketContext(ctx context.Context, authTicket string, lookupHash string) (*ListResult, error) {
 	if !a.isInitialized() {
 		return nil, notInitialized
 	}
@@ -1273,7 +1384,7 @@ func (a *Allocation) ListDirFromAuthTicket(authTicket string, lookupHash string)
 	listReq.blobbers = a.Blobbers
 	listReq.fullconsensus = a.fullconsensus
 	listReq.consensusThresh = a.consensusThreshold
-	listReq.ctx = a.ctx
+	listReq.ctx = ctx
 	listReq.remotefilepathhash = lookupHash
 	listReq.authToken = at
 	ref, err := listReq.GetListFromBlobbers()
@@ -1289,6 +1400,12 @@ func (a *Allocation) ListDirFromAuthTicket(authTicket string, lookupHash string)
 }
 
 func (a *Allocation) ListDir(path string, opts ...bool) (*ListResult, error) {
+	return a.ListDirContext(a.ctx, path, opts...)
+}
+
+// ListDirContext is ListDir bound to ctx: canceling ctx aborts the requests
+// to the blobbers.
+func (a *Allocation) ListDirContext(ctx context.Context, path string, opts ...bool) (*ListResult, error) {
 	if !a.isInitialized() {
 		return nil, notInitialized
 	}
@@ -1306,7 +1423,7 @@ func (a *Allocation) ListDir(path string, opts ...bool) (*ListResult, error) {
 	listReq.blobbers = a.Blobbers
 	listReq.fullconsensus = a.fullconsensus
 	listReq.consensusThresh = a.DataShards
-	listReq.ctx = a.ctx
+	listReq.ctx = ctx
 	listReq.remotefilepath = path
 	if len(opts) > 0 {
 		listReq.forRepair = opts[0]
@@ -1322,7 +1439,7 @@ func (a *Allocation) ListDir(path string, opts ...bool) (*ListResult, error) {
 	return nil, errors.New("list_request_failed", "Failed to get list response from the blobbers")
 }
 
-func (a *Allocation) getRefs(path, pathHash, authToken, offsetPath, updatedDate, offsetDate, fileType, refType string, level, pageLimit int) (*ObjectTreeResult, error) {
+func (a *Allocation) getRefs(ctx context.Context, path, pathHash, authToken, offsetPath, updatedDate, offsetDate, fileType, refType string, level, pageLimit int) (*ObjectTreeResult, error) {
 	if !a.isInitialized() {
 		return nil, notInitialized
 	}
@@ -1342,7 +1459,7 @@ func (a *Allocation) getRefs(path, pathHash, authToken, offsetPath, updatedDate,
 		fileType:       fileType,
 		refType:        refType,
 		wg:             &sync.WaitGroup{},
-		ctx:            a.ctx,
+		ctx:            ctx,
 	}
 	oTreeReq.fullconsensus = a.fullconsensus
 	oTreeReq.consensusThresh = a.consensusThreshold
@@ -1369,6 +1486,12 @@ func (a *Allocation) getDownloadMaskForBlobber(blobberID string) (zboxutil.Uint1
 }
 
 func (a *Allocation) DownloadFromBlobber(blobberID, localPath, remotePath string, status StatusCallback) error {
+	return a.DownloadFromBlobberContext(a.ctx, blobberID, localPath, remotePath, status)
+}
+
+// DownloadFromBlobberContext is DownloadFromBlobber bound to ctx: canceling
+// ctx cancels the download.
+func (a *Allocation) DownloadFromBlobberContext(ctx context.Context, blobberID, localPath, remotePath string, status StatusCallback) error {
 
 	mask, blobbers, err := a.getDownloadMaskForBlobber(blobberID)
 	if err != nil {
@@ -1382,7 +1505,7 @@ func (a *Allocation) DownloadFromBlobber(blobberID, localPath, remotePath string
 	if err != nil {
 		return err
 	}
-	downloadReq, err := a.generateDownloadRequest(f, remotePath, DOWNLOAD_CONTENT_FULL, 1, 0, numBlockDownloads, verifyDownload,
+	downloadReq, err := a.generateDownloadRequest(ctx, f, remotePath, DOWNLOAD_CONTENT_FULL, 1, 0, numBlockDownloads, verifyDownload,
 		status, zboxutil.NewConnectionId(), localFilePath)
 	if err != nil {
 		if !toKeep {
@@ -1414,6 +1537,11 @@ func (a *Allocation) DownloadFromBlobber(blobberID, localPath, remotePath string
 
 // GetRefsWithAuthTicket get refs that are children of shared remote path.
 func (a *Allocation) GetRefsWithAuthTicket(authToken, offsetPath, updatedDate, offsetDate, fileType, refType string, level, pageLimit int) (*ObjectTreeResult, error) {
+	return a.GetRefsWithAuthTicketContext(a.ctx, authToken, offsetPath, updatedDate, offsetDate, fileType, refType, level, pageLimit)
+}
+
+// GetRefsWithAuthTicketContext is GetRefsWithAuthTicket bound to ctx.
+func (a *Allocation) GetRefsWithAuthTicketContext(ctx context.Context, authToken, offsetPath, updatedDate, offsetDate, fileType, refType string, level, pageLimit int) (*ObjectTreeResult, error) {
 	if authToken == "" {
 		return nil, errors.New("empty_auth_token", "auth token cannot be empty")
 	}
@@ -1428,24 +1556,34 @@ func (a *Allocation) GetRefsWithAuthTicket(authToken, offsetPath, updatedDate, o
 	}
 
 	at, _ := json.Marshal(authTicket)
-	return a.getRefs("", authTicket.FilePathHash, string(at), offsetPath, updatedDate, offsetDate, fileType, refType, level, pageLimit)
+	return a.getRefs(ctx, "", authTicket.FilePathHash, string(at), offsetPath, updatedDate, offsetDate, fileType, refType, level, pageLimit)
 }
 
 // This function will retrieve paginated objectTree and will handle concensus; Required tree should be made in application side.
 func (a *Allocation) GetRefs(path, offsetPath, updatedDate, offsetDate, fileType, refType string, level, pageLimit int) (*ObjectTreeResult, error) {
+	return a.GetRefsContext(a.ctx, path, offsetPath, updatedDate, offsetDate, fileType, refType, level, pageLimit)
+}
+
+// GetRefsContext is GetRefs bound to ctx.
+func (a *Allocation) GetRefsContext(ctx context.Context, path, offsetPath, updatedDate, offsetDate, fileType, refType string, level, pageLimit int) (*ObjectTreeResult, error) {
 	if len(path) == 0 || !zboxutil.IsRemoteAbs(path) {
 		return nil, errors.New("invalid_path", fmt.Sprintf("Absolute path required. Path provided: %v", path))
 	}
 
-	return a.getRefs(path, "", "", offsetPath, updatedDate, offsetDate, fileType, refType, level, pageLimit)
+	return a.getRefs(ctx, path, "", "", offsetPath, updatedDate, offsetDate, fileType, refType, level, pageLimit)
 }
 
 func (a *Allocation) GetRefsFromLookupHash(pathHash, offsetPath, updatedDate, offsetDate, fileType, refType string, level, pageLimit int) (*ObjectTreeResult, error) {
+	return a.GetRefsFromLookupHashContext(a.ctx, pathHash, offsetPath, updatedDate, offsetDate, fileType, refType, level, pageLimit)
+}
+
+// GetRefsFromLookupHashContext is GetRefsFromLookupHash bound to ctx.
+func (a *Allocation) GetRefsFromLookupHashContext(ctx context.Context, pathHash, offsetPath, updatedDate, offsetDate, fileType, refType string, level, pageLimit int) (*ObjectTreeResult, error) {
 	if pathHash == "" {
 		return nil, errors.New("invalid_lookup_hash", "lookup hash cannot be empty")
 	}
 
-	return a.getRefs("", pathHash, "", offsetPath, updatedDate, offsetDate, fileType, refType, level, pageLimit)
+	return a.getRefs(ctx, "", pathHash, "", offsetPath, updatedDate, offsetDate, fileType, refType, level, pageLimit)
 
 }
 
@@ -1480,10 +1618,20 @@ func (a *Allocation) GetRecentlyAddedRefs(page int, fromDate int64, pageLimit in
 }
 
 func (a *Allocation) GetFileMeta(path string) (*ConsolidatedFileMeta, error) {
+	return a.GetFileMetaContext(a.ctx, path)
+}
+
+// GetFileMetaContext is GetFileMeta bound to ctx.
+func (a *Allocation) GetFileMetaContext(ctx context.Context, path string) (*ConsolidatedFileMeta, error) {
 	if !a.isInitialized() {
 		return nil, notInitialized
 	}
 
+	cache := GetCache()
+	if meta, ok := cache.getFileMeta(a.ID, path); ok {
+		return meta, nil
+	}
+
 	result := &ConsolidatedFileMeta{}
 	listReq := &ListRequest{Consensus: Consensus{RWMutex: &sync.RWMutex{}}}
 	listReq.allocationID = a.ID
@@ -1491,7 +1639,7 @@ func (a *Allocation) GetFileMeta(path string) (*ConsolidatedFileMeta, error) {
 	listReq.blobbers = a.Blobbers
 	listReq.fullconsensus = a.fullconsensus
 	listReq.consensusThresh = a.consensusThreshold
-	listReq.ctx = a.ctx
+	listReq.ctx = ctx
 	listReq.remotefilepath = path
 	_, _, ref, _ := listReq.getFileConsensusFromBlobbers()
 	if ref != nil {
@@ -1509,6 +1657,7 @@ func (a *Allocation) GetFileMeta(path string) (*ConsolidatedFileMeta, error) {
 		if result.ActualFileSize > 0 {
 			result.ActualNumBlocks = (ref.ActualFileSize + CHUNK_SIZE - 1) / CHUNK_SIZE
 		}
+		cache.setFileMeta(a.ID, path, result)
 		return result, nil
 	}
 	return nil, errors.New("file_meta_error", "Error getting the file meta data from blobbers")
@@ -1523,6 +1672,11 @@ func (a *Allocation) GetChunkReadSize(encrypt bool) int64 {
 }
 
 func (a *Allocation) GetFileMetaFromAuthTicket(authTicket string, lookupHash string) (*ConsolidatedFileMeta, error) {
+	return a.GetFileMetaFromAuthTicketContext(a.ctx, authTicket, lookupHash)
+}
+
+// GetFileMetaFromAuthTicketContext is GetFileMetaFromAuthTicket bound to ctx.
+func (a *Allocation) GetFileMetaFromAuthTicketContext(ctx context.Context, authTicket string, lookupHash string) (*ConsolidatedFileMeta, error) {
 	if !a.isInitialized() {
 		return nil, notInitialized
 	}
@@ -1547,7 +1701,7 @@ func (a *Allocation) GetFileMetaFromAuthTicket(authTicket string, lookupHash str
 	listReq.blobbers = a.Blobbers
 	listReq.fullconsensus = a.fullconsensus
 	listReq.consensusThresh = a.consensusThreshold
-	listReq.ctx = a.ctx
+	listReq.ctx = ctx
 	listReq.remotefilepathhash = lookupHash
 	listReq.authToken = at
 	_, _, ref, _ := listReq.getFileConsensusFromBlobbers()
@@ -1570,6 +1724,11 @@ func (a *Allocation) GetFileMetaFromAuthTicket(authTicket string, lookupHash str
 }
 
 func (a *Allocation) GetFileStats(path string) (map[string]*FileStats, error) {
+	return a.GetFileStatsContext(a.ctx, path)
+}
+
+// GetFileStatsContext is GetFileStats bound to ctx.
+func (a *Allocation) GetFileStatsContext(ctx context.Context, path string) (map[string]*FileStats, error) {
 	if !a.isInitialized() {
 		return nil, notInitialized
 	}
@@ -1587,7 +1746,7 @@ func (a *Allocation) GetFileStats(path string) (map[string]*FileStats, error) {
 	listReq.blobbers = a.Blobbers
 	listReq.fullconsensus = a.fullconsensus
 	listReq.consensusThresh = a.consensusThreshold
-	listReq.ctx = a.ctx
+	listReq.ctx = ctx
 	listReq.remotefilepath = path
 	ref := listReq.getFileStatsFromBlobbers()
 	if ref != nil {
@@ -1597,10 +1756,19 @@ func (a *Allocation) GetFileStats(path string) (map[string]*FileStats, error) {
 }
 
 func (a *Allocation) DeleteFile(path string) error {
-	return a.deleteFile(path, a.consensusThreshold, a.fullconsensus, zboxutil.NewUint128(1).Lsh(uint64(len(a.Blobbers))).Sub64(1))
+	return a.DeleteFileContext(a.ctx, path)
+}
+
+// DeleteFileContext is DeleteFile bound to ctx.
+func (a *Allocation) DeleteFileContext(ctx context.Context, path string) error {
+	return a.deleteFileContext(ctx, path, a.consensusThreshold, a.fullconsensus, zboxutil.NewUint128(1).Lsh(uint64(len(a.Blobbers))).Sub64(1))
 }
 
 func (a *Allocation) deleteFile(path string, threshConsensus, fullConsensus int, mask zboxutil.Uint128) error {
+	return a.deleteFileContext(a.ctx, path, threshConsensus, fullConsensus, mask)
+}
+
+func (a *Allocation) deleteFileContext(ctx context.Context, path string, threshConsensus, fullConsensus int, mask zboxutil.Uint128) error {
 	if !a.isInitialized() {
 		return notInitialized
 	}
@@ -1624,7 +1792,7 @@ func (a *Allocation) deleteFile(path string, threshConsensus, fullConsensus int,
 	req.allocationID = a.ID
 	req.allocationTx = a.Tx
 	req.consensus.Init(threshConsensus, fullConsensus)
-	req.ctx, req.ctxCncl = context.WithCancel(a.ctx)
+	req.ctx, req.ctxCncl = context.WithCancel(ctx)
 	req.remotefilepath = path
 	req.connectionID = zboxutil.NewConnectionId()
 	req.deleteMask = mask
@@ -1635,6 +1803,11 @@ func (a *Allocation) deleteFile(path string, threshConsensus, fullConsensus int,
 }
 
 func (a *Allocation) RenameObject(path string, destName string) error {
+	return a.RenameObjectContext(a.ctx, path, destName)
+}
+
+// RenameObjectContext is RenameObject bound to ctx.
+func (a *Allocation) RenameObjectContext(ctx context.Context, path string, destName string) error {
 	if !a.isInitialized() {
 		return notInitialized
 	}
@@ -1670,7 +1843,7 @@ func (a *Allocation) RenameObject(path string, destName string) error {
 	req.newName = destName
 	req.consensus.fullconsensus = a.fullconsensus
 	req.consensus.consensusThresh = a.consensusThreshold
-	req.ctx, req.ctxCncl = context.WithCancel(a.ctx)
+	req.ctx, req.ctxCncl = context.WithCancel(ctx)
 	req.remotefilepath = path
 	req.renameMask = zboxutil.NewUint128(1).Lsh(uint64(len(a.Blobbers))).Sub64(1)
 	req.maskMU = &sync.Mutex{}
@@ -1680,6 +1853,11 @@ func (a *Allocation) RenameObject(path string, destName string) error {
 }
 
 func (a *Allocation) MoveObject(srcPath string, destPath string) error {
+	return a.MoveObjectContext(a.ctx, srcPath, destPath)
+}
+
+// MoveObjectContext is MoveObject bound to ctx.
+func (a *Allocation) MoveObjectContext(ctx context.Context, srcPath string, destPath string) error {
 	if !a.isInitialized() {
 		return notInitialized
 	}
@@ -1713,7 +1891,7 @@ func (a *Allocation) MoveObject(srcPath string, destPath string) error {
 	req.destPath = destPath
 	req.fullconsensus = a.fullconsensus
 	req.consensusThresh = a.consensusThreshold
-	req.ctx, req.ctxCncl = context.WithCancel(a.ctx)
+	req.ctx, req.ctxCncl = context.WithCancel(ctx)
 	req.remotefilepath = srcPath
 	req.moveMask = zboxutil.NewUint128(1).Lsh(uint64(len(a.Blobbers))).Sub64(1)
 	req.maskMU = &sync.Mutex{}
@@ -1723,6 +1901,11 @@ func (a *Allocation) MoveObject(srcPath string, destPath string) error {
 }
 
 func (a *Allocation) CopyObject(path string, destPath string) error {
+	return a.CopyObjectContext(a.ctx, path, destPath)
+}
+
+// CopyObjectContext is CopyObject bound to ctx.
+func (a *Allocation) CopyObjectContext(ctx context.Context, path string, destPath string) error {
 	if !a.isInitialized() {
 		return notInitialized
 	}
@@ -1756,7 +1939,7 @@ func (a *Allocation) CopyObject(path string, destPath string) error {
 	req.destPath = destPath
 	req.fullconsensus = a.fullconsensus
 	req.consensusThresh = a.consensusThreshold
-	req.ctx, req.ctxCncl = context.WithCancel(a.ctx)
+	req.ctx, req.ctxCncl = context.WithCancel(ctx)
 	req.remotefilepath = path
 	req.copyMask = zboxutil.NewUint128(1).Lsh(uint64(len(a.Blobbers))).Sub64(1)
 	req.maskMU = &sync.Mutex{}
@@ -1772,7 +1955,30 @@ func (a *Allocation) GetAuthTicketForShare(
 	return a.GetAuthTicket(path, filename, referenceType, refereeClientID, "", 0, &now)
 }
 
+// RevokeShare revokes the share of path to refereeClientID, the public share
+// if empty, and records it in the share registry.
 func (a *Allocation) RevokeShare(path string, refereeClientID string) error {
+	return a.RevokeShareContext(a.ctx, path, refereeClientID)
+}
+
+// RevokeShareContext is RevokeShare bound to ctx.
+func (a *Allocation) RevokeShareContext(ctx context.Context, path string, refereeClientID string) error {
+	err := a.revokeShare(ctx, path, refereeClientID)
+	if err != nil && !isShareNotFound(err) {
+		return err
+	}
+	if rerr := a.markRevoked(path, refereeClientID); rerr != nil {
+		l.Logger.Error("Record revoked share: ", rerr)
+	}
+	return err
+}
+
+var errShareNotFound = errors.New("", "share not found")
+
+func (a *Allocation) revokeShare(ctx context.Context, path string, refereeClientID string) error {
+	ctx, cncl := context.WithCancel(ctx)
+	defer cncl()
+
 	success := make(chan int, len(a.Blobbers))
 	notFound := make(chan int, len(a.Blobbers))
 	wg := &sync.WaitGroup{}
@@ -1790,7 +1996,7 @@ func (a *Allocation) RevokeShare(path string, refereeClientID string) error {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := zboxutil.HttpDo(a.ctx, a.ctxCancelF, httpreq, func(resp *http.Response, err error) error {
+			err := zboxutil.HttpDo(ctx, cncl, httpreq, func(resp *http.Response, err error) error {
 				if err != nil {
 					l.Logger.Error("Revoke share : ", err)
 					return err
@@ -1824,7 +2030,7 @@ func (a *Allocation) RevokeShare(path string, refereeClientID string) error {
 	wg.Wait()
 	if len(success) == len(a.Blobbers) {
 		if len(notFound) == len(a.Blobbers) {
-			return errors.New("", "share not found")
+			return errShareNotFound
 		}
 		return nil
 	}
@@ -1836,6 +2042,13 @@ var ErrInvalidPrivateShare = errors.New("invalid_private_share", "private sharin
 func (a *Allocation) GetAuthTicket(path, filename string,
 	referenceType, refereeClientID, refereeEncryptionPublicKey string, expiration int64, availableAfter *time.Time) (string, error) {
 
+	return a.GetAuthTicketContext(a.ctx, path, filename, referenceType, refereeClientID, refereeEncryptionPublicKey, expiration, availableAfter)
+}
+
+// GetAuthTicketContext is GetAuthTicket bound to ctx.
+func (a *Allocation) GetAuthTicketContext(ctx context.Context, path, filename string,
+	referenceType, refereeClientID, refereeEncryptionPublicKey string, expiration int64, availableAfter *time.Time) (string, error) {
+
 	if !a.isInitialized() {
 		return "", notInitialized
 	}
@@ -1850,8 +2063,12 @@ func (a *Allocation) GetAuthTicket(path, filename string,
 		return "", errors.New("invalid_path", "Path should be valid and absolute")
 	}
 
+	if refereeClientID != "" && exposesShareStore(path) {
+		return "", ErrShareStoreExposed
+	}
+
 	if referenceType == fileref.FILE && refereeClientID != "" {
-		fileMeta, err := a.GetFileMeta(path)
+		fileMeta, err := a.GetFileMetaContext(ctx, path)
 		if err != nil {
 			return "", err
 		}
@@ -1867,7 +2084,7 @@ func (a *Allocation) GetAuthTicket(path, filename string,
 		allocationID:      a.ID,
 		allocationTx:      a.Tx,
 		blobbers:          a.Blobbers,
-		ctx:               a.ctx,
+		ctx:               ctx,
 		remotefilepath:    path,
 		remotefilename:    filename,
 	}
@@ -1888,7 +2105,7 @@ func (a *Allocation) GetAuthTicket(path, filename string,
 		return "", err
 	}
 
-	if err := a.UploadAuthTicketToBlobber(string(atBytes), refereeEncryptionPublicKey, availableAfter); err != nil {
+	if err := a.UploadAuthTicketToBlobberContext(ctx, string(atBytes), refereeEncryptionPublicKey, availableAfter); err != nil {
 		return "", err
 	}
 
@@ -1901,11 +2118,38 @@ func (a *Allocation) GetAuthTicket(path, filename string,
 	if err != nil {
 		return "", err
 	}
+	ticket := base64.StdEncoding.EncodeToString(atBytes)
 
-	return base64.StdEncoding.EncodeToString(atBytes), nil
+	record := &ShareRecord{
+		Path:                       path,
+		FileName:                   filename,
+		RefType:                    shareReq.refType,
+		RefereeClientID:            refereeClientID,
+		RefereeEncryptionPublicKey: refereeEncryptionPublicKey,
+		AuthTicket:                 ticket,
+		CreatedAt:                  common.Timestamp(aTicket.Timestamp),
+		Expiration:                 common.Timestamp(aTicket.Expiration),
+	}
+	if availableAfter != nil {
+		record.AvailableAfter = availableAfter.Unix()
+	}
+	// the ticket is issued, failing to record it doesn't revoke it
+	if err := a.recordShare(record); err != nil {
+		l.Logger.Error("Record share: ", err)
+	}
+
+	return ticket, nil
 }
 
 func (a *Allocation) UploadAuthTicketToBlobber(authTicket string, clientEncPubKey string, availableAfter *time.Time) error {
+	return a.UploadAuthTicketToBlobberContext(a.ctx, authTicket, clientEncPubKey, availableAfter)
+}
+
+// UploadAuthTicketToBlobberContext is UploadAuthTicketToBlobber bound to ctx.
+func (a *Allocation) UploadAuthTicketToBlobberContext(ctx context.Context, authTicket string, clientEncPubKey string, availableAfter *time.Time) error {
+	ctx, cncl := context.WithCancel(ctx)
+	defer cncl()
+
 	success := make(chan int, len(a.Blobbers))
 	wg := &sync.WaitGroup{}
 	for idx := range a.Blobbers {
@@ -1936,7 +2180,7 @@ func (a *Allocation) UploadAuthTicketToBlobber(authTicket string, clientEncPubKe
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := zboxutil.HttpDo(a.ctx, a.ctxCancelF, httpreq, func(resp *http.Response, err error) error {
+			err := zboxutil.HttpDo(ctx, cncl, httpreq, func(resp *http.Response, err error) error {
 				if err != nil {
 					l.Logger.Error("Insert share info : ", err)
 					return err
@@ -2108,7 +2352,23 @@ func (a *Allocation) DownloadFileToFileHandlerFromAuthTicket(
 	status StatusCallback,
 	isFinal bool,
 ) error {
-	return a.downloadFromAuthTicket(fileHandler, authTicket, remoteLookupHash, 1, 0, numBlockDownloads,
+	return a.DownloadFileToFileHandlerFromAuthTicketContext(a.ctx, fileHandler, authTicket, remoteLookupHash, remoteFilename, verifyDownload, status, isFinal)
+}
+
+// DownloadFileToFileHandlerFromAuthTicketContext is
+// DownloadFileToFileHandlerFromAuthTicket bound to ctx: canceling ctx
+// cancels the download.
+func (a *Allocation) DownloadFileToFileHandlerFromAuthTicketContext(
+	ctx context.Context,
+	fileHandler sys.File,
+	authTicket string,
+	remoteLookupHash string,
+	remoteFilename string,
+	verifyDownload bool,
+	status StatusCallback,
+	isFinal bool,
+) error {
+	return a.downloadFromAuthTicket(ctx, fileHandler, authTicket, remoteLookupHash, 1, 0, numBlockDownloads,
 		remoteFilename, DOWNLOAD_CONTENT_FULL, verifyDownload, status, isFinal, "")
 }
 
@@ -2123,7 +2383,26 @@ func (a *Allocation) DownloadByBlocksToFileHandlerFromAuthTicket(
 	status StatusCallback,
 	isFinal bool,
 ) error {
-	return a.downloadFromAuthTicket(fileHandler, authTicket, remoteLookupHash, startBlock, endBlock, numBlocks,
+	return a.DownloadByBlocksToFileHandlerFromAuthTicketContext(a.ctx, fileHandler, authTicket, remoteLookupHash, startBlock, endBlock, numBlocks,
+		remoteFilename, verifyDownload, status, isFinal)
+}
+
+// DownloadByBlocksToFileHandlerFromAuthTicketContext is
+// DownloadByBlocksToFileHandlerFromAuthTicket bound to ctx: canceling ctx
+// cancels the download.
+func (a *Allocation) DownloadByBlocksToFileHandlerFromAuthTicketContext(
+	ctx context.Context,
+	fileHandler sys.File,
+	authTicket string,
+	remoteLookupHash string,
+	startBlock, endBlock int64,
+	numBlocks int,
+	remoteFilename string,
+	verifyDownload bool,
+	status StatusCallback,
+	isFinal bool,
+) error {
+	return a.downloadFromAuthTicket(ctx, fileHandler, authTicket, remoteLookupHash, startBlock, endBlock, numBlocks,
 		remoteFilename, DOWNLOAD_CONTENT_FULL, verifyDownload, status, isFinal, "")
 }
 
@@ -2136,7 +2415,23 @@ func (a *Allocation) DownloadThumbnailToFileHandlerFromAuthTicket(
 	status StatusCallback,
 	isFinal bool,
 ) error {
-	return a.downloadFromAuthTicket(fileHandler, authTicket, remoteLookupHash, 1, 0, numBlockDownloads,
+	return a.DownloadThumbnailToFileHandlerFromAuthTicketContext(a.ctx, fileHandler, authTicket, remoteLookupHash, remoteFilename, verifyDownload, status, isFinal)
+}
+
+// DownloadThumbnailToFileHandlerFromAuthTicketContext is
+// DownloadThumbnailToFileHandlerFromAuthTicket bound to ctx: canceling ctx
+// cancels the download.
+func (a *Allocation) DownloadThumbnailToFileHandlerFromAuthTicketContext(
+	ctx context.Context,
+	fileHandler sys.File,
+	authTicket string,
+	remoteLookupHash string,
+	remoteFilename string,
+	verifyDownload bool,
+	status StatusCallback,
+	isFinal bool,
+) error {
+	return a.downloadFromAuthTicket(ctx, fileHandler, authTicket, remoteLookupHash, 1, 0, numBlockDownloads,
 		remoteFilename, DOWNLOAD_CONTENT_THUMB, verifyDownload, status, isFinal, "")
 }
 
@@ -2148,13 +2443,29 @@ func (a *Allocation) DownloadThumbnailFromAuthTicket(
 	verifyDownload bool,
 	status StatusCallback,
 	isFinal bool,
+) error {
+	return a.DownloadThumbnailFromAuthTicketContext(a.ctx, localPath, authTicket, remoteLookupHash, remoteFilename, verifyDownload, status, isFinal)
+}
+
+// DownloadThumbnailFromAuthTicketContext is
+// DownloadThumbnailFromAuthTicket bound to ctx: canceling ctx
+// cancels the download.
+func (a *Allocation) DownloadThumbnailFromAuthTicketContext(
+	ctx context.Context,
+	localPath string,
+	authTicket string,
+	remoteLookupHash string,
+	remoteFilename string,
+	verifyDownload bool,
+	status StatusCallback,
+	isFinal bool,
 ) error {
 	f, localFilePath, toKeep, err := a.prepareAndOpenLocalFile(localPath, remoteFilename)
 	if err != nil {
 		return err
 	}
 
-	err = a.downloadFromAuthTicket(f, authTicket, remoteLookupHash, 1, 0, numBlockDownloads, remoteFilename,
+	err = a.downloadFromAuthTicket(ctx, f, authTicket, remoteLookupHash, 1, 0, numBlockDownloads, remoteFilename,
 		DOWNLOAD_CONTENT_THUMB, verifyDownload, status, isFinal, localFilePath)
 	if err != nil {
 		if !toKeep {
@@ -2167,13 +2478,20 @@ func (a *Allocation) DownloadThumbnailFromAuthTicket(
 }
 
 func (a *Allocation) DownloadFromAuthTicket(localPath string, authTicket string,
+	remoteLookupHash string, remoteFilename string, verifyDownload bool, status StatusCallback, isFinal bool) error {
+	return a.DownloadFromAuthTicketContext(a.ctx, localPath, authTicket, remoteLookupHash, remoteFilename, verifyDownload, status, isFinal)
+}
+
+// DownloadFromAuthTicketContext is DownloadFromAuthTicket bound to ctx:
+// canceling ctx cancels the download.
+func (a *Allocation) DownloadFromAuthTicketContext(ctx context.Context, localPath string, authTicket string,
 	remoteLookupHash string, remoteFilename string, verifyDownload bool, status StatusCallback, isFinal bool) error {
 	f, localFilePath, toKeep, err := a.prepareAndOpenLocalFile(localPath, remoteFilename)
 	if err != nil {
 		return err
 	}
 
-	err = a.downloadFromAuthTicket(f, authTicket, remoteLookupHash, 1, 0, numBlockDownloads, remoteFilename,
+	err = a.downloadFromAuthTicket(ctx, f, authTicket, remoteLookupHash, 1, 0, numBlockDownloads, remoteFilename,
 		DOWNLOAD_CONTENT_FULL, verifyDownload, status, isFinal, localFilePath)
 	if err != nil {
 		if !toKeep {
@@ -2189,13 +2507,23 @@ func (a *Allocation) DownloadFromAuthTicketByBlocks(localPath string,
 	authTicket string, startBlock int64, endBlock int64, numBlocks int,
 	remoteLookupHash string, remoteFilename string, verifyDownload bool,
 	status StatusCallback, isFinal bool) error {
+	return a.DownloadFromAuthTicketByBlocksContext(a.ctx, localPath, authTicket, startBlock, endBlock, numBlocks,
+		remoteLookupHash, remoteFilename, verifyDownload, status, isFinal)
+}
+
+// DownloadFromAuthTicketByBlocksContext is DownloadFromAuthTicketByBlocks
+// bound to ctx: canceling ctx cancels the download.
+func (a *Allocation) DownloadFromAuthTicketByBlocksContext(ctx context.Context, localPath string,
+	authTicket string, startBlock int64, endBlock int64, numBlocks int,
+	remoteLookupHash string, remoteFilename string, verifyDownload bool,
+	status StatusCallback, isFinal bool) error {
 
 	f, localFilePath, toKeep, err := a.prepareAndOpenLocalFile(localPath, remoteFilename)
 	if err != nil {
 		return err
 	}
 
-	err = a.downloadFromAuthTicket(f, authTicket, remoteLookupHash, startBlock, endBlock, numBlockDownloads, remoteFilename,
+	err = a.downloadFromAuthTicket(ctx, f, authTicket, remoteLookupHash, startBlock, endBlock, numBlockDownloads, remoteFilename,
 		DOWNLOAD_CONTENT_FULL, verifyDownload, status, isFinal, localFilePath)
 	if err != nil {
 		if !toKeep {
@@ -2207,7 +2535,7 @@ func (a *Allocation) DownloadFromAuthTicketByBlocks(localPath string,
 	return nil
 }
 
-func (a *Allocation) downloadFromAuthTicket(fileHandler sys.File, authTicket string,
+func (a *Allocation) downloadFromAuthTicket(ctx context.Context, fileHandler sys.File, authTicket string,
 	remoteLookupHash string, startBlock int64, endBlock int64, numBlocks int,
 	remoteFilename string, contentMode string, verifyDownload bool,
 	status StatusCallback, isFinal bool, localFilePath string) error {
@@ -2232,7 +2560,7 @@ func (a *Allocation) downloadFromAuthTicket(fileHandler sys.File, authTicket str
 	downloadReq.allocationTx = a.Tx
 	downloadReq.allocOwnerID = a.Owner
 	downloadReq.allocOwnerPubKey = a.OwnerPublicKey
-	downloadReq.ctx, downloadReq.ctxCncl = context.WithCancel(a.ctx)
+	downloadReq.ctx, downloadReq.ctxCncl = context.WithCancel(ctx)
 	downloadReq.fileHandler = fileHandler
 	downloadReq.localFilePath = localFilePath
 	downloadReq.remotefilepathhash = remoteLookupHash
@@ -0,0 +1,195 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0chain/gosdk/zboxcore/blockchain"
+	"github.com/0chain/gosdk/zboxcore/client"
+	"github.com/0chain/gosdk/zboxcore/zboxutil"
+	"github.com/stretchr/testify/require"
+)
+
+// useHTTPClient sends the blobber requests over http until the end of the
+// test, instead of the mocked client some tests leave set.
+func useHTTPClient(t *testing.T) {
+	previous := zboxutil.Client
+	zboxutil.Client = &http.Client{Transport: zboxutil.DefaultTransport}
+	t.Cleanup(func() {
+		zboxutil.Client = previous
+	})
+}
+
+func TestAllocationContextCanceled(t *testing.T) {
+	useHTTPClient(t)
+	tests := []struct {
+		name string
+		call func(a *Allocation, ctx context.Context) error
+	}{
+		{
+			name: "ListDirContext",
+			call: func(a *Allocation, ctx context.Context) error {
+				_, err := a.ListDirContext(ctx, "/")
+				return err
+			},
+		},
+		{
+			name: "GetRefsContext",
+			call: func(a *Allocation, ctx context.Context) error {
+				_, err := a.GetRefsContext(ctx, "/", "", "", "", "", "regular", 0, 1)
+				return err
+			},
+		},
+		{
+			name: "RevokeShareContext",
+			call: func(a *Allocation, ctx context.Context) error {
+				return a.RevokeShareContext(ctx, "/file", "referee")
+			},
+		},
+		{
+			name: "UploadAuthTicketToBlobberContext",
+			call: func(a *Allocation, ctx context.Context) error {
+				return a.UploadAuthTicketToBlobberContext(ctx, "ticket", "", nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			arrived := make(chan struct{}, 4)
+			release := make(chan struct{})
+			defer close(release)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				// the connection is watched for a close once the body is read
+				io.Copy(io.Discard, r.Body) //nolint: errcheck
+				arrived <- struct{}{}
+				select {
+				case <-r.Context().Done():
+				case <-release:
+				}
+			}))
+			defer server.Close()
+
+			a := &Allocation{ID: "alloc", Tx: "alloc", DataShards: 2, ParityShards: 1}
+			setupMockAllocation(t, a)
+			for i := 0; i < 3; i++ {
+				a.Blobbers = append(a.Blobbers, &blockchain.StorageNode{
+					ID:      "blobber_" + strconv.Itoa(i),
+					Baseurl: server.URL + "/" + strconv.Itoa(i),
+				})
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				<-arrived
+				cancel()
+			}()
+
+			done := make(chan error, 1)
+			go func() {
+				done <- tt.call(a, ctx)
+			}()
+			select {
+			case err := <-done:
+				require.Error(t, err)
+			case <-time.After(10 * time.Second):
+				t.Fatalf("%s didn't return when its context was canceled", tt.name)
+			}
+			require.NotZero(t, atomic.LoadInt32(&requests))
+			// the allocation itself isn't canceled
+			require.NoError(t, a.ctx.Err())
+		})
+	}
+}
+
+func TestWriteMarkerMutexUnlockDetached(t *testing.T) {
+	var mu sync.Mutex
+	unlocked := make(map[string]bool)
+	onUnlock := func() {}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			mu.Lock()
+			onUnlock()
+			unlocked[r.URL.Path] = true
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	a := &Allocation{ID: "alloc", Tx: "alloc", DataShards: 2, ParityShards: 1}
+	setupMockAllocation(t, a)
+	for i := 0; i < 3; i++ {
+		a.Blobbers = append(a.Blobbers, &blockchain.StorageNode{
+			ID:      "blobber_" + strconv.Itoa(i),
+			Baseurl: server.URL + "/" + strconv.Itoa(i),
+		})
+	}
+	mutex, err := CreateWriteMarkerMutex(client.GetClient(), a)
+	require.NoError(t, err)
+
+	// the operation was canceled, the locks are released anyway
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mask := zboxutil.NewUint128(1).Lsh(uint64(len(a.Blobbers))).Sub64(1)
+	mutex.Unlock(ctx, mask, a.Blobbers, time.Minute, "conn")
+	require.Len(t, unlocked, len(a.Blobbers))
+
+	// the operation is canceled while the locks are released
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	mu.Lock()
+	unlocked = make(map[string]bool)
+	onUnlock = func() {
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Unlock()
+	mutex, err = CreateWriteMarkerMutex(client.GetClient(), a)
+	require.NoError(t, err)
+	mutex.Unlock(ctx, mask, a.Blobbers, time.Minute, "conn")
+	require.Len(t, unlocked, len(a.Blobbers))
+}
+
+func TestChunkedUploadWithContext(t *testing.T) {
+	a := &Allocation{ID: "alloc", Tx: "alloc", DataShards: 2, ParityShards: 1, Size: 1 << 20}
+	setupMockAllocation(t, a)
+	for i := 0; i < 3; i++ {
+		a.Blobbers = append(a.Blobbers, &blockchain.StorageNode{
+			ID:      "blobber_" + strconv.Itoa(i),
+			Baseurl: "http://blobber_" + strconv.Itoa(i),
+		})
+	}
+	fileMeta := FileMeta{
+		Path:       "/tmp/test.txt",
+		ActualSize: 4,
+		MimeType:   "plain/text",
+		RemoteName: "test.txt",
+		RemotePath: "/test.txt",
+	}
+
+	su, err := CreateChunkedUpload(t.TempDir(), a, fileMeta, bytes.NewReader([]byte("test")), false, false, false, zboxutil.NewConnectionId())
+	require.NoError(t, err)
+	require.NoError(t, su.ctx.Err())
+	a.ctxCancelF()
+	require.Error(t, su.ctx.Err(), "the upload is bound to the allocation by default")
+
+	setupMockAllocation(t, a)
+	ctx, cancel := context.WithCancel(context.Background())
+	su, err = CreateChunkedUpload(t.TempDir(), a, fileMeta, bytes.NewReader([]byte("test")), false, false, false, zboxutil.NewConnectionId(), WithContext(ctx))
+	require.NoError(t, err)
+	require.NoError(t, su.ctx.Err())
+	cancel()
+	require.Error(t, su.ctx.Err())
+	require.NoError(t, a.ctx.Err())
+}
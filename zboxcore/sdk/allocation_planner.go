@@ -0,0 +1,291 @@
+package sdk
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/common"
+)
+
+const (
+	// defaultPlannerMaxDataShards caps the data shards tried by the planner
+	// when AllocationRequirements.MaxDataShards is not set.
+	defaultPlannerMaxDataShards = 10
+	// plannerMonth is the period the planner uses for monthly traffic.
+	plannerMonth = 30 * 24 * time.Hour
+)
+
+// AllocationRequirements describes what a new allocation has to provide.
+// It is the input of PlanAllocation.
+type AllocationRequirements struct {
+	// DataSize is the amount of data, in bytes, the allocation should hold.
+	DataSize int64
+	// ReadsPerMonth is the number of bytes expected to be downloaded per month.
+	ReadsPerMonth int64
+	// WritesPerMonth is the number of bytes expected to be uploaded per month.
+	WritesPerMonth int64
+	// Durability is the number of blobbers that may be lost without losing
+	// data. It is the minimal number of parity shards of a plan.
+	Durability int
+	// Budget is the maximal amount of tokens (in SAS) the caller is ready
+	// to lock for the first time unit. Zero means no limit.
+	Budget uint64
+	// MaxDataShards limits the data shards tried by the planner.
+	MaxDataShards int
+	// MinStake filters out blobbers with less total stake.
+	MinStake int64
+}
+
+// AllocationPlan is a proposed allocation layout with its cost estimation.
+// All costs are in SAS.
+type AllocationPlan struct {
+	DataShards   int
+	ParityShards int
+	Size         int64
+	Blobbers     []*Blobber
+	ReadPrice    PriceRange
+	WritePrice   PriceRange
+
+	// StorageCost is the cost of the allocation size for one time unit.
+	// It is the amount to lock in the write pool on creation.
+	StorageCost common.Balance
+	// MonthlyWriteCost is the estimated cost of the monthly uploads.
+	MonthlyWriteCost common.Balance
+	// MonthlyReadCost is the estimated cost of the monthly downloads.
+	MonthlyReadCost common.Balance
+	// Lock is the amount to lock with CreateAllocationWith. It covers the
+	// storage cost and a month of uploads, and is never below the allocation
+	// min lock (see GetAllocationMinLock).
+	Lock uint64
+	// ReadPoolLock is the amount to lock with ReadPoolLock for a month of reads.
+	ReadPoolLock uint64
+	// WithinBudget is false when Lock and ReadPoolLock exceed the budget.
+	WithinBudget bool
+}
+
+// TotalCost returns all the tokens the plan requires to be locked.
+func (p *AllocationPlan) TotalCost() uint64 {
+	return p.Lock + p.ReadPoolLock
+}
+
+// BlobberIds returns the ids of the selected blobbers.
+func (p *AllocationPlan) BlobberIds() []string {
+	return blobberIDs(p.Blobbers)
+}
+
+// CreateOptions converts the plan into options for CreateAllocationWith.
+func (p *AllocationPlan) CreateOptions() CreateAllocationOptions {
+	return CreateAllocationOptions{
+		DataShards:   p.DataShards,
+		ParityShards: p.ParityShards,
+		Size:         p.Size,
+		ReadPrice:    p.ReadPrice,
+		WritePrice:   p.WritePrice,
+		Lock:         p.Lock,
+		BlobberIds:   p.BlobberIds(),
+	}
+}
+
+// AllocationPlanDiff is the what-if difference of a plan against a base plan.
+// Positive values mean the plan is more expensive or more durable.
+type AllocationPlanDiff struct {
+	DataShards       int
+	ParityShards     int
+	StorageCost      int64
+	MonthlyWriteCost int64
+	MonthlyReadCost  int64
+	TotalCost        int64
+}
+
+// Compare returns the difference between the plan and the given base plan.
+func (p *AllocationPlan) Compare(base *AllocationPlan) AllocationPlanDiff {
+	return AllocationPlanDiff{
+		DataShards:       p.DataShards - base.DataShards,
+		ParityShards:     p.ParityShards - base.ParityShards,
+		StorageCost:      int64(p.StorageCost) - int64(base.StorageCost),
+		MonthlyWriteCost: int64(p.MonthlyWriteCost) - int64(base.MonthlyWriteCost),
+		MonthlyReadCost:  int64(p.MonthlyReadCost) - int64(base.MonthlyReadCost),
+		TotalCost:        int64(p.TotalCost()) - int64(base.TotalCost()),
+	}
+}
+
+// AllocationPlans is a list of plans, ordered from the cheapest one.
+type AllocationPlans []*AllocationPlan
+
+// Best returns the cheapest plan within budget, or nil if none fits.
+func (ps AllocationPlans) Best() *AllocationPlan {
+	for _, p := range ps {
+		if p.WithinBudget {
+			return p
+		}
+	}
+	return nil
+}
+
+// WhatIf returns the difference of every plan against the best one.
+func (ps AllocationPlans) WhatIf() []AllocationPlanDiff {
+	best := ps.Best()
+	if best == nil {
+		return nil
+	}
+	diffs := make([]AllocationPlanDiff, 0, len(ps))
+	for _, p := range ps {
+		diffs = append(diffs, p.Compare(best))
+	}
+	return diffs
+}
+
+// PlanAllocation estimates the costs of the possible data/parity layouts for
+// the given requirements, using the active blobbers of the network and the
+// storage SC time unit. The returned plans are sorted by total cost; use Best
+// to pick one and CreateOptions to create the allocation.
+func PlanAllocation(req AllocationRequirements) (AllocationPlans, error) {
+	if !sdkInitialized {
+		return nil, sdkNotInitialized
+	}
+
+	blobbers, err := GetBlobbers(true)
+	if err != nil {
+		return nil, err
+	}
+
+	timeUnit, err := getStorageTimeUnit()
+	if err != nil {
+		return nil, err
+	}
+
+	return planAllocation(req, blobbers, timeUnit)
+}
+
+func planAllocation(req AllocationRequirements, blobbers []*Blobber, timeUnit time.Duration) (AllocationPlans, error) {
+	if req.DataSize <= 0 {
+		return nil, errors.New("invalid_requirements", "data size must be positive")
+	}
+	if req.Durability < 0 {
+		return nil, errors.New("invalid_requirements", "durability can't be negative")
+	}
+	if timeUnit <= 0 {
+		return nil, errors.New("invalid_time_unit", "time unit must be positive")
+	}
+
+	maxData := req.MaxDataShards
+	if maxData <= 0 {
+		maxData = defaultPlannerMaxDataShards
+	}
+	// allocations without parity shards are not accepted by the chain
+	minParity := req.Durability
+	if minParity < 1 {
+		minParity = 1
+	}
+
+	var plans AllocationPlans
+	for data := 1; data <= maxData; data++ {
+		for parity := minParity; parity <= minParity+1; parity++ {
+			shardSize := (req.DataSize + int64(data) - 1) / int64(data)
+			selected := selectPlanBlobbers(blobbers, data+parity, shardSize, req.MinStake)
+			if selected == nil {
+				continue
+			}
+			p, err := newAllocationPlan(req, data, parity, selected, timeUnit)
+			if err != nil {
+				return nil, err
+			}
+			plans = append(plans, p)
+		}
+	}
+
+	if len(plans) == 0 {
+		return nil, errors.New("not_enough_blobbers", "no blobbers match the requirements")
+	}
+
+	sort.SliceStable(plans, func(i, j int) bool {
+		if plans[i].TotalCost() == plans[j].TotalCost() {
+			return plans[i].ParityShards > plans[j].ParityShards
+		}
+		return plans[i].TotalCost() < plans[j].TotalCost()
+	})
+	return plans, nil
+}
+
+// selectPlanBlobbers picks the n cheapest blobbers able to store shardSize
+// bytes. More staked blobbers are preferred at equal prices.
+func selectPlanBlobbers(blobbers []*Blobber, n int, shardSize, minStake int64) []*Blobber {
+	candidates := make([]*Blobber, 0, len(blobbers))
+	for _, b := range blobbers {
+		if b.IsKilled || b.IsShutdown || b.NotAvailable {
+			continue
+		}
+		if int64(b.Capacity-b.Allocated) < shardSize {
+			continue
+		}
+		if b.TotalStake < minStake {
+			continue
+		}
+		candidates = append(candidates, b)
+	}
+	if len(candidates) < n {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		bi, bj := candidates[i], candidates[j]
+		if bi.Terms.WritePrice != bj.Terms.WritePrice {
+			return bi.Terms.WritePrice < bj.Terms.WritePrice
+		}
+		if bi.Terms.ReadPrice != bj.Terms.ReadPrice {
+			return bi.Terms.ReadPrice < bj.Terms.ReadPrice
+		}
+		return bi.TotalStake > bj.TotalStake
+	})
+	return candidates[:n]
+}
+
+func newAllocationPlan(req AllocationRequirements, data, parity int, blobbers []*Blobber, timeUnit time.Duration) (*AllocationPlan, error) {
+	p := &AllocationPlan{
+		DataShards:   data,
+		ParityShards: parity,
+		Size:         req.DataSize,
+		Blobbers:     blobbers,
+	}
+
+	var sumRead, sumWrite float64
+	for _, b := range blobbers {
+		if uint64(b.Terms.ReadPrice) > p.ReadPrice.Max {
+			p.ReadPrice.Max = uint64(b.Terms.ReadPrice)
+		}
+		if uint64(b.Terms.WritePrice) > p.WritePrice.Max {
+			p.WritePrice.Max = uint64(b.Terms.WritePrice)
+		}
+		sumRead += float64(b.Terms.ReadPrice)
+		sumWrite += float64(b.Terms.WritePrice)
+	}
+
+	// every blobber stores a shard of size/data, prices are per GB
+	shardGB := float64((req.DataSize+int64(data)-1)/int64(data)) / GB
+	p.StorageCost = common.Balance(math.Ceil(sumWrite * shardGB))
+
+	// uploads are spread over all the blobbers, and the storage is paid
+	// for the months it's kept in the time units of the storage SC
+	unitsPerMonth := float64(plannerMonth) / float64(timeUnit)
+	writeShardGB := float64(req.WritesPerMonth) / float64(data) / GB
+	p.MonthlyWriteCost = common.Balance(math.Ceil(sumWrite * writeShardGB * unitsPerMonth))
+
+	// downloads are served by data shards only
+	avgRead := sumRead / float64(len(blobbers))
+	p.MonthlyReadCost = common.Balance(math.Ceil(avgRead * float64(req.ReadsPerMonth) / GB))
+
+	// the chain refuses locks below the allocation min lock
+	minLock, err := allocationMinLock(data, parity, req.DataSize, p.ReadPrice, p.WritePrice, timeUnit)
+	if err != nil {
+		return nil, err
+	}
+	p.Lock = uint64(p.StorageCost + p.MonthlyWriteCost)
+	if uint64(minLock) > p.Lock {
+		p.Lock = uint64(minLock)
+	}
+	p.ReadPoolLock = uint64(p.MonthlyReadCost)
+	p.WithinBudget = req.Budget == 0 || p.TotalCost() <= req.Budget
+	return p, nil
+}
@@ -0,0 +1,116 @@
+package sdk
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/0chain/gosdk/core/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newPlannerBlobbers(n int) []*Blobber {
+	blobbers := make([]*Blobber, 0, n)
+	for i := 0; i < n; i++ {
+		blobbers = append(blobbers, &Blobber{
+			ID:         common.Key("blobber" + strconv.Itoa(i)),
+			Capacity:   common.Size(100 * GB),
+			TotalStake: int64(i + 1),
+			Terms: Terms{
+				ReadPrice:  common.Balance(1e9),
+				WritePrice: common.Balance(1e9),
+			},
+		})
+	}
+	return blobbers
+}
+
+func TestPlanAllocation(t *testing.T) {
+	blobbers := newPlannerBlobbers(6)
+
+	t.Run("Cheapest blobbers", func(t *testing.T) {
+		plans, err := planAllocation(AllocationRequirements{
+			DataSize:      2 * GB,
+			Durability:    1,
+			MaxDataShards: 2,
+		}, blobbers, time.Hour)
+		require.NoError(t, err)
+		require.Len(t, plans, 4)
+
+		best := plans.Best()
+		require.NotNil(t, best)
+		require.Equal(t, 2, best.DataShards)
+		require.Equal(t, 1, best.ParityShards)
+		require.Equal(t, []string{"blobber5", "blobber4", "blobber3"}, best.BlobberIds())
+		require.EqualValues(t, 3e9, best.StorageCost)
+		require.EqualValues(t, 1e9, best.WritePrice.Max)
+
+		opts := best.CreateOptions()
+		require.Equal(t, best.Lock, opts.Lock)
+		require.Len(t, opts.BlobberIds, 3)
+
+		diffs := plans.WhatIf()
+		require.Len(t, diffs, len(plans))
+		require.Zero(t, diffs[0].TotalCost)
+		for _, d := range diffs {
+			require.GreaterOrEqual(t, d.TotalCost, int64(0))
+		}
+	})
+
+	t.Run("Budget", func(t *testing.T) {
+		plans, err := planAllocation(AllocationRequirements{
+			DataSize:   2 * GB,
+			Durability: 1,
+			Budget:     1,
+		}, blobbers, time.Hour)
+		require.NoError(t, err)
+		require.Nil(t, plans.Best())
+		require.Nil(t, plans.WhatIf())
+	})
+
+	t.Run("Capacity and stake", func(t *testing.T) {
+		_, err := planAllocation(AllocationRequirements{
+			DataSize:   2 * GB,
+			Durability: 1,
+			MinStake:   7,
+		}, blobbers, time.Hour)
+		require.Error(t, err)
+
+		_, err = planAllocation(AllocationRequirements{
+			DataSize:   1000 * GB,
+			Durability: 1,
+		}, blobbers, time.Hour)
+		require.Error(t, err)
+	})
+
+	t.Run("Min lock", func(t *testing.T) {
+		// a 30 days time unit makes the min lock cover a single time unit
+		plans, err := planAllocation(AllocationRequirements{
+			DataSize:      2 * GB,
+			Durability:    1,
+			MaxDataShards: 2,
+		}, blobbers, 720*time.Hour)
+		require.NoError(t, err)
+
+		best := plans.Best()
+		require.EqualValues(t, 3e9, best.StorageCost)
+		require.EqualValues(t, 6e9, best.Lock)
+		for _, p := range plans {
+			minLock, err := allocationMinLock(p.DataShards, p.ParityShards, p.Size, p.ReadPrice, p.WritePrice, 720*time.Hour)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, p.Lock, uint64(minLock))
+		}
+	})
+
+	t.Run("Reads", func(t *testing.T) {
+		plans, err := planAllocation(AllocationRequirements{
+			DataSize:      GB,
+			ReadsPerMonth: 10 * GB,
+			Durability:    1,
+			MaxDataShards: 1,
+		}, blobbers, time.Hour)
+		require.NoError(t, err)
+		require.EqualValues(t, 10e9, plans[0].MonthlyReadCost)
+		require.EqualValues(t, 10e9, plans[0].ReadPoolLock)
+	})
+}
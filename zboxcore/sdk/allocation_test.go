@@ -1772,7 +1772,7 @@ func TestAllocation_downloadFromAuthTicket(t *testing.T) {
 
 			if err == nil {
 				err = a.downloadFromAuthTicket(
-					f, tt.parameters.authTicket, tt.parameters.lookupHash,
+					context.Background(), f, tt.parameters.authTicket, tt.parameters.lookupHash,
 					tt.parameters.startBlock, tt.parameters.endBlock, tt.parameters.numBlocks,
 					tt.parameters.remoteFilename, tt.parameters.contentMode, true, tt.parameters.statusCallback, false, localFilePath)
 			}
@@ -0,0 +1,208 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TrafficPriority is the priority class of a transfer. When a bandwidth limit
+// is reached, waiting transfers of a higher priority (lower value) are served
+// before the others.
+type TrafficPriority int
+
+const (
+	// PriorityInteractive is used by uploads and downloads requested by the user.
+	PriorityInteractive TrafficPriority = iota
+	// PriorityRepair is used by the repair downloads and uploads.
+	PriorityRepair
+
+	numTrafficPriorities = int(PriorityRepair) + 1
+)
+
+// maxRateLimiterWait bounds a single sleep of a waiter, so that it notices
+// limit changes and higher priority waiters leaving.
+const maxRateLimiterWait = 100 * time.Millisecond
+
+// RateLimiter is a token bucket shared by transfers of different priorities.
+// A token is a byte. Transfers larger than the bucket are allowed to borrow
+// tokens, later transfers wait until the debt is paid back.
+type RateLimiter struct {
+	mu      sync.Mutex
+	rate    float64 // tokens per second, 0 means unlimited
+	burst   float64
+	tokens  float64
+	last    time.Time
+	waiting [numTrafficPriorities]int
+}
+
+// NewRateLimiter creates a limiter allowing bytesPerSecond, with bursts of one
+// second of traffic. Zero bytesPerSecond disables the limit.
+func NewRateLimiter(bytesPerSecond int64) *RateLimiter {
+	rl := &RateLimiter{}
+	rl.SetLimit(bytesPerSecond)
+	return rl
+}
+
+// SetLimit changes the rate of the limiter. Zero disables the limit.
+func (rl *RateLimiter) SetLimit(bytesPerSecond int64) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if bytesPerSecond < 0 {
+		bytesPerSecond = 0
+	}
+	rl.rate = float64(bytesPerSecond)
+	rl.burst = rl.rate
+	rl.tokens = rl.burst
+	rl.last = time.Now()
+}
+
+// Limit returns the rate of the limiter in bytes per second.
+func (rl *RateLimiter) Limit() int64 {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return int64(rl.rate)
+}
+
+// Wait blocks until n bytes can be transferred with the given priority, or
+// until ctx is done.
+func (rl *RateLimiter) Wait(ctx context.Context, n int, prio TrafficPriority) error {
+	if rl == nil || n <= 0 {
+		return nil
+	}
+	if prio < 0 || int(prio) >= numTrafficPriorities {
+		prio = PriorityRepair
+	}
+
+	registered := false
+	defer func() {
+		if registered {
+			rl.mu.Lock()
+			rl.waiting[prio]--
+			rl.mu.Unlock()
+		}
+	}()
+
+	for {
+		rl.mu.Lock()
+		if rl.rate == 0 {
+			rl.mu.Unlock()
+			return nil
+		}
+		rl.refill()
+		if rl.tokens > 0 && !rl.preempted(prio) {
+			rl.tokens -= float64(n)
+			rl.mu.Unlock()
+			return nil
+		}
+		if !registered {
+			rl.waiting[prio]++
+			registered = true
+		}
+		wait := maxRateLimiterWait
+		if rl.tokens <= 0 {
+			if d := time.Duration((1 - rl.tokens) / rl.rate * float64(time.Second)); d < wait {
+				wait = d
+			}
+		}
+		rl.mu.Unlock()
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (rl *RateLimiter) refill() {
+	now := time.Now()
+	rl.tokens += now.Sub(rl.last).Seconds() * rl.rate
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+	rl.last = now
+}
+
+// preempted returns true if a waiter of a higher priority is waiting.
+func (rl *RateLimiter) preempted(prio TrafficPriority) bool {
+	for p := 0; p < int(prio); p++ {
+		if rl.waiting[p] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// trafficShaper holds the upload and download limiters of a scope.
+type trafficShaper struct {
+	upload   *RateLimiter
+	download *RateLimiter
+}
+
+func newTrafficShaper() *trafficShaper {
+	return &trafficShaper{
+		upload:   NewRateLimiter(0),
+		download: NewRateLimiter(0),
+	}
+}
+
+func (ts *trafficShaper) setLimits(upload, download int64) {
+	ts.upload.SetLimit(upload)
+	ts.download.SetLimit(download)
+}
+
+var globalTraffic = newTrafficShaper()
+
+// SetBandwidthLimits sets the upload and download limits, in bytes per second,
+// shared by all allocations. Zero disables a limit.
+func SetBandwidthLimits(upload, download int64) {
+	globalTraffic.setLimits(upload, download)
+}
+
+// GetBandwidthLimits returns the global upload and download limits.
+func GetBandwidthLimits() (upload, download int64) {
+	return globalTraffic.upload.Limit(), globalTraffic.download.Limit()
+}
+
+// SetBandwidthLimits sets the upload and download limits, in bytes per second,
+// of the allocation. They apply on top of the global ones. Zero disables a limit.
+func (a *Allocation) SetBandwidthLimits(upload, download int64) {
+	a.trafficShaper().setLimits(upload, download)
+}
+
+// trafficMu guards the creation of the traffic shapers of the allocations.
+var trafficMu sync.Mutex
+
+func (a *Allocation) trafficShaper() *trafficShaper {
+	trafficMu.Lock()
+	defer trafficMu.Unlock()
+	if a.traffic == nil {
+		a.traffic = newTrafficShaper()
+	}
+	return a.traffic
+}
+
+// waitUpload waits for the global and the allocation upload limiters.
+func waitUpload(ctx context.Context, ts *trafficShaper, n int, prio TrafficPriority) error {
+	if err := globalTraffic.upload.Wait(ctx, n, prio); err != nil {
+		return err
+	}
+	if ts == nil {
+		return nil
+	}
+	return ts.upload.Wait(ctx, n, prio)
+}
+
+// waitDownload waits for the global and the allocation download limiters.
+func waitDownload(ctx context.Context, ts *trafficShaper, n int, prio TrafficPriority) error {
+	if err := globalTraffic.download.Wait(ctx, n, prio); err != nil {
+		return err
+	}
+	if ts == nil {
+		return nil
+	}
+	return ts.download.Wait(ctx, n, prio)
+}
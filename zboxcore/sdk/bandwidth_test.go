@@ -0,0 +1,92 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	t.Run("Unlimited", func(t *testing.T) {
+		rl := NewRateLimiter(0)
+		start := time.Now()
+		for i := 0; i < 100; i++ {
+			require.NoError(t, rl.Wait(context.Background(), MB, PriorityInteractive))
+		}
+		require.Less(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("Limited", func(t *testing.T) {
+		rl := NewRateLimiter(10 * KB)
+		start := time.Now()
+		// the first wait consumes the burst, the second one pays the debt
+		require.NoError(t, rl.Wait(context.Background(), 12*KB, PriorityInteractive))
+		require.NoError(t, rl.Wait(context.Background(), KB, PriorityInteractive))
+		require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		rl := NewRateLimiter(KB)
+		require.NoError(t, rl.Wait(context.Background(), 10*KB, PriorityInteractive))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, rl.Wait(ctx, KB, PriorityInteractive), context.DeadlineExceeded)
+	})
+
+	t.Run("Priority", func(t *testing.T) {
+		rl := NewRateLimiter(100 * KB)
+		require.NoError(t, rl.Wait(context.Background(), 120*KB, PriorityInteractive))
+
+		var (
+			mu    sync.Mutex
+			order []TrafficPriority
+			wg    sync.WaitGroup
+		)
+		wait := func(prio TrafficPriority) {
+			defer wg.Done()
+			require.NoError(t, rl.Wait(context.Background(), 100*KB, prio))
+			mu.Lock()
+			order = append(order, prio)
+			mu.Unlock()
+		}
+
+		wg.Add(2)
+		go wait(PriorityRepair)
+		time.Sleep(10 * time.Millisecond)
+		go wait(PriorityInteractive)
+		wg.Wait()
+
+		require.Equal(t, []TrafficPriority{PriorityInteractive, PriorityRepair}, order)
+	})
+}
+
+func TestSetBandwidthLimits(t *testing.T) {
+	defer SetBandwidthLimits(0, 0)
+
+	SetBandwidthLimits(MB, 2*MB)
+	up, down := GetBandwidthLimits()
+	require.EqualValues(t, MB, up)
+	require.EqualValues(t, 2*MB, down)
+
+	a := &Allocation{}
+	a.SetBandwidthLimits(KB, 2*KB)
+	require.EqualValues(t, KB, a.traffic.upload.Limit())
+	require.EqualValues(t, 2*KB, a.traffic.download.Limit())
+}
+
+func TestAllocationTrafficShaperConcurrent(t *testing.T) {
+	a := &Allocation{}
+	shapers := make(chan *trafficShaper, 10)
+	for i := 0; i < cap(shapers); i++ {
+		go func() {
+			shapers <- a.trafficShaper()
+		}()
+	}
+	first := <-shapers
+	for i := 1; i < cap(shapers); i++ {
+		require.Same(t, first, <-shapers)
+	}
+}
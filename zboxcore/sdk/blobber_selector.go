@@ -0,0 +1,447 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/health"
+	"github.com/0chain/gosdk/core/resty"
+	l "github.com/0chain/gosdk/zboxcore/logger"
+	"github.com/0chain/gosdk/zboxcore/zboxutil"
+	"go.uber.org/zap"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	defaultBlobberProbeTimeout = 5 * time.Second
+	defaultBlobberProbePath    = "/_stats"
+)
+
+var errNotEnoughBlobbers = errors.New("not_enough_blobbers", "not enough blobbers match the selection policy")
+
+// BlobberSelector picks the blobbers of an allocation among the candidates.
+// Implementations return exactly n blobbers ordered from the most preferred
+// one, or an error if there aren't enough suitable candidates.
+type BlobberSelector interface {
+	Select(ctx context.Context, candidates []*Blobber, n int) ([]*Blobber, error)
+}
+
+// BlobberSelectorFunc is an adapter to use ordinary functions as BlobberSelector.
+type BlobberSelectorFunc func(ctx context.Context, candidates []*Blobber, n int) ([]*Blobber, error)
+
+// Select calls f(ctx, candidates, n).
+func (f BlobberSelectorFunc) Select(ctx context.Context, candidates []*Blobber, n int) ([]*Blobber, error) {
+	return f(ctx, candidates, n)
+}
+
+// BlobberRanker is implemented by the selectors that can order all the
+// suitable candidates, skipping the unsuitable ones, rather than pick n of
+// them. DiversitySelector uses it to choose among more than n blobbers.
+type BlobberRanker interface {
+	Rank(ctx context.Context, candidates []*Blobber) ([]*Blobber, error)
+}
+
+// selectRanked returns the n first blobbers ranked by r.
+func selectRanked(ctx context.Context, r BlobberRanker, candidates []*Blobber, n int) ([]*Blobber, error) {
+	if len(candidates) < n {
+		return nil, errNotEnoughBlobbers
+	}
+	ranked, err := r.Rank(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(ranked) < n {
+		return nil, errNotEnoughBlobbers
+	}
+	return ranked[:n], nil
+}
+
+// LowestCostSelector prefers blobbers with the lowest write price, then the
+// lowest read price.
+type LowestCostSelector struct{}
+
+// Select implements BlobberSelector.
+func (s LowestCostSelector) Select(ctx context.Context, candidates []*Blobber, n int) ([]*Blobber, error) {
+	return selectRanked(ctx, s, candidates, n)
+}
+
+// Rank implements BlobberRanker.
+func (LowestCostSelector) Rank(_ context.Context, candidates []*Blobber) ([]*Blobber, error) {
+	sorted := make([]*Blobber, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Terms.WritePrice != sorted[j].Terms.WritePrice {
+			return sorted[i].Terms.WritePrice < sorted[j].Terms.WritePrice
+		}
+		return sorted[i].Terms.ReadPrice < sorted[j].Terms.ReadPrice
+	})
+	return sorted, nil
+}
+
+// LowestLatencySelector probes the candidates concurrently and prefers the
+// fastest ones. Blobbers that don't respond within Timeout are skipped.
+type LowestLatencySelector struct {
+	// Timeout of a single probe, 5 seconds by default.
+	Timeout time.Duration
+	// Probe measures the latency of a blobber. By default it sends a GET
+	// request to the stats endpoint of the blobber.
+	Probe func(ctx context.Context, b *Blobber) (time.Duration, error)
+}
+
+// Select implements BlobberSelector.
+func (s LowestLatencySelector) Select(ctx context.Context, candidates []*Blobber, n int) ([]*Blobber, error) {
+	return selectRanked(ctx, s, candidates, n)
+}
+
+// Rank implements BlobberRanker, the unreachable blobbers are skipped.
+func (s LowestLatencySelector) Rank(ctx context.Context, candidates []*Blobber) ([]*Blobber, error) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultBlobberProbeTimeout
+	}
+	probe := s.Probe
+	if probe == nil {
+		probe = probeBlobber
+	}
+
+	latencies := make([]time.Duration, len(candidates))
+	wg := &sync.WaitGroup{}
+	for i, b := range candidates {
+		wg.Add(1)
+		go func(i int, b *Blobber) {
+			defer wg.Done()
+			pctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			d, err := probe(pctx, b)
+			if err != nil {
+				l.Logger.Debug("blobber probe failed", zap.String("blobber", string(b.ID)), zap.Error(err))
+				d = -1
+			}
+			latencies[i] = d
+		}(i, b)
+	}
+	wg.Wait()
+
+	type probed struct {
+		blobber *Blobber
+		latency time.Duration
+	}
+	reachable := make([]probed, 0, len(candidates))
+	for i, b := range candidates {
+		if latencies[i] >= 0 {
+			reachable = append(reachable, probed{b, latencies[i]})
+		}
+	}
+
+	sort.SliceStable(reachable, func(i, j int) bool {
+		return reachable[i].latency < reachable[j].latency
+	})
+	ranked := make([]*Blobber, 0, len(reachable))
+	for _, p := range reachable {
+		ranked = append(ranked, p.blobber)
+	}
+	return ranked, nil
+}
+
+func probeBlobber(ctx context.Context, b *Blobber) (time.Duration, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(b.BaseURL, "/")+defaultBlobberProbePath, nil)
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	resp, err := zboxutil.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, errors.New("blobber_unhealthy", fmt.Sprintf("%s responded %d", defaultBlobberProbePath, resp.StatusCode))
+	}
+	return time.Since(start), nil
+}
+
+// HealthSelector prefers the blobbers with the best health recorded by the
+// SDK on its previous requests, and skips those whose circuit is open.
+type HealthSelector struct {
+	// Registry is health.Default by default.
+	Registry *health.Registry
+}
+
+// Select implements BlobberSelector.
+func (s HealthSelector) Select(ctx context.Context, candidates []*Blobber, n int) ([]*Blobber, error) {
+	return selectRanked(ctx, s, candidates, n)
+}
+
+// Rank implements BlobberRanker, the blobbers whose circuit is open are
+// skipped.
+func (s HealthSelector) Rank(_ context.Context, candidates []*Blobber) ([]*Blobber, error) {
+	registry := s.Registry
+	if registry == nil {
+		registry = health.Default
+	}
+
+	byNode := make(map[string]*Blobber, len(candidates))
+	nodes := make([]string, 0, len(candidates))
+	for _, b := range candidates {
+		node := health.NodeOf(b.BaseURL)
+		if resty.DefaultCircuitBreaker.State(node) == resty.CircuitOpen {
+			continue
+		}
+		byNode[node] = b
+		nodes = append(nodes, node)
+	}
+
+	ranked := make([]*Blobber, 0, len(nodes))
+	for _, node := range registry.Rank(nodes) {
+		ranked = append(ranked, byNode[node])
+	}
+	return ranked, nil
+}
+
+// DiversitySelector spreads the allocation over as many hosts as possible.
+// Candidates are ordered by Base (LowestCostSelector by default), then the
+// first blobber of every host is taken before a second one of the same host.
+// The choice is among all the candidates if Base is a BlobberRanker, like the
+// selectors of this package, else among the n blobbers Base selects.
+type DiversitySelector struct {
+	Base BlobberSelector
+}
+
+// Select implements BlobberSelector.
+func (s DiversitySelector) Select(ctx context.Context, candidates []*Blobber, n int) ([]*Blobber, error) {
+	base := s.Base
+	if base == nil {
+		base = LowestCostSelector{}
+	}
+	var ordered []*Blobber
+	var err error
+	if r, ok := base.(BlobberRanker); ok {
+		ordered, err = r.Rank(ctx, candidates)
+	} else {
+		ordered, err = base.Select(ctx, candidates, n)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(ordered) < n {
+		return nil, errNotEnoughBlobbers
+	}
+
+	selected := make([]*Blobber, 0, n)
+	var rest []*Blobber
+	hosts := make(map[string]bool)
+	for _, b := range ordered {
+		h := blobberHost(b.BaseURL)
+		if hosts[h] {
+			rest = append(rest, b)
+			continue
+		}
+		hosts[h] = true
+		selected = append(selected, b)
+		if len(selected) == n {
+			return selected, nil
+		}
+	}
+	return append(selected, rest[:n-len(selected)]...), nil
+}
+
+// blobberHost returns the registrable domain of the blobber host, so that
+// b1.example.com and b2.example.com, or b1.example.co.uk and b2.example.co.uk,
+// are considered the same host.
+func blobberHost(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Hostname() == "" {
+		return baseURL
+	}
+	host := u.Hostname()
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return domain
+	}
+	return host
+}
+
+// StakeWeightedSelector picks blobbers randomly with probability proportional
+// to their total stake, so that well staked blobbers are preferred without
+// always loading the same ones.
+type StakeWeightedSelector struct {
+	// Stake returns the stake of a blobber. By default it is read with
+	// GetStakePoolInfo.
+	Stake func(b *Blobber) (int64, error)
+	// Rand is the random source, a time seeded one is used by default.
+	Rand *rand.Rand
+}
+
+// Select implements BlobberSelector.
+func (s StakeWeightedSelector) Select(ctx context.Context, candidates []*Blobber, n int) ([]*Blobber, error) {
+	return selectRanked(ctx, s, candidates, n)
+}
+
+// Rank implements BlobberRanker, a random order weighted by stake. The
+// blobbers without stake are skipped.
+func (s StakeWeightedSelector) Rank(_ context.Context, candidates []*Blobber) ([]*Blobber, error) {
+	stakeOf := s.Stake
+	if stakeOf == nil {
+		stakeOf = stakePoolTotal
+	}
+	r := s.Rand
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	pool := make([]*Blobber, 0, len(candidates))
+	weights := make([]float64, 0, len(candidates))
+	var total float64
+	for _, b := range candidates {
+		stake, err := stakeOf(b)
+		if err != nil {
+			l.Logger.Debug("can't get blobber stake", zap.String("blobber", string(b.ID)), zap.Error(err))
+			continue
+		}
+		if stake <= 0 {
+			continue
+		}
+		pool = append(pool, b)
+		weights = append(weights, float64(stake))
+		total += float64(stake)
+	}
+
+	ranked := make([]*Blobber, 0, len(pool))
+	for len(pool) > 0 {
+		x := r.Float64() * total
+		i := 0
+		for ; i < len(pool)-1; i++ {
+			x -= weights[i]
+			if x < 0 {
+				break
+			}
+		}
+		ranked = append(ranked, pool[i])
+		total -= weights[i]
+		pool = append(pool[:i], pool[i+1:]...)
+		weights = append(weights[:i], weights[i+1:]...)
+	}
+	return ranked, nil
+}
+
+func stakePoolTotal(b *Blobber) (int64, error) {
+	info, err := GetStakePoolInfo(ProviderBlobber, string(b.ID))
+	if err != nil {
+		return 0, err
+	}
+	return int64(info.StakeTotal), nil
+}
+
+// SelectAllocationBlobbers picks the blobbers for a new allocation with the
+// given selector among the active blobbers having enough free space and
+// matching the price ranges.
+func SelectAllocationBlobbers(ctx context.Context, selector BlobberSelector,
+	datashards, parityshards int, size int64,
+	readPrice, writePrice PriceRange) ([]string, error) {
+
+	return selectAllocationBlobbers(ctx, selector, datashards, parityshards, size, readPrice, writePrice, nil)
+}
+
+// selectAllocationBlobbers is SelectAllocationBlobbers completing the
+// preferred blobbers: they are returned first, followed by the missing ones
+// picked by selector among the others.
+func selectAllocationBlobbers(ctx context.Context, selector BlobberSelector,
+	datashards, parityshards int, size int64,
+	readPrice, writePrice PriceRange, preferred []string) ([]string, error) {
+
+	if !sdkInitialized {
+		return nil, sdkNotInitialized
+	}
+
+	exclude := make(map[string]bool, len(preferred))
+	ids := make([]string, 0, datashards+parityshards)
+	for _, id := range preferred {
+		if !exclude[id] {
+			exclude[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) >= datashards+parityshards {
+		return ids, nil
+	}
+
+	blobbers, err := GetBlobbers(true)
+	if err != nil {
+		return nil, err
+	}
+
+	shardSize := (size + int64(datashards) - 1) / int64(datashards)
+	candidates := filterAllocationBlobbers(blobbers, shardSize, readPrice, writePrice, exclude)
+	selected, err := selector.Select(ctx, candidates, datashards+parityshards-len(ids))
+	if err != nil {
+		return nil, err
+	}
+	return append(ids, blobberIDs(selected)...), nil
+}
+
+// SelectBlobberForUpdate picks, with the given selector, a blobber to add to
+// the allocation with UpdateAllocation.
+func SelectBlobberForUpdate(ctx context.Context, selector BlobberSelector, alloc *Allocation) (string, error) {
+	if !sdkInitialized {
+		return "", sdkNotInitialized
+	}
+
+	blobbers, err := GetBlobbers(true)
+	if err != nil {
+		return "", err
+	}
+
+	exclude := make(map[string]bool, len(alloc.Blobbers))
+	for _, b := range alloc.Blobbers {
+		exclude[b.ID] = true
+	}
+
+	shardSize := (alloc.Size + int64(alloc.DataShards) - 1) / int64(alloc.DataShards)
+	candidates := filterAllocationBlobbers(blobbers, shardSize, alloc.ReadPriceRange, alloc.WritePriceRange, exclude)
+	selected, err := selector.Select(ctx, candidates, 1)
+	if err != nil {
+		return "", err
+	}
+	return string(selected[0].ID), nil
+}
+
+func filterAllocationBlobbers(blobbers []*Blobber, shardSize int64,
+	readPrice, writePrice PriceRange, exclude map[string]bool) []*Blobber {
+
+	candidates := make([]*Blobber, 0, len(blobbers))
+	for _, b := range blobbers {
+		if b.IsKilled || b.IsShutdown || b.NotAvailable || exclude[string(b.ID)] {
+			continue
+		}
+		if int64(b.Capacity-b.Allocated) < shardSize {
+			continue
+		}
+		if readPrice.Max > 0 && (uint64(b.Terms.ReadPrice) < readPrice.Min || uint64(b.Terms.ReadPrice) > readPrice.Max) {
+			continue
+		}
+		if writePrice.Max > 0 && (uint64(b.Terms.WritePrice) < writePrice.Min || uint64(b.Terms.WritePrice) > writePrice.Max) {
+			continue
+		}
+		candidates = append(candidates, b)
+	}
+	return candidates
+}
+
+func blobberIDs(blobbers []*Blobber) []string {
+	ids := make([]string, 0, len(blobbers))
+	for _, b := range blobbers {
+		ids = append(ids, string(b.ID))
+	}
+	return ids
+}
@@ -0,0 +1,132 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0chain/gosdk/core/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newSelectorBlobbers() []*Blobber {
+	return []*Blobber{
+		{ID: "b0", BaseURL: "https://b0.host-a.net", Terms: Terms{WritePrice: 3, ReadPrice: 1}, Capacity: common.Size(GB)},
+		{ID: "b1", BaseURL: "https://b1.host-a.net", Terms: Terms{WritePrice: 1, ReadPrice: 2}, Capacity: common.Size(GB)},
+		{ID: "b2", BaseURL: "https://b2.host-b.net", Terms: Terms{WritePrice: 1, ReadPrice: 1}, Capacity: common.Size(GB)},
+		{ID: "b3", BaseURL: "http://10.0.0.1:5051", Terms: Terms{WritePrice: 2, ReadPrice: 1}, Capacity: common.Size(GB), IsKilled: true},
+	}
+}
+
+func TestLowestCostSelector(t *testing.T) {
+	selected, err := LowestCostSelector{}.Select(context.Background(), newSelectorBlobbers(), 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b2", "b1", "b3"}, blobberIDs(selected))
+
+	_, err = LowestCostSelector{}.Select(context.Background(), newSelectorBlobbers(), 5)
+	require.Error(t, err)
+}
+
+func TestLowestLatencySelector(t *testing.T) {
+	latencies := map[common.Key]time.Duration{"b0": 30, "b1": 10, "b2": 20}
+	s := LowestLatencySelector{
+		Probe: func(_ context.Context, b *Blobber) (time.Duration, error) {
+			d, ok := latencies[b.ID]
+			if !ok {
+				return 0, errors.New("unreachable")
+			}
+			return d, nil
+		},
+	}
+
+	selected, err := s.Select(context.Background(), newSelectorBlobbers(), 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b1", "b2"}, blobberIDs(selected))
+
+	_, err = s.Select(context.Background(), newSelectorBlobbers(), 4)
+	require.Error(t, err)
+}
+
+func TestDiversitySelector(t *testing.T) {
+	selected, err := DiversitySelector{}.Select(context.Background(), newSelectorBlobbers(), 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b2", "b1", "b3"}, blobberIDs(selected))
+
+	selected, err = DiversitySelector{}.Select(context.Background(), newSelectorBlobbers(), 4)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b2", "b1", "b3", "b0"}, blobberIDs(selected))
+
+	// the base drops an unreachable candidate, the choice is among the others
+	latency := LowestLatencySelector{
+		Probe: func(_ context.Context, b *Blobber) (time.Duration, error) {
+			if b.ID == "b3" {
+				return 0, errors.New("unreachable")
+			}
+			return 10, nil
+		},
+	}
+	selected, err = DiversitySelector{Base: latency}.Select(context.Background(), newSelectorBlobbers(), 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b0", "b2"}, blobberIDs(selected))
+
+	require.Equal(t, "host-a.net", blobberHost("https://b0.host-a.net"))
+	require.Equal(t, "host-a.co.uk", blobberHost("https://b0.host-a.co.uk"))
+	require.Equal(t, "host-b.co.uk", blobberHost("https://b0.host-b.co.uk"))
+	require.Equal(t, "10.0.0.1", blobberHost("http://10.0.0.1:5051"))
+	require.Equal(t, "::1", blobberHost("http://[::1]:5051"))
+	require.Equal(t, "localhost", blobberHost("http://localhost:5051"))
+}
+
+func TestProbeBlobber(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, defaultBlobberProbePath, strings.TrimPrefix(r.URL.Path, "/unhealthy"))
+		if strings.HasPrefix(r.URL.Path, "/unhealthy") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	useHTTPClient(t)
+
+	_, err := probeBlobber(context.Background(), &Blobber{BaseURL: server.URL})
+	require.NoError(t, err)
+	_, err = probeBlobber(context.Background(), &Blobber{BaseURL: server.URL + "/unhealthy"})
+	require.Error(t, err)
+}
+
+func TestStakeWeightedSelector(t *testing.T) {
+	stakes := map[common.Key]int64{"b0": 100, "b1": 0, "b2": 1, "b3": 50}
+	s := StakeWeightedSelector{
+		Stake: func(b *Blobber) (int64, error) { return stakes[b.ID], nil },
+		Rand:  rand.New(rand.NewSource(1)),
+	}
+
+	selected, err := s.Select(context.Background(), newSelectorBlobbers(), 3)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"b0", "b2", "b3"}, blobberIDs(selected))
+
+	_, err = s.Select(context.Background(), newSelectorBlobbers(), 4)
+	require.Error(t, err)
+}
+
+func TestSelectAllocationBlobbersPreferred(t *testing.T) {
+	original := sdkInitialized
+	sdkInitialized = true
+	defer func() { sdkInitialized = original }()
+
+	// enough preferred blobbers, nothing to select
+	ids, err := selectAllocationBlobbers(context.Background(), LowestCostSelector{},
+		2, 1, GB, PriceRange{}, PriceRange{}, []string{"b1", "b0", "b1", "b2"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b1", "b0", "b2"}, ids)
+}
+
+func TestFilterAllocationBlobbers(t *testing.T) {
+	candidates := filterAllocationBlobbers(newSelectorBlobbers(), GB/2,
+		PriceRange{}, PriceRange{Min: 0, Max: 2}, map[string]bool{"b2": true})
+	require.Equal(t, []string{"b1"}, blobberIDs(candidates))
+}
@@ -44,6 +44,8 @@ type BlockDownloadRequest struct {
 	result             chan *downloadBlock
 	shouldVerify       bool
 	connectionID       string
+	traffic            *trafficShaper
+	priority           TrafficPriority
 }
 
 type downloadResponse struct {
@@ -109,6 +111,15 @@ func (req *BlockDownloadRequest) downloadBlobberBlock() {
 		req.result <- &downloadBlock{Success: false, idx: req.blobberIdx, err: errors.New("invalid_request", "Invalid number of blocks for download")}
 		return
 	}
+	chunkSize := req.chunkSize
+	if chunkSize == 0 {
+		chunkSize = CHUNK_SIZE
+	}
+	if err := waitDownload(req.ctx, req.traffic, int(req.numBlocks)*chunkSize, req.priority); err != nil {
+		req.result <- &downloadBlock{Success: false, idx: req.blobberIdx, err: err}
+		return
+	}
+
 	retry := 0
 	var err error
 	for retry < 3 {
@@ -162,7 +173,7 @@ func (req *BlockDownloadRequest) downloadBlobberBlock() {
 				if err = json.Unmarshal(respBody, &rspData); err == nil {
 					return errors.New("download_error", fmt.Sprintf("Response status: %d, Error: %v,", resp.StatusCode, rspData.err))
 				}
-				return errors.New("response_error", string(respBody))
+				return newBlobberHTTPError(req.blobber, resp.StatusCode, string(respBody))
 			}
 
 			dR := downloadResponse{}
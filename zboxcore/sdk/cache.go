@@ -0,0 +1,162 @@
+package sdk
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMetaCacheTTL is the time file metadata is kept by a Cache created
+// with a zero TTL.
+const DefaultMetaCacheTTL = time.Minute
+
+// CacheBackend stores the entries of a Cache. Implementations must be safe for
+// concurrent use.
+type CacheBackend interface {
+	// Get returns the value of key, false if it is missing or expired.
+	Get(key string) ([]byte, bool)
+	// Set stores value under key for ttl, zero ttl never expires.
+	Set(key string, value []byte, ttl time.Duration)
+	// DeletePrefix removes the keys starting with prefix.
+	DeletePrefix(prefix string)
+}
+
+// Cache is a read-through cache of the downloaded blocks and of the file
+// metadata, so that repeated reads of a file (seeking in a StreamDownload,
+// polling a playlist) don't download the blocks and redeem read markers again.
+//
+// Blocks are keyed by allocation, lookup hash, validation root (the actual
+// thumbnail hash for thumbnails) and block number: a new version of a file has
+// a new validation root, so cached blocks are never stale. File metadata is kept for MetaTTL, and dropped when the
+// allocation root changes, after a commit of the SDK or when a newer write
+// marker is seen. Blocks of encrypted files are not cached.
+type Cache struct {
+	Backend CacheBackend
+	MetaTTL time.Duration
+
+	mu    sync.Mutex
+	roots map[string]string
+}
+
+// NewCache creates a Cache, DefaultMetaCacheTTL is used if metaTTL is zero.
+func NewCache(backend CacheBackend, metaTTL time.Duration) *Cache {
+	if metaTTL <= 0 {
+		metaTTL = DefaultMetaCacheTTL
+	}
+	return &Cache{
+		Backend: backend,
+		MetaTTL: metaTTL,
+		roots:   make(map[string]string),
+	}
+}
+
+var (
+	cacheMu     sync.RWMutex
+	globalCache *Cache
+)
+
+// SetCache sets the cache used by all the allocations, nil disables caching.
+func SetCache(c *Cache) {
+	cacheMu.Lock()
+	globalCache = c
+	cacheMu.Unlock()
+}
+
+// GetCache returns the cache used by all the allocations, nil if caching is
+// disabled.
+func GetCache() *Cache {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+	return globalCache
+}
+
+// InvalidateAllocation drops the cached file metadata of the allocation.
+func (c *Cache) InvalidateAllocation(allocationID string) {
+	if c == nil || c.Backend == nil {
+		return
+	}
+	c.Backend.DeletePrefix(metaCachePrefix(allocationID))
+}
+
+// ObserveAllocationRoot records the allocation root of the latest write marker
+// of a blobber of the allocation, the cached file metadata is dropped if it
+// changed.
+func (c *Cache) ObserveAllocationRoot(allocationID, blobberID, root string) {
+	if c == nil || c.Backend == nil || root == "" {
+		return
+	}
+	key := allocationID + ":" + blobberID
+	c.mu.Lock()
+	if c.roots == nil {
+		c.roots = make(map[string]string)
+	}
+	prev, ok := c.roots[key]
+	c.roots[key] = root
+	c.mu.Unlock()
+
+	if ok && prev != root {
+		c.InvalidateAllocation(allocationID)
+	}
+}
+
+func (c *Cache) getBlock(key string, size int) ([]byte, bool) {
+	if c == nil || c.Backend == nil {
+		return nil, false
+	}
+	data, ok := c.Backend.Get(key)
+	if !ok || len(data) != size {
+		return nil, false
+	}
+	return data, true
+}
+
+func (c *Cache) setBlock(key string, data []byte) {
+	if c == nil || c.Backend == nil {
+		return
+	}
+	block := make([]byte, len(data))
+	copy(block, data)
+	c.Backend.Set(key, block, 0)
+}
+
+func (c *Cache) getFileMeta(allocationID, path string) (*ConsolidatedFileMeta, bool) {
+	if c == nil || c.Backend == nil {
+		return nil, false
+	}
+	data, ok := c.Backend.Get(metaCachePrefix(allocationID) + path)
+	if !ok {
+		return nil, false
+	}
+	meta := &ConsolidatedFileMeta{}
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, false
+	}
+	return meta, true
+}
+
+func (c *Cache) setFileMeta(allocationID, path string, meta *ConsolidatedFileMeta) {
+	if c == nil || c.Backend == nil {
+		return
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return
+	}
+	c.Backend.Set(metaCachePrefix(allocationID)+path, data, c.MetaTTL)
+}
+
+func metaCachePrefix(allocationID string) string {
+	return "meta:" + allocationID + ":"
+}
+
+// blockCacheKeyPrefix returns the prefix of the keys of the blocks of a file
+// version; the block number is appended to it.
+func blockCacheKeyPrefix(allocationID, lookupHash, contentHash, contentMode string) string {
+	return strings.Join([]string{"block", allocationID, lookupHash, contentHash, contentMode}, ":") + ":"
+}
+
+func blockCacheKey(prefix string, block int64) string {
+	return prefix + strconv.FormatInt(block, 10)
+}
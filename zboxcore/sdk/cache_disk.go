@@ -0,0 +1,207 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package sdk
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0chain/errors"
+)
+
+// DiskCache is a CacheBackend storing every entry in a file of a directory,
+// bounded in bytes. The least recently used entries are removed first. It
+// survives restarts of the application.
+type DiskCache struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	size     int64
+	entries  map[string]*diskCacheEntry
+}
+
+type diskCacheEntry struct {
+	key      string
+	size     int64
+	expires  time.Time
+	lastUsed time.Time
+}
+
+// diskCacheHeaderSize is the size of the header of an entry file: the expiry
+// as unix nanoseconds and the length of the key, followed by the key.
+const diskCacheHeaderSize = 8 + 4
+
+const maxDiskCacheKeySize = 4096
+
+// NewDiskCache opens the cache in dir, creating it if needed, and loads the
+// entries already there.
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "cache_dir_error")
+	}
+	d := &DiskCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*diskCacheEntry),
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "cache_dir_error")
+	}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		name := filepath.Join(dir, f.Name())
+		key, expires, err := readDiskCacheHeader(name)
+		if err != nil || d.fileName(key) != name {
+			os.Remove(name) //nolint: errcheck
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		d.entries[key] = &diskCacheEntry{
+			key:      key,
+			size:     info.Size(),
+			expires:  expires,
+			lastUsed: info.ModTime(),
+		}
+		d.size += info.Size()
+	}
+	d.evict()
+	return d, nil
+}
+
+// Get implements CacheBackend.
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	e, ok := d.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		d.remove(e)
+		return nil, false
+	}
+	data, err := os.ReadFile(d.fileName(key))
+	headerSize := diskCacheHeaderSize + len(key)
+	if err != nil || len(data) < headerSize {
+		d.remove(e)
+		return nil, false
+	}
+	e.lastUsed = time.Now()
+	return data[headerSize:], true
+}
+
+// Set implements CacheBackend. Values larger than the cache are ignored.
+func (d *DiskCache) Set(key string, value []byte, ttl time.Duration) {
+	size := int64(diskCacheHeaderSize + len(key) + len(value))
+	if size > d.maxBytes {
+		return
+	}
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	buf := make([]byte, size)
+	if !expires.IsZero() {
+		binary.BigEndian.PutUint64(buf, uint64(expires.UnixNano()))
+	}
+	binary.BigEndian.PutUint32(buf[8:], uint32(len(key)))
+	copy(buf[diskCacheHeaderSize:], key)
+	copy(buf[diskCacheHeaderSize+len(key):], value)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if e, ok := d.entries[key]; ok {
+		d.remove(e)
+	}
+	if err := os.WriteFile(d.fileName(key), buf, 0600); err != nil {
+		return
+	}
+	d.entries[key] = &diskCacheEntry{key: key, size: size, expires: expires, lastUsed: time.Now()}
+	d.size += size
+	d.evict()
+}
+
+// DeletePrefix implements CacheBackend.
+func (d *DiskCache) DeletePrefix(prefix string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key, e := range d.entries {
+		if strings.HasPrefix(key, prefix) {
+			d.remove(e)
+		}
+	}
+}
+
+func (d *DiskCache) evict() {
+	if d.size <= d.maxBytes {
+		return
+	}
+	lru := make([]*diskCacheEntry, 0, len(d.entries))
+	for _, e := range d.entries {
+		lru = append(lru, e)
+	}
+	sort.Slice(lru, func(i, j int) bool { return lru[i].lastUsed.Before(lru[j].lastUsed) })
+	for _, e := range lru {
+		if d.size <= d.maxBytes {
+			return
+		}
+		d.remove(e)
+	}
+}
+
+func (d *DiskCache) remove(e *diskCacheEntry) {
+	os.Remove(d.fileName(e.key)) //nolint: errcheck
+	delete(d.entries, e.key)
+	d.size -= e.size
+}
+
+func (d *DiskCache) fileName(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(h[:]))
+}
+
+func readDiskCacheHeader(name string) (string, time.Time, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer f.Close()
+
+	header := make([]byte, diskCacheHeaderSize)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return "", time.Time{}, err
+	}
+	var expires time.Time
+	if ns := binary.BigEndian.Uint64(header); ns != 0 {
+		expires = time.Unix(0, int64(ns))
+	}
+	keyLen := binary.BigEndian.Uint32(header[8:])
+	if keyLen > maxDiskCacheKeySize {
+		return "", time.Time{}, errors.New("cache_entry_error", "invalid key length")
+	}
+	key := make([]byte, keyLen)
+	if _, err := io.ReadFull(f, key); err != nil {
+		return "", time.Time{}, err
+	}
+	return string(key), expires, nil
+}
@@ -0,0 +1,98 @@
+package sdk
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryCache is an in-memory LRU CacheBackend bounded in bytes.
+type MemoryCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	ll       *list.List
+	entries  map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates a MemoryCache keeping up to maxBytes of values.
+func NewMemoryCache(maxBytes int64) *MemoryCache {
+	return &MemoryCache{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get implements CacheBackend.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*memoryCacheEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		m.remove(el)
+		return nil, false
+	}
+	m.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// Set implements CacheBackend. Values larger than the cache are ignored.
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	if int64(len(value)) > m.maxBytes {
+		return
+	}
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.entries[key]; ok {
+		m.remove(el)
+	}
+	m.entries[key] = m.ll.PushFront(&memoryCacheEntry{key: key, value: value, expires: expires})
+	m.size += int64(len(value))
+	for m.size > m.maxBytes {
+		m.remove(m.ll.Back())
+	}
+}
+
+// DeletePrefix implements CacheBackend.
+func (m *MemoryCache) DeletePrefix(prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, el := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(el)
+		}
+	}
+}
+
+// Len returns the number of entries.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+func (m *MemoryCache) remove(el *list.Element) {
+	e := m.ll.Remove(el).(*memoryCacheEntry)
+	delete(m.entries, e.key)
+	m.size -= int64(len(e.value))
+}
@@ -0,0 +1,93 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	t.Run("LRU eviction", func(t *testing.T) {
+		m := NewMemoryCache(8)
+		m.Set("a", []byte("1234"), 0)
+		m.Set("b", []byte("5678"), 0)
+		_, ok := m.Get("a")
+		require.True(t, ok)
+
+		m.Set("c", []byte("90"), 0)
+		_, ok = m.Get("b")
+		require.False(t, ok, "least recently used entry should be evicted")
+		v, ok := m.Get("a")
+		require.True(t, ok)
+		require.Equal(t, []byte("1234"), v)
+		require.Equal(t, 2, m.Len())
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		m := NewMemoryCache(1024)
+		m.Set("a", []byte("x"), time.Millisecond)
+		time.Sleep(5 * time.Millisecond)
+		_, ok := m.Get("a")
+		require.False(t, ok)
+	})
+
+	t.Run("DeletePrefix", func(t *testing.T) {
+		m := NewMemoryCache(1024)
+		m.Set("meta:a:/x", []byte("1"), 0)
+		m.Set("meta:a:/y", []byte("2"), 0)
+		m.Set("meta:b:/x", []byte("3"), 0)
+		m.DeletePrefix("meta:a:")
+		require.Equal(t, 1, m.Len())
+		_, ok := m.Get("meta:b:/x")
+		require.True(t, ok)
+	})
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	d, err := NewDiskCache(dir, 1024)
+	require.NoError(t, err)
+	d.Set("block:a:1", []byte("data"), 0)
+	d.Set("meta:a:/x", []byte("meta"), time.Millisecond)
+
+	d, err = NewDiskCache(dir, 1024)
+	require.NoError(t, err)
+	v, ok := d.Get("block:a:1")
+	require.True(t, ok, "entries should survive reopening the cache")
+	require.Equal(t, []byte("data"), v)
+
+	time.Sleep(5 * time.Millisecond)
+	_, ok = d.Get("meta:a:/x")
+	require.False(t, ok)
+
+	d.DeletePrefix("block:")
+	_, ok = d.Get("block:a:1")
+	require.False(t, ok)
+}
+
+func TestCacheObserveAllocationRoot(t *testing.T) {
+	c := NewCache(NewMemoryCache(1<<20), 0)
+	meta := &ConsolidatedFileMeta{Name: "x", Path: "/x"}
+	c.setFileMeta("alloc", "/x", meta)
+
+	c.ObserveAllocationRoot("alloc", "b1", "root1")
+	c.ObserveAllocationRoot("alloc", "b2", "root2")
+	got, ok := c.getFileMeta("alloc", "/x")
+	require.True(t, ok, "first roots seen should not invalidate")
+	require.Equal(t, meta.Path, got.Path)
+
+	c.ObserveAllocationRoot("alloc", "b1", "root1")
+	_, ok = c.getFileMeta("alloc", "/x")
+	require.True(t, ok)
+
+	c.ObserveAllocationRoot("alloc", "b1", "root3")
+	_, ok = c.getFileMeta("alloc", "/x")
+	require.False(t, ok, "a new allocation root should drop the metadata")
+
+	var nilCache *Cache
+	nilCache.ObserveAllocationRoot("alloc", "b1", "root")
+	nilCache.InvalidateAllocation("alloc")
+	_, ok = nilCache.getBlock("k", 1)
+	require.False(t, ok)
+}
@@ -165,7 +165,7 @@ func CreateChunkedUpload(
 		opCode:        opCode,
 	}
 
-	su.ctx, su.ctxCncl = context.WithCancel(allocationObj.ctx)
+	su.ctx = allocationObj.ctx
 
 	if isUpdate {
 		su.httpMethod = http.MethodPut
@@ -201,6 +201,7 @@ func CreateChunkedUpload(
 	for _, opt := range opts {
 		opt(su)
 	}
+	su.ctx, su.ctxCncl = context.WithCancel(su.ctx)
 	if su.progressStorer == nil {
 		su.progressStorer = createFsChunkedUploadProgress(context.Background())
 	}
@@ -634,7 +635,7 @@ func (su *ChunkedUpload) processUpload(chunkStartIndex, chunkEndIndex int,
 	isFinal bool, uploadLength int64) error {
 	su.consensus.Reset()
 
-	ctx, cancel := context.WithCancel(context.TODO())
+	ctx, cancel := context.WithCancel(su.ctx)
 	defer cancel()
 	var errCount int32
 
@@ -711,8 +712,9 @@ func (su *ChunkedUpload) processUpload(chunkStartIndex, chunkEndIndex int,
 	}
 
 	if !su.consensus.isConsensusOk() {
-		return thrown.New("consensus_not_met", fmt.Sprintf("Upload failed File not found for path %s. Required consensus atleast %d, got %d",
-			su.fileMeta.RemotePath, su.consensus.consensusThresh, su.consensus.getConsensus()))
+		return newConsensusError(ConsensusNotMet, fmt.Sprintf("Upload failed File not found for path %s. Required consensus atleast %d, got %d",
+			su.fileMeta.RemotePath, su.consensus.consensusThresh, su.consensus.getConsensus()),
+			su.consensus.consensusThresh, su.consensus.getConsensus(), nil, nil)
 	}
 
 	return nil
@@ -754,11 +756,12 @@ func (su *ChunkedUpload) processCommit() error {
 
 	wg.Wait()
 
-	if !su.consensus.isConsensusOk() {
+	if !su.consensus.recordConsensus(su.ctx, "upload_commit") {
 		consensus := su.consensus.getConsensus()
-		err := thrown.New("consensus_not_met",
+		err := newConsensusError(ConsensusNotMet,
 			fmt.Sprintf("Upload commit failed. Required consensus atleast %d, got %d",
-				su.consensus.consensusThresh, consensus))
+				su.consensus.consensusThresh, consensus),
+			su.consensus.consensusThresh, consensus, nil, nil)
 
 		if su.statusCallback != nil {
 			su.statusCallback.Error(su.allocationObj.ID, su.fileMeta.RemotePath, su.opCode, err)
@@ -766,6 +769,7 @@ func (su *ChunkedUpload) processCommit() error {
 		return err
 	}
 
+	GetCache().InvalidateAllocation(su.allocationObj.ID)
 	if su.statusCallback != nil {
 		su.statusCallback.Completed(su.allocationObj.ID, su.fileMeta.RemotePath, su.fileMeta.RemoteName, su.fileMeta.MimeType, int(su.progress.UploadLength), su.opCode)
 	}
@@ -75,6 +75,14 @@ func (sb *ChunkedUploadBlobber) sendUploadRequest(
 
 	req.Header.Add("Content-Type", formData.ContentType)
 
+	priority := PriorityInteractive
+	if su.isRepair {
+		priority = PriorityRepair
+	}
+	if err = waitUpload(ctx, su.allocationObj.trafficShaper(), body.Len(), priority); err != nil {
+		return err
+	}
+
 	var (
 		shouldContinue   bool
 		latestRespMsg    string
@@ -381,6 +389,7 @@ func (sb *ChunkedUploadBlobber) processWriteMarker(
 	}
 
 	if lR.LatestWM != nil {
+		GetCache().ObserveAllocationRoot(su.allocationObj.ID, sb.blobber.ID, lR.LatestWM.AllocationRoot)
 		rootRef.CalculateHash()
 		prevAllocationRoot := rootRef.Hash
 		if prevAllocationRoot != lR.LatestWM.AllocationRoot {
@@ -1,6 +1,7 @@
 package sdk
 
 import (
+	"context"
 	"encoding/hex"
 	"math"
 	"os"
@@ -92,6 +93,13 @@ func WithMask(mask zboxutil.Uint128) ChunkedUploadOption {
 	}
 }
 
+// WithContext binds the upload to ctx: canceling ctx cancels the upload.
+func WithContext(ctx context.Context) ChunkedUploadOption {
+	return func(su *ChunkedUpload) {
+		su.ctx = ctx
+	}
+}
+
 func WithEncryptedPoint(point string) ChunkedUploadOption {
 	return func(su *ChunkedUpload) {
 		su.encryptedKeyPoint = point
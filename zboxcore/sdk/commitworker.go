@@ -159,6 +159,7 @@ func (commitreq *CommitRequest) processCommit() {
 			return
 		}
 
+		GetCache().ObserveAllocationRoot(commitreq.allocationID, commitreq.blobber.ID, lR.LatestWM.AllocationRoot)
 		rootRef.CalculateHash()
 		prevAllocationRoot := rootRef.Hash
 		if prevAllocationRoot != lR.LatestWM.AllocationRoot {
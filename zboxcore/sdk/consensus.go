@@ -1,6 +1,11 @@
 package sdk
 
-import "sync"
+import (
+	"context"
+	"sync"
+
+	"github.com/0chain/gosdk/core/telemetry"
+)
 
 type Consensus struct {
 	*sync.RWMutex
@@ -42,3 +47,22 @@ func (c *Consensus) isConsensusOk() bool {
 
 	return c.getConsensus() >= c.consensusThresh
 }
+
+// recordConsensus reports the consensus of the operation to the telemetry and
+// returns whether it is reached.
+func (c *Consensus) recordConsensus(ctx context.Context, operation string) bool {
+	c.RLock()
+	got, required, total := c.consensus, c.consensusThresh, c.fullconsensus
+	c.RUnlock()
+
+	ok := got >= required
+	telemetry.RecordConsensus(ctx, telemetry.Consensus{
+		Operation: operation,
+		Kind:      telemetry.KindBlobber,
+		Required:  required,
+		Got:       got,
+		Total:     total,
+		OK:        ok,
+	})
+	return ok
+}
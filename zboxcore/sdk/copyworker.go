@@ -147,7 +147,7 @@ func (req *CopyRequest) copyBlobberObject(
 				return
 			}
 			l.Logger.Error(blobber.Baseurl, "Response: ", string(respBody))
-			err = errors.New("response_error", string(respBody))
+			err = newBlobberHTTPError(blobber, resp.StatusCode, string(respBody))
 			return
 		}()
 
@@ -197,24 +197,19 @@ func (req *CopyRequest) ProcessCopy() error {
 	objectTreeRefs, blobberErrors := req.ProcessWithBlobbers()
 
 	if !req.isConsensusOk() {
-		err := zboxutil.MajorError(blobberErrors)
-		if err != nil {
-			return errors.New("copy_failed", fmt.Sprintf("Copy failed. %s", err.Error()))
-		}
-
-		return errors.New("consensus_not_met",
-			fmt.Sprintf("Copy failed. Required consensus %d, got %d",
-				req.Consensus.consensusThresh, req.Consensus.consensus))
+		return newProcessError("copy_failed", "Copy failed.",
+			req.Consensus.consensusThresh, req.Consensus.consensus,
+			req.blobbers, blobberErrors)
 	}
 
 	writeMarkerMutex, err := CreateWriteMarkerMutex(client.GetClient(), req.allocationObj)
 	if err != nil {
-		return fmt.Errorf("Copy failed: %s", err.Error())
+		return fmt.Errorf("Copy failed: %w", err)
 	}
 	err = writeMarkerMutex.Lock(req.ctx, &req.copyMask, req.maskMU,
 		req.blobbers, &req.Consensus, 0, time.Minute, req.connectionID)
 	if err != nil {
-		return fmt.Errorf("Copy failed: %s", err.Error())
+		return fmt.Errorf("Copy failed: %w", err)
 	}
 	defer writeMarkerMutex.Unlock(req.ctx, req.copyMask, req.blobbers, time.Minute, req.connectionID) //nolint: errcheck
 
@@ -222,7 +217,7 @@ func (req *CopyRequest) ProcessCopy() error {
 	status, err := req.allocationObj.CheckAllocStatus()
 	if err != nil {
 		logger.Logger.Error("Error checking allocation status: ", err)
-		return fmt.Errorf("Copy failed: %s", err.Error())
+		return fmt.Errorf("Copy failed: %w", err)
 	}
 
 	if status == Repair {
@@ -284,9 +279,10 @@ func (req *CopyRequest) ProcessCopy() error {
 	}
 
 	if !req.isConsensusOk() {
-		return errors.New("consensus_not_met",
+		return newConsensusError(ConsensusNotMet,
 			fmt.Sprintf("Commit on copy failed. Required consensus %d, got %d",
-				req.Consensus.consensusThresh, req.Consensus.consensus))
+				req.Consensus.consensusThresh, req.Consensus.consensus),
+			req.Consensus.consensusThresh, req.Consensus.consensus, nil, nil)
 	}
 	return nil
 }
@@ -324,14 +320,9 @@ func (co *CopyOperation) Process(allocObj *Allocation, connectionID string) ([]f
 	objectTreeRefs, blobberErrors := cR.ProcessWithBlobbers()
 
 	if !cR.isConsensusOk() {
-		err := zboxutil.MajorError(blobberErrors)
-		if err != nil {
-			return nil, cR.copyMask, errors.New("copy_failed", fmt.Sprintf("Copy failed. %s", err.Error()))
-		}
-
-		return nil, cR.copyMask, errors.New("consensus_not_met",
-			fmt.Sprintf("Copy failed. Required consensus %d, got %d",
-				cR.Consensus.consensusThresh, cR.Consensus.consensus))
+		return nil, cR.copyMask, newProcessError("copy_failed", "Copy failed.",
+			cR.Consensus.consensusThresh, cR.Consensus.consensus,
+			cR.blobbers, blobberErrors)
 	}
 	return objectTreeRefs, cR.copyMask, nil
 
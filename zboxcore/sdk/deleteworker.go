@@ -11,7 +11,6 @@ import (
 	"time"
 
 	"github.com/0chain/errors"
-	thrown "github.com/0chain/errors"
 	"github.com/google/uuid"
 
 	"github.com/0chain/gosdk/constants"
@@ -118,8 +117,9 @@ func (req *DeleteRequest) deleteBlobberFile(
 				return
 			}
 
-			err = errors.New("response_error", fmt.Sprintf("unexpected response with status code %d, message: %s",
-				resp.StatusCode, string(respBody)))
+			err = newBlobberHTTPError(blobber, resp.StatusCode,
+				fmt.Sprintf("unexpected response with status code %d, message: %s",
+					resp.StatusCode, string(respBody)))
 			return
 		}()
 
@@ -190,6 +190,7 @@ func (req *DeleteRequest) ProcessDelete() (err error) {
 	req.consensus.consensus = removedNum
 
 	var errCount int32
+	blobberErrors := make([]error, len(req.blobbers))
 	wgErrors := make(chan error)
 	wgDone := make(chan bool)
 
@@ -198,9 +199,10 @@ func (req *DeleteRequest) ProcessDelete() (err error) {
 		pos = uint64(i.TrailingZeros())
 		go func(blobberIdx uint64) {
 			defer req.wg.Done()
-			err = req.deleteBlobberFile(req.blobbers[blobberIdx], int(blobberIdx))
+			err := req.deleteBlobberFile(req.blobbers[blobberIdx], int(blobberIdx))
 			if err != nil {
 				logger.Logger.Error("error during deleteBlobberFile", err)
+				blobberErrors[blobberIdx] = err
 				errC := atomic.AddInt32(&errCount, 1)
 				if errC > int32(req.consensus.fullconsensus-req.consensus.consensusThresh) {
 					wgErrors <- err
@@ -218,25 +220,32 @@ func (req *DeleteRequest) ProcessDelete() (err error) {
 	case <-wgDone:
 		break
 	case err := <-wgErrors:
-		return thrown.New("delete_failed", fmt.Sprintf("Delete failed. %s", err.Error()))
+		return &ConsensusError{
+			Code:          "delete_failed",
+			Msg:           fmt.Sprintf("Delete failed. %s", err.Error()),
+			Required:      req.consensus.consensusThresh,
+			Got:           req.consensus.getConsensus(),
+			BlobberErrors: []BlobberError{{Err: err}},
+		}
 	}
 
 	if !req.consensus.isConsensusOk() {
-		return errors.New("consensus_not_met",
+		return newConsensusError(ConsensusNotMet,
 			fmt.Sprintf("Consensus on delete failed. Required consensus %d got %d",
-				req.consensus.consensusThresh, req.consensus.getConsensus()))
+				req.consensus.consensusThresh, req.consensus.getConsensus()),
+			req.consensus.consensusThresh, req.consensus.getConsensus(), req.blobbers, blobberErrors)
 	}
 
 	writeMarkerMutex, err := CreateWriteMarkerMutex(client.GetClient(), req.allocationObj)
 	if err != nil {
-		return fmt.Errorf("Delete failed: %s", err.Error())
+		return fmt.Errorf("Delete failed: %w", err)
 	}
 	err = writeMarkerMutex.Lock(
 		req.ctx, &req.deleteMask, req.maskMu,
 		req.blobbers, &req.consensus, removedNum, time.Minute, req.connectionID)
 
 	if err != nil {
-		return fmt.Errorf("Delete failed: %s", err.Error())
+		return fmt.Errorf("Delete failed: %w", err)
 	}
 	defer writeMarkerMutex.Unlock(req.ctx, req.deleteMask, req.blobbers, time.Minute, req.connectionID) //nolint: errcheck
 
@@ -283,9 +292,10 @@ func (req *DeleteRequest) ProcessDelete() (err error) {
 	}
 
 	if !req.consensus.isConsensusOk() {
-		return errors.New("consensus_not_met",
+		return newConsensusError(ConsensusNotMet,
 			fmt.Sprintf("Consensus on commit not met. Required %d, got %d",
-				req.consensus.consensusThresh, req.consensus.getConsensus()))
+				req.consensus.consensusThresh, req.consensus.getConsensus()),
+			req.consensus.consensusThresh, req.consensus.getConsensus(), nil, nil)
 	}
 	return nil
 }
@@ -348,14 +358,9 @@ func (dop *DeleteOperation) Process(allocObj *Allocation, connectionID string) (
 	deleteReq.wg.Wait()
 
 	if !deleteReq.consensus.isConsensusOk() {
-		err := zboxutil.MajorError(blobberErrors)
-		if err != nil {
-			return nil, deleteReq.deleteMask, thrown.New("delete_failed", fmt.Sprintf("Delete failed. %s", err.Error()))
-		}
-
-		return nil, deleteReq.deleteMask, thrown.New("consensus_not_met",
-			fmt.Sprintf("Delete failed. Required consensus %d, got %d",
-				deleteReq.consensus.consensusThresh, deleteReq.consensus.consensus))
+		return nil, deleteReq.deleteMask, newProcessError("delete_failed", "Delete failed.",
+			deleteReq.consensus.consensusThresh, deleteReq.consensus.consensus,
+			deleteReq.blobbers, blobberErrors)
 	}
 	l.Logger.Info("Delete Process Ended ")
 	return objectTreeRefs, deleteReq.deleteMask, nil
@@ -81,18 +81,19 @@ func (req *DirRequest) ProcessDir(a *Allocation) error {
 	defer req.ctxCncl()
 	existingDirCount := req.ProcessWithBlobbers(a)
 	if !req.isConsensusOk() {
-		return errors.New("consensus_not_met", "directory creation failed due to consensus not met")
+		return newConsensusError(ConsensusNotMet, "directory creation failed due to consensus not met",
+			req.consensusThresh, req.getConsensus(), nil, nil)
 	}
 
 	writeMarkerMU, err := CreateWriteMarkerMutex(client.GetClient(), a)
 	if err != nil {
-		return fmt.Errorf("directory creation failed. Err: %s", err.Error())
+		return fmt.Errorf("directory creation failed. Err: %w", err)
 	}
 	err = writeMarkerMU.Lock(
 		req.ctx, &req.dirMask, req.mu,
 		req.blobbers, &req.Consensus, existingDirCount, time.Minute, req.connectionID)
 	if err != nil {
-		return fmt.Errorf("directory creation failed. Err: %s", err.Error())
+		return fmt.Errorf("directory creation failed. Err: %w", err)
 	}
 	defer writeMarkerMU.Unlock(req.ctx, req.dirMask,
 		a.Blobbers, time.Minute, req.connectionID) //nolint: errcheck
@@ -101,7 +102,7 @@ func (req *DirRequest) ProcessDir(a *Allocation) error {
 	l.Logger.Info("Allocation status: ", status)
 	if err != nil {
 		l.Logger.Error("Error checking allocation status: ", err)
-		return fmt.Errorf("directory creation failed: %s", err.Error())
+		return fmt.Errorf("directory creation failed: %w", err)
 	}
 
 	if status == Repair {
@@ -170,7 +171,8 @@ func (req *DirRequest) commitRequest(existingDirCount int) error {
 	}
 
 	if !req.isConsensusOk() {
-		return errors.New("consensus_not_met", "directory creation failed due consensus not met")
+		return newConsensusError(ConsensusNotMet, "directory creation failed due consensus not met",
+			req.consensusThresh, req.getConsensus(), nil, nil)
 	}
 	return nil
 }
@@ -264,7 +266,7 @@ func (req *DirRequest) createDirInBlobber(blobber *blockchain.StorageNode, pos u
 				return
 			}
 
-			err = errors.New("response_error", msg)
+			err = newBlobberHTTPError(blobber, resp.StatusCode, msg)
 			return
 		}()
 
@@ -320,7 +322,8 @@ func (dirOp *DirOperation) Process(allocObj *Allocation, connectionID string) ([
 	dirOp.alreadyExists = dR.alreadyExists
 
 	if !dR.isConsensusOk() {
-		return nil, dR.dirMask, errors.New("consensus_not_met", "directory creation failed due to consensus not met")
+		return nil, dR.dirMask, newConsensusError(ConsensusNotMet, "directory creation failed due to consensus not met",
+			dR.consensusThresh, dR.getConsensus(), nil, nil)
 	}
 	return refs, dR.dirMask, nil
 
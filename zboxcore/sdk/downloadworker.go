@@ -79,6 +79,10 @@ type DownloadRequest struct {
 	chunksPerShard     int64
 	size               int64
 	offset             int64
+	traffic            *trafficShaper
+	priority           TrafficPriority
+	blockCache         *Cache
+	blockCachePrefix   string
 }
 
 type blockData struct {
@@ -86,6 +90,17 @@ type blockData struct {
 	data     []byte
 }
 
+// initBlockCache enables the cache of the downloaded blocks of the file
+// version identified by contentHash. Blocks of encrypted files are not cached.
+func (req *DownloadRequest) initBlockCache(lookupHash, contentHash string) {
+	cache := GetCache()
+	if cache == nil || req.encryptedKey != "" || contentHash == "" {
+		return
+	}
+	req.blockCache = cache
+	req.blockCachePrefix = blockCacheKeyPrefix(req.allocationID, lookupHash, contentHash, req.contentMode)
+}
+
 func (req *DownloadRequest) removeFromMask(pos uint64) {
 	req.maskMu.Lock()
 	req.downloadMask = req.downloadMask.And(zboxutil.NewUint128(1).Lsh(pos).Not())
@@ -131,9 +146,45 @@ func (req *DownloadRequest) getBlocksDataFromBlobbers(startBlock, totalBlock int
 	return shards, err
 }
 
-// getBlocksData will get data blocks for some interval from minimal blobers and aggregate them and
-// return to the caller
+// getBlocksData will get data blocks for some interval from the cache, or from minimal blobers,
+// and aggregate them and return to the caller
 func (req *DownloadRequest) getBlocksData(startBlock, totalBlock int64) ([]byte, error) {
+	if req.blockCache == nil {
+		return req.downloadBlocksData(startBlock, totalBlock)
+	}
+
+	// download the range of blocks missing from the cache
+	c := req.datashards * req.effectiveBlockSize
+	data := make([]byte, c*int(totalBlock))
+	first, last := int64(-1), int64(-1)
+	for i := int64(0); i < totalBlock; i++ {
+		block, ok := req.blockCache.getBlock(blockCacheKey(req.blockCachePrefix, startBlock+i), c)
+		if !ok {
+			if first < 0 {
+				first = i
+			}
+			last = i
+			continue
+		}
+		copy(data[i*int64(c):], block)
+	}
+	if first < 0 {
+		return data, nil
+	}
+
+	downloaded, err := req.downloadBlocksData(startBlock+first, last-first+1)
+	if err != nil {
+		return nil, err
+	}
+	copy(data[first*int64(c):], downloaded)
+	for i := first; i <= last; i++ {
+		req.blockCache.setBlock(blockCacheKey(req.blockCachePrefix, startBlock+i), data[i*int64(c):(i+1)*int64(c)])
+	}
+	return data, nil
+}
+
+// downloadBlocksData downloads the data blocks of an interval and decodes them.
+func (req *DownloadRequest) downloadBlocksData(startBlock, totalBlock int64) ([]byte, error) {
 
 	shards, err := req.getBlocksDataFromBlobbers(startBlock, totalBlock)
 	if err != nil {
@@ -202,6 +253,8 @@ func (req *DownloadRequest) downloadBlock(
 			encryptedKey:       req.encryptedKey,
 			shouldVerify:       req.shouldVerify,
 			connectionID:       req.connectionID,
+			traffic:            req.traffic,
+			priority:           req.priority,
 		}
 
 		if blockDownloadReq.blobber.IsSkip() {
@@ -644,7 +697,7 @@ func (req *DownloadRequest) handleReadMarkerError(resp *http.Response, blobber *
 		if appErrorCode == NotEnoughTokens {
 			logger.Logger.Debug(fmt.Sprintf("NotEnoughTokens - blobberID: %v", blobber.ID))
 			blobber.SetSkip(true)
-			return errors.New(NotEnoughTokens, string(respBody))
+			return &NotEnoughTokensError{BlobberID: blobber.ID, Msg: string(respBody)}
 		}
 		if appErrorCode == InvalidAuthTicket {
 			logger.Logger.Debug(fmt.Sprintf("InvalidAuthTicket - blobberID: %v", blobber.ID))
@@ -677,7 +730,7 @@ func (req *DownloadRequest) handleReadMarkerError(resp *http.Response, blobber *
 		return fmt.Errorf("download_error: response status: %d, error: %v", resp.StatusCode, rspData.err)
 	}
 
-	return fmt.Errorf("response_error: %s", string(respBody))
+	return newBlobberHTTPError(blobber, resp.StatusCode, string(respBody))
 }
 
 func IsErrCode(err error, code string) bool {
@@ -687,6 +740,10 @@ func IsErrCode(err error, code string) bool {
 	if e, ok := err.(*errors.Error); ok && e.Code == code {
 		return true
 	}
+	// typed errors of the sdk match the errors having their code
+	if errors.Is(err, errors.New(code, "")) {
+		return true
+	}
 	return strings.Contains(err.Error(), code)
 }
 
@@ -764,6 +821,11 @@ func (req *DownloadRequest) calculateShardsParams(
 	req.size = size
 	req.encryptedKey = fRef.EncryptedKey
 	req.chunkSize = int(fRef.ChunkSize)
+	if req.contentMode == DOWNLOAD_CONTENT_THUMB {
+		req.initBlockCache(fRef.LookupHash, fRef.ActualThumbnailHash)
+	} else {
+		req.initBlockCache(fRef.LookupHash, fRef.ValidationRoot)
+	}
 
 	effectivePerShardSize := (size + int64(req.datashards) - 1) / int64(req.datashards)
 	effectiveBlockSize := fRef.ChunkSize
@@ -918,7 +980,7 @@ func (req *DownloadRequest) getFileMetaConsensus(fMetaResp []*fileMetaResponse)
 
 	if selected == nil {
 		l.Logger.Error("File consensus not found for ", req.remotefilepath)
-		return nil, errors.New("consensus_not_met", "")
+		return nil, newConsensusError(ConsensusNotMet, "", req.consensusThresh, 0, nil, nil)
 	}
 
 	req.validationRootMap = make(map[string]*blobberFile)
@@ -967,7 +1029,7 @@ func (req *DownloadRequest) getFileMetaConsensus(fMetaResp []*fileMetaResponse)
 	}
 	req.consensus = foundMask.CountOnes()
 	if !req.isConsensusOk() {
-		return nil, fmt.Errorf("consensus_not_met")
+		return nil, newConsensusError(ConsensusNotMet, "", req.consensusThresh, req.consensus, nil, nil)
 	}
 	req.downloadMask = foundMask
 	return selected.fileref, nil
@@ -0,0 +1,209 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/constants"
+	"github.com/0chain/gosdk/zboxcore/blockchain"
+	"github.com/0chain/gosdk/zboxcore/zboxutil"
+)
+
+// error codes of the typed errors
+const (
+	ConsensusNotMet     = "consensus_not_met"
+	ResponseError       = "response_error"
+	LockConsensusNotMet = "lock_consensus_not_met"
+	LockTimeout         = "lock_timeout"
+)
+
+// Sentinel errors to be used with errors.Is. The typed errors below match the
+// sentinel of their kind, and also any *errors.Error having the same code.
+var (
+	ErrConsensusNotMet = errors.New(ConsensusNotMet, "consensus not met")
+	ErrBlobberResponse = errors.New(ResponseError, "unexpected blobber response")
+	ErrNotEnoughTokens = errors.New(NotEnoughTokens, "not enough tokens")
+	ErrLockConflict    = errors.New(LockConsensusNotMet, "write marker lock is held by another connection")
+	// ErrNotFound is constants.ErrNotFound, so that existing checks keep working.
+	ErrNotFound = constants.ErrNotFound
+)
+
+// BlobberError is the error returned by a single blobber.
+type BlobberError struct {
+	BlobberID  string
+	BlobberURL string
+	Err        error
+}
+
+func (e BlobberError) Error() string {
+	return fmt.Sprintf("%s: %v", e.BlobberURL, e.Err)
+}
+
+// ConsensusError is returned when an operation didn't succeed on enough
+// blobbers. Code is consensus_not_met, or <operation>_failed when it failed
+// because of the errors returned by the blobbers, in which case Unwrap returns
+// the most frequent of them.
+type ConsensusError struct {
+	Code     string
+	Msg      string
+	Required int
+	Got      int
+	// BlobberErrors is the per blobber breakdown of the failure. It is empty
+	// if the blobbers responded but the responses didn't match.
+	BlobberErrors []BlobberError
+}
+
+func (e *ConsensusError) Error() string {
+	if e.Msg == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Msg
+}
+
+// Is implements errors.Is.
+func (e *ConsensusError) Is(target error) bool {
+	return target == ErrConsensusNotMet || isErrCode(target, e.Code)
+}
+
+// Unwrap returns the most frequent blobber error, if any.
+func (e *ConsensusError) Unwrap() error {
+	errs := make([]error, 0, len(e.BlobberErrors))
+	for _, be := range e.BlobberErrors {
+		errs = append(errs, be.Err)
+	}
+	return zboxutil.MajorError(errs)
+}
+
+// BlobberHTTPError is returned when a blobber responds with an unexpected status.
+type BlobberHTTPError struct {
+	BlobberID  string
+	BlobberURL string
+	StatusCode int
+	Msg        string
+}
+
+func (e *BlobberHTTPError) Error() string {
+	return ResponseError + ": " + e.Msg
+}
+
+// Is implements errors.Is. A 404 response also matches ErrNotFound.
+func (e *BlobberHTTPError) Is(target error) bool {
+	if target == ErrBlobberResponse || isErrCode(target, ResponseError) {
+		return true
+	}
+	return target == ErrNotFound && e.StatusCode == 404
+}
+
+// NotEnoughTokensError is returned when the read or write pool of the client
+// can't pay for the operation.
+type NotEnoughTokensError struct {
+	BlobberID string
+	Msg       string
+}
+
+func (e *NotEnoughTokensError) Error() string {
+	return NotEnoughTokens + ": " + e.Msg
+}
+
+// Is implements errors.Is.
+func (e *NotEnoughTokensError) Is(target error) bool {
+	return target == ErrNotEnoughTokens || isErrCode(target, NotEnoughTokens)
+}
+
+// LockConflictError is returned when the write marker lock can't be acquired
+// on enough blobbers, usually because another connection holds it.
+type LockConflictError struct {
+	ConnectionID string
+	Required     int
+	Got          int
+	Msg          string
+	// Timeout is true if the lead blobber didn't grant the lock in time.
+	Timeout bool
+}
+
+func (e *LockConflictError) code() string {
+	if e.Timeout {
+		return LockTimeout
+	}
+	return LockConsensusNotMet
+}
+
+func (e *LockConflictError) Error() string {
+	return e.code() + ": " + e.Msg
+}
+
+// Is implements errors.Is.
+func (e *LockConflictError) Is(target error) bool {
+	return target == ErrLockConflict || isErrCode(target, e.code())
+}
+
+// NotFoundError is returned when a file or directory doesn't exist.
+type NotFoundError struct {
+	Path string
+	Msg  string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Msg == "" {
+		return ErrNotFound.Error() + ": " + e.Path
+	}
+	return e.Msg
+}
+
+// Is implements errors.Is.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+func isErrCode(target error, code string) bool {
+	e, ok := target.(*errors.Error)
+	return ok && e.Code == code
+}
+
+func newBlobberHTTPError(b *blockchain.StorageNode, statusCode int, msg string) *BlobberHTTPError {
+	return &BlobberHTTPError{
+		BlobberID:  b.ID,
+		BlobberURL: b.Baseurl,
+		StatusCode: statusCode,
+		Msg:        strings.TrimSpace(msg),
+	}
+}
+
+// newConsensusError builds a ConsensusError with the breakdown of the non nil
+// blobberErrors, indexed like blobbers.
+func newConsensusError(code, msg string, required, got int,
+	blobbers []*blockchain.StorageNode, blobberErrors []error) *ConsensusError {
+
+	e := &ConsensusError{
+		Code:     code,
+		Msg:      strings.TrimSpace(msg),
+		Required: required,
+		Got:      got,
+	}
+	for i, err := range blobberErrors {
+		if err == nil || i >= len(blobbers) {
+			continue
+		}
+		e.BlobberErrors = append(e.BlobberErrors, BlobberError{
+			BlobberID:  blobbers[i].ID,
+			BlobberURL: blobbers[i].Baseurl,
+			Err:        err,
+		})
+	}
+	return e
+}
+
+// newProcessError returns the error of an operation that didn't reach the
+// consensus: failCode with the most frequent blobber error if the blobbers
+// failed, consensus_not_met otherwise.
+func newProcessError(failCode, prefix string, required, got int,
+	blobbers []*blockchain.StorageNode, blobberErrors []error) *ConsensusError {
+
+	if err := zboxutil.MajorError(blobberErrors); err != nil {
+		return newConsensusError(failCode, prefix+" "+err.Error(), required, got, blobbers, blobberErrors)
+	}
+	return newConsensusError(ConsensusNotMet,
+		fmt.Sprintf("%s Required consensus %d, got %d", prefix, required, got),
+		required, got, blobbers, blobberErrors)
+}
@@ -0,0 +1,57 @@
+package sdk
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/constants"
+	"github.com/0chain/gosdk/zboxcore/blockchain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsensusError(t *testing.T) {
+	blobbers := []*blockchain.StorageNode{
+		{ID: "b0", Baseurl: "http://b0"},
+		{ID: "b1", Baseurl: "http://b1"},
+		{ID: "b2", Baseurl: "http://b2"},
+	}
+	httpErr := newBlobberHTTPError(blobbers[1], http.StatusBadRequest, "bad request")
+	err := newProcessError("copy_failed", "Copy failed.", 2, 1, blobbers, []error{nil, httpErr, nil})
+
+	require.Equal(t, "copy_failed: Copy failed. response_error: bad request", err.Error())
+	require.True(t, errors.Is(err, ErrConsensusNotMet))
+	require.True(t, errors.Is(err, errors.New("copy_failed", "")))
+	require.True(t, IsErrCode(err, "copy_failed"))
+	require.Len(t, err.BlobberErrors, 1)
+	require.Equal(t, "b1", err.BlobberErrors[0].BlobberID)
+
+	var target *BlobberHTTPError
+	require.True(t, errors.As(fmt.Errorf("Copy failed: %w", err), &target))
+	require.Equal(t, http.StatusBadRequest, target.StatusCode)
+
+	err = newProcessError("copy_failed", "Copy failed.", 2, 1, blobbers, nil)
+	require.Equal(t, "consensus_not_met: Copy failed. Required consensus 2, got 1", err.Error())
+	require.Nil(t, err.Unwrap())
+}
+
+func TestTypedErrors(t *testing.T) {
+	notFound := newBlobberHTTPError(&blockchain.StorageNode{}, http.StatusNotFound, "")
+	require.True(t, errors.Is(notFound, ErrBlobberResponse))
+	require.True(t, errors.Is(notFound, ErrNotFound))
+
+	require.True(t, errors.Is(&NotFoundError{Path: "/a"}, constants.ErrNotFound))
+	require.Equal(t, "ref not found: /a", (&NotFoundError{Path: "/a"}).Error())
+
+	tokens := &NotEnoughTokensError{BlobberID: "b0", Msg: "read pool is empty"}
+	require.True(t, errors.Is(tokens, ErrNotEnoughTokens))
+	require.True(t, IsErrCode(tokens, NotEnoughTokens))
+
+	lock := &LockConflictError{Msg: "Required consensus 3 got 1"}
+	require.True(t, errors.Is(lock, ErrLockConflict))
+	require.False(t, errors.Is(lock, ErrConsensusNotMet))
+	timeout := &LockConflictError{Timeout: true}
+	require.True(t, errors.Is(timeout, ErrLockConflict))
+	require.True(t, IsErrCode(timeout, LockTimeout))
+}
@@ -35,10 +35,11 @@ func (req *ListRequest) getFileMetaInfoFromBlobber(blobber *blockchain.StorageNo
 		rspCh <- &fileMetaResponse{fileref: fileRef, blobberIdx: blobberIdx, err: err}
 	}
 	defer fileMetaRetFn()
+	pathHash := req.remotefilepathhash
 	if len(req.remotefilepath) > 0 {
-		req.remotefilepathhash = fileref.GetReferenceLookup(req.allocationID, req.remotefilepath)
+		pathHash = fileref.GetReferenceLookup(req.allocationID, req.remotefilepath)
 	}
-	err = formWriter.WriteField("path_hash", req.remotefilepathhash)
+	err = formWriter.WriteField("path_hash", pathHash)
 	if err != nil {
 		l.Logger.Error("File meta info request error: ", err.Error())
 		return
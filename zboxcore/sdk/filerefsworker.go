@@ -9,7 +9,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/0chain/errors"
 	"github.com/0chain/gosdk/core/common"
 	"github.com/0chain/gosdk/zboxcore/blockchain"
 	l "github.com/0chain/gosdk/zboxcore/logger"
@@ -97,8 +96,14 @@ func (o *ObjectTreeRequest) GetRefs() (*ObjectTreeResult, error) {
 	if code, _ := zboxutil.GetErrorMessageCode(majorErrorMsg); code == INVALID_PATH {
 		return &ObjectTreeResult{}, nil
 	}
-	var selected *ObjectTreeResult
+	var (
+		selected *ObjectTreeResult
+		got      int
+	)
 	for k, v := range hashCount {
+		if v > got {
+			got = v
+		}
 		if v >= o.consensusThresh {
 			selected = hashRefsMap[k]
 			break
@@ -108,7 +113,8 @@ func (o *ObjectTreeRequest) GetRefs() (*ObjectTreeResult, error) {
 	if selected != nil {
 		return selected, nil
 	}
-	return nil, errors.New("consensus_failed", "Refs consensus is less than consensus threshold")
+	return nil, newConsensusError("consensus_failed", "Refs consensus is less than consensus threshold",
+		o.consensusThresh, got, o.blobbers, oTreeResponseErrors)
 }
 
 func (o *ObjectTreeRequest) getFileRefs(oTR *oTreeResponse, bUrl string) {
@@ -152,7 +158,11 @@ func (o *ObjectTreeRequest) getFileRefs(oTR *oTreeResponse, bUrl string) {
 			}
 			return nil
 		} else {
-			return errors.New("response_error", fmt.Sprintf("got status %d, err: %s", resp.StatusCode, respBody))
+			return &BlobberHTTPError{
+				BlobberURL: bUrl,
+				StatusCode: resp.StatusCode,
+				Msg:        fmt.Sprintf("got status %d, err: %s", resp.StatusCode, respBody),
+			}
 		}
 	})
 	if err != nil {
@@ -186,6 +196,7 @@ type SimilarField struct {
 	EncryptedKey        string `json:"encrypted_key"`
 	ActualFileSize      int64  `json:"actual_file_size"`
 	ActualFileHash      string `json:"actual_file_hash"`
+	ValidationRoot      string `json:"validation_root"`
 	MimeType            string `json:"mimetype"`
 	ActualThumbnailSize int64  `json:"actual_thumbnail_size"`
 	ActualThumbnailHash string `json:"actual_thumbnail_hash"`
@@ -228,8 +239,10 @@ func (r *RecentlyAddedRefRequest) GetRecentlyAddedRefs() (*RecentlyAddedRefResul
 
 	hashCount := make(map[string]int)
 	hashRefsMap := make(map[string]*RecentlyAddedRefResult)
+	responseErrors := make([]error, totalBlobbers)
 
-	for _, response := range responses {
+	for i, response := range responses {
+		responseErrors[i] = response.err
 		if response.err != nil {
 			l.Logger.Error(response.err)
 			continue
@@ -255,8 +268,14 @@ func (r *RecentlyAddedRefRequest) GetRecentlyAddedRefs() (*RecentlyAddedRefResul
 		}
 	}
 
-	var selected *RecentlyAddedRefResult
+	var (
+		selected *RecentlyAddedRefResult
+		got      int
+	)
 	for k, v := range hashCount {
+		if v > got {
+			got = v
+		}
 		if v >= r.consensusThresh {
 			selected = hashRefsMap[k]
 			break
@@ -264,7 +283,8 @@ func (r *RecentlyAddedRefRequest) GetRecentlyAddedRefs() (*RecentlyAddedRefResul
 	}
 
 	if selected == nil {
-		return nil, errors.New("consensus_failed", "Refs consensus is less than consensus threshold")
+		return nil, newConsensusError("consensus_failed", "Refs consensus is less than consensus threshold",
+			r.consensusThresh, got, r.blobbers, responseErrors)
 	}
 	return selected, nil
 }
@@ -74,8 +74,10 @@ func (req *ListRequest) getListInfoFromBlobber(blobber *blockchain.StorageNode,
 	}
 	defer listRetFn()
 
+	// the requests to the blobbers run concurrently, each computes its own hash
+	pathHash := req.remotefilepathhash
 	if len(req.remotefilepath) > 0 {
-		req.remotefilepathhash = fileref.GetReferenceLookup(req.allocationID, req.remotefilepath)
+		pathHash = fileref.GetReferenceLookup(req.allocationID, req.remotefilepath)
 	}
 	//formWriter.WriteField("path_hash", req.remotefilepathhash)
 	//Logger.Info("Path hash for list dir: ", req.remotefilepathhash)
@@ -91,7 +93,7 @@ func (req *ListRequest) getListInfoFromBlobber(blobber *blockchain.StorageNode,
 	}
 
 	//formWriter.Close()
-	httpreq, err := zboxutil.NewListRequest(blobber.Baseurl, req.allocationID, req.allocationTx, req.remotefilepath, req.remotefilepathhash, string(authTokenBytes))
+	httpreq, err := zboxutil.NewListRequest(blobber.Baseurl, req.allocationID, req.allocationTx, req.remotefilepath, pathHash, string(authTokenBytes))
 	if err != nil {
 		l.Logger.Error("List info request error: ", err.Error())
 		return
@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"github.com/0chain/errors"
-	thrown "github.com/0chain/errors"
 	"github.com/google/uuid"
 
 	"github.com/0chain/gosdk/constants"
@@ -144,7 +143,7 @@ func (req *MoveRequest) moveBlobberObject(
 				return
 			}
 			l.Logger.Error(blobber.Baseurl, "Response: ", string(respBody))
-			err = errors.New("response_error", string(respBody))
+			err = newBlobberHTTPError(blobber, resp.StatusCode, string(respBody))
 			return
 		}()
 
@@ -193,31 +192,26 @@ func (req *MoveRequest) ProcessMove() error {
 	objectTreeRefs, blobberErrors := req.ProcessWithBlobbers()
 
 	if !req.isConsensusOk() {
-		err := zboxutil.MajorError(blobberErrors)
-		if err != nil {
-			return errors.New("move_failed", fmt.Sprintf("Move failed. %s", err.Error()))
-		}
-
-		return errors.New("consensus_not_met",
-			fmt.Sprintf("Move failed. Required consensus %d, got %d",
-				req.Consensus.consensusThresh, req.Consensus.consensus))
+		return newProcessError("move_failed", "Move failed.",
+			req.Consensus.consensusThresh, req.Consensus.consensus,
+			req.blobbers, blobberErrors)
 	}
 
 	writeMarkerMutex, err := CreateWriteMarkerMutex(client.GetClient(), req.allocationObj)
 	if err != nil {
-		return fmt.Errorf("Move failed: %s", err.Error())
+		return fmt.Errorf("Move failed: %w", err)
 	}
 	err = writeMarkerMutex.Lock(req.ctx, &req.moveMask, req.maskMU,
 		req.blobbers, &req.Consensus, 0, time.Minute, req.connectionID)
 	if err != nil {
-		return fmt.Errorf("Move failed: %s", err.Error())
+		return fmt.Errorf("Move failed: %w", err)
 	}
 
 	//Check if the allocation is to be repaired or rolled back
 	status, err := req.allocationObj.CheckAllocStatus()
 	if err != nil {
 		logger.Logger.Error("Error checking allocation status: ", err)
-		return fmt.Errorf("Move failed: %s", err.Error())
+		return fmt.Errorf("Move failed: %w", err)
 	}
 	defer writeMarkerMutex.Unlock(req.ctx, req.moveMask, req.blobbers, time.Minute, req.connectionID) //nolint: errcheck
 
@@ -279,9 +273,10 @@ func (req *MoveRequest) ProcessMove() error {
 	}
 
 	if !req.isConsensusOk() {
-		return errors.New("consensus_not_met",
+		return newConsensusError(ConsensusNotMet,
 			fmt.Sprintf("Commit on move failed. Required consensus %d, got %d",
-				req.Consensus.consensusThresh, req.Consensus.consensus))
+				req.Consensus.consensusThresh, req.Consensus.consensus),
+			req.Consensus.consensusThresh, req.Consensus.consensus, nil, nil)
 	}
 	return nil
 }
@@ -317,14 +312,9 @@ func (mo *MoveOperation) Process(allocObj *Allocation, connectionID string) ([]f
 	objectTreeRefs, blobberErrors := mR.ProcessWithBlobbers()
 
 	if !mR.Consensus.isConsensusOk() {
-		err := zboxutil.MajorError(blobberErrors)
-		if err != nil {
-			return nil, mR.moveMask, thrown.New("move_failed", fmt.Sprintf("Move failed. %s", err.Error()))
-		}
-
-		return nil, mR.moveMask, thrown.New("consensus_not_met",
-			fmt.Sprintf("Move failed. Required consensus %d, got %d",
-				mR.Consensus.consensusThresh, mR.Consensus.consensus))
+		return nil, mR.moveMask, newProcessError("move_failed", "Move failed.",
+			mR.Consensus.consensusThresh, mR.Consensus.consensus,
+			mR.blobbers, blobberErrors)
 	}
 	return objectTreeRefs, mR.moveMask, nil
 }
@@ -125,7 +125,7 @@ func (mo *MultiOperation) createConnectionObj(blobberIdx int) (err error) {
 				return
 			}
 			l.Logger.Error(blobber.Baseurl, "Response: ", string(respBody))
-			err = errors.New("response_error", string(respBody))
+			err = newBlobberHTTPError(blobber, resp.StatusCode, string(respBody))
 			return
 		}()
 
@@ -187,13 +187,21 @@ func (mo *MultiOperation) Process() error {
 	if mo.operationMask.CountOnes() < mo.consensusThresh || ctx.Err() != nil {
 		majorErr := zboxutil.MajorError(errsSlice)
 		if majorErr != nil {
-			return errors.New("consensus_not_met",
+			consensusErr := newConsensusError(ConsensusNotMet,
 				fmt.Sprintf("Multioperation failed. Required consensus %d got %d. Major error: %s",
-					mo.consensusThresh, mo.operationMask.CountOnes(), majorErr.Error()))
+					mo.consensusThresh, mo.operationMask.CountOnes(), majorErr.Error()),
+				mo.consensusThresh, mo.operationMask.CountOnes(), nil, nil)
+			// keep the per blobber breakdown of the failed operation
+			var opErr *ConsensusError
+			if errors.As(majorErr, &opErr) {
+				consensusErr.BlobberErrors = opErr.BlobberErrors
+			}
+			return consensusErr
 		}
-		return errors.New("consensus_not_met",
+		return newConsensusError(ConsensusNotMet,
 			fmt.Sprintf("Multioperation failed. Required consensus %d got %d",
-				mo.consensusThresh, mo.operationMask.CountOnes()))
+				mo.consensusThresh, mo.operationMask.CountOnes()),
+			mo.consensusThresh, mo.operationMask.CountOnes(), nil, nil)
 	}
 
 	// Take transpose of mo.change because it will be easier to iterate mo if it contains blobber changes
@@ -204,14 +212,14 @@ func (mo *MultiOperation) Process() error {
 
 	writeMarkerMutex, err := CreateWriteMarkerMutex(client.GetClient(), mo.allocationObj)
 	if err != nil {
-		return fmt.Errorf("Operation failed: %s", err.Error())
+		return fmt.Errorf("Operation failed: %w", err)
 	}
 
 	l.Logger.Info("Trying to lock write marker.....")
 	err = writeMarkerMutex.Lock(mo.ctx, &mo.operationMask, mo.maskMU,
 		mo.allocationObj.Blobbers, &mo.Consensus, 0, time.Minute, mo.connectionID)
 	if err != nil {
-		return fmt.Errorf("Operation failed: %s", err.Error())
+		return fmt.Errorf("Operation failed: %w", err)
 	}
 	l.Logger.Info("WriteMarker locked")
 
@@ -219,7 +227,7 @@ func (mo *MultiOperation) Process() error {
 	if err != nil {
 		logger.Logger.Error("Error checking allocation status", err)
 		writeMarkerMutex.Unlock(mo.ctx, mo.operationMask, mo.allocationObj.Blobbers, time.Minute, mo.connectionID) //nolint: errcheck
-		return fmt.Errorf("Check allocation status failed: %s", err.Error())
+		return fmt.Errorf("Check allocation status failed: %w", err)
 	}
 	if status == Repair {
 		logger.Logger.Info("Repairing allocation")
@@ -288,10 +296,11 @@ func (mo *MultiOperation) Process() error {
 		}
 	}
 
-	if !mo.isConsensusOk() {
-		err := errors.New("consensus_not_met",
+	if !mo.recordConsensus(mo.ctx, "commit") {
+		err := newConsensusError(ConsensusNotMet,
 			fmt.Sprintf("Commit failed. Required consensus %d, got %d",
-				mo.Consensus.consensusThresh, mo.Consensus.consensus))
+				mo.Consensus.consensusThresh, mo.Consensus.consensus),
+			mo.Consensus.consensusThresh, mo.Consensus.consensus, nil, nil)
 		if mo.getConsensus() != 0 {
 			mo.allocationObj.RollbackWithMask(rollbackMask)
 		}
@@ -301,6 +310,7 @@ func (mo *MultiOperation) Process() error {
 		return err
 	}
 
+	GetCache().InvalidateAllocation(mo.allocationObj.ID)
 	for _, op := range mo.operations {
 		op.Completed(mo.allocationObj)
 	}
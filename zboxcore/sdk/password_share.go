@@ -0,0 +1,146 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/encryption"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/0chain/gosdk/zboxcore/client"
+	zboxenc "github.com/0chain/gosdk/zboxcore/encryption"
+	"github.com/0chain/gosdk/zboxcore/zboxutil"
+)
+
+// ErrPasswordRequired is returned by GetAllocationFromAuthTicket for the links of
+// GetPasswordAuthTicket, opened by GetAllocationFromPasswordAuthTicket.
+var ErrPasswordRequired = errors.New("password_required", "the auth ticket is protected by a password")
+
+// passwordAuthTicket is a share link of GetPasswordAuthTicket.
+type passwordAuthTicket struct {
+	AllocationID    string `json:"allocation_id"`
+	AuthTicket      string `json:"auth_ticket"`
+	SignatureScheme string `json:"signature_scheme"`
+	// WrappedKey is the wallet the auth ticket was issued to, encrypted with
+	// the password by zboxutil.ScryptEncrypt, hex encoded.
+	WrappedKey string `json:"wrapped_key"`
+}
+
+// passwordKey is the key ScryptEncrypt derives the wrapping key from.
+func passwordKey(password string) []byte {
+	return encryption.RawHash(password)
+}
+
+// GetPasswordAuthTicket shares path with whoever knows password, without
+// their encryption public key. The share is private to a wallet created for
+// the link: the blobbers get the re-encryption key to its encryption key, and
+// its keys are wrapped in the link with a key derived from the password by
+// scrypt. A leaked link is useless without the password. Like private shares,
+// a file must be encrypted.
+func (a *Allocation) GetPasswordAuthTicket(path, filename, referenceType, password string,
+	expiration int64, availableAfter *time.Time) (string, error) {
+
+	if password == "" {
+		return "", errors.New("invalid_password", "password is required")
+	}
+	scheme := client.GetClient().SignatureScheme
+	if scheme == "" {
+		scheme = "bls0chain"
+	}
+	w, err := zcncrypto.NewSignatureScheme(scheme).GenerateKeys()
+	if err != nil {
+		return "", err
+	}
+
+	encScheme := zboxenc.NewEncryptionScheme()
+	if _, err := encScheme.Initialize(w.Mnemonic); err != nil {
+		return "", err
+	}
+	encPublicKey, err := encScheme.GetPublicKey()
+	if err != nil {
+		return "", err
+	}
+
+	at, err := a.GetAuthTicket(path, filename, referenceType, w.ClientID, encPublicKey, expiration, availableAfter)
+	if err != nil {
+		return "", err
+	}
+	walletJSON, err := w.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return newPasswordAuthTicket(a.ID, at, scheme, walletJSON, password)
+}
+
+func newPasswordAuthTicket(allocationID, authTicket, scheme, walletJSON, password string) (string, error) {
+	wrapped, err := zboxutil.ScryptEncrypt(passwordKey(password), []byte(walletJSON))
+	if err != nil {
+		return "", err
+	}
+	buf, err := json.Marshal(&passwordAuthTicket{
+		AllocationID:    allocationID,
+		AuthTicket:      authTicket,
+		SignatureScheme: scheme,
+		WrappedKey:      hex.EncodeToString(wrapped),
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// decodePasswordAuthTicket decodes link, false if it isn't a link of
+// GetPasswordAuthTicket.
+func decodePasswordAuthTicket(link string) (*passwordAuthTicket, bool) {
+	buf, err := base64.StdEncoding.DecodeString(link)
+	if err != nil {
+		return nil, false
+	}
+	p := &passwordAuthTicket{}
+	if err := json.Unmarshal(buf, p); err != nil || p.WrappedKey == "" {
+		return nil, false
+	}
+	return p, true
+}
+
+// OpenPasswordAuthTicket unwraps a link of GetPasswordAuthTicket with
+// password. It returns the auth ticket and the json of the wallet it was
+// issued to, the wallet to download with.
+func OpenPasswordAuthTicket(link, password string) (authTicket, walletJSON string, err error) {
+	p, ok := decodePasswordAuthTicket(link)
+	if !ok {
+		return "", "", errors.New("auth_ticket_decode_error", "not a password protected auth ticket")
+	}
+	wrapped, err := hex.DecodeString(p.WrappedKey)
+	if err != nil {
+		return "", "", errors.New("auth_ticket_decode_error", "Error decoding the wrapped key."+err.Error())
+	}
+	buf, err := zboxutil.ScryptDecrypt(passwordKey(password), wrapped)
+	if err != nil {
+		return "", "", errors.New("invalid_password", "the password doesn't open the auth ticket")
+	}
+	return p.AuthTicket, string(buf), nil
+}
+
+// GetAllocationFromPasswordAuthTicket is GetAllocationFromAuthTicket for the
+// links of GetPasswordAuthTicket. It returns the allocation, the auth ticket
+// and the json of the wallet unwrapped with password. The client isn't
+// changed: the caller downloads with the auth ticket as that wallet, for
+// example by initializing the sdk with it, so the recipient needs no wallet of
+// its own.
+func GetAllocationFromPasswordAuthTicket(link, password string) (alloc *Allocation, authTicket, walletJSON string, err error) {
+	if !sdkInitialized {
+		return nil, "", "", sdkNotInitialized
+	}
+	authTicket, walletJSON, err = OpenPasswordAuthTicket(link, password)
+	if err != nil {
+		return nil, "", "", err
+	}
+	alloc, err = GetAllocationFromAuthTicket(authTicket)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return alloc, authTicket, walletJSON, nil
+}
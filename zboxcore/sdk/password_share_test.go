@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/zboxcore/client"
+	"github.com/0chain/gosdk/zboxcore/marker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordAuthTicket(t *testing.T) {
+	at, err := json.Marshal(&marker.AuthTicket{AllocationID: "alloc", ClientID: "link"})
+	require.NoError(t, err)
+	ticket := base64.StdEncoding.EncodeToString(at)
+	wallet := `{"client_id":"link","mnemonics":"words"}`
+
+	link, err := newPasswordAuthTicket("alloc", ticket, "bls0chain", wallet, "secret")
+	require.NoError(t, err)
+	require.NotContains(t, link, "words")
+
+	_, ok := decodePasswordAuthTicket(ticket)
+	require.False(t, ok)
+	p, ok := decodePasswordAuthTicket(link)
+	require.True(t, ok)
+	require.Equal(t, "alloc", p.AllocationID)
+
+	gotTicket, gotWallet, err := OpenPasswordAuthTicket(link, "secret")
+	require.NoError(t, err)
+	require.Equal(t, ticket, gotTicket)
+	require.Equal(t, wallet, gotWallet)
+
+	_, _, err = OpenPasswordAuthTicket(link, "wrong")
+	require.Error(t, err)
+	_, _, err = OpenPasswordAuthTicket(ticket, "secret")
+	require.Error(t, err)
+}
+
+func TestGetAllocationFromPasswordAuthTicket(t *testing.T) {
+	owner, linkWallet := newRotationWallet(t), newRotationWallet(t)
+	setupRotationChain(t, &rotationChain{txns: map[string]*transaction.Transaction{}}, owner)
+	ownerID := client.GetClientID()
+
+	at, err := json.Marshal(&marker.AuthTicket{AllocationID: "alloc", ClientID: "link"})
+	require.NoError(t, err)
+	ticket := base64.StdEncoding.EncodeToString(at)
+	link, err := newPasswordAuthTicket("alloc", ticket, "bls0chain", linkWallet, "secret")
+	require.NoError(t, err)
+
+	_, err = GetAllocationFromAuthTicket(link)
+	require.Equal(t, ErrPasswordRequired, err)
+	_, _, _, err = GetAllocationFromPasswordAuthTicket(link, "wrong")
+	require.Error(t, err)
+
+	// the wallet of the link is returned, the client is left unchanged
+	alloc, gotTicket, gotWallet, err := GetAllocationFromPasswordAuthTicket(link, "secret")
+	require.NoError(t, err)
+	require.Equal(t, "alloc", alloc.ID)
+	require.Equal(t, ticket, gotTicket)
+	require.Equal(t, linkWallet, gotWallet)
+	require.Equal(t, ownerID, client.GetClientID())
+}
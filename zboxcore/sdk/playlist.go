@@ -9,7 +9,6 @@ import (
 
 	"github.com/0chain/gosdk/core/encryption"
 	"github.com/0chain/gosdk/core/resty"
-	"github.com/0chain/gosdk/core/sys"
 	"github.com/0chain/gosdk/zboxcore/client"
 	"github.com/0chain/gosdk/zboxcore/fileref"
 	"github.com/0chain/gosdk/zboxcore/logger"
@@ -68,7 +67,7 @@ func getPlaylistFromBlobbers(ctx context.Context, alloc *Allocation, query strin
 		req.Header.Set("X-App-Client-Key", client.GetClientPublicKey())
 
 		hash := encryption.Hash(alloc.ID)
-		sign, err := sys.Sign(hash, client.GetClient().SignatureScheme, client.GetClientSysKeys())
+		sign, err := client.Sign(hash)
 		if err != nil {
 			return err
 		}
@@ -149,7 +148,7 @@ func getPlaylistFileFromBlobbers(ctx context.Context, alloc *Allocation, query s
 		req.Header.Set("X-App-Client-Key", client.GetClientPublicKey())
 
 		hash := encryption.Hash(alloc.ID)
-		sign, err := sys.Sign(hash, client.GetClient().SignatureScheme, client.GetClientSysKeys())
+		sign, err := client.Sign(hash)
 		if err != nil {
 			return err
 		}
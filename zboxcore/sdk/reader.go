@@ -229,5 +229,11 @@ func GetDStorageFileReader(alloc *Allocation, ref *ORef, sdo *StreamDownloadOpti
 		}
 	}
 
+	if sdo.ContentMode == DOWNLOAD_CONTENT_THUMB {
+		sd.initBlockCache(ref.LookupHash, ref.ActualThumbnailHash)
+	} else {
+		sd.initBlockCache(ref.LookupHash, ref.ValidationRoot)
+	}
+
 	return sd, err
 }
@@ -140,7 +140,7 @@ func (req *RenameRequest) renameBlobberObject(
 				return
 			}
 			l.Logger.Error(blobber.Baseurl, "Response: ", string(respBody))
-			err = errors.New("response_error", string(respBody))
+			err = newBlobberHTTPError(blobber, resp.StatusCode, string(respBody))
 			return
 		}()
 
@@ -188,26 +188,20 @@ func (req *RenameRequest) ProcessRename() error {
 	objectTreeRefs, blobberErrors := req.ProcessWithBlobbers()
 
 	if !req.consensus.isConsensusOk() {
-		err := zboxutil.MajorError(blobberErrors)
-		if err != nil {
-			return errors.New("rename_failed",
-				fmt.Sprintf("Rename failed. %s", err.Error()))
-		}
-
-		return errors.New("consensus_not_met",
-			fmt.Sprintf("Rename failed. Required consensus %d got %d",
-				req.consensus.consensusThresh, req.consensus.getConsensus()))
+		return newProcessError("rename_failed", "Rename failed.",
+			req.consensus.consensusThresh, req.consensus.getConsensus(),
+			req.blobbers, blobberErrors)
 	}
 
 	writeMarkerMutex, err := CreateWriteMarkerMutex(client.GetClient(), req.allocationObj)
 	if err != nil {
-		return fmt.Errorf("rename failed: %s", err.Error())
+		return fmt.Errorf("rename failed: %w", err)
 	}
 
 	err = writeMarkerMutex.Lock(req.ctx, &req.renameMask,
 		req.maskMU, req.blobbers, &req.consensus, 0, time.Minute, req.connectionID)
 	if err != nil {
-		return fmt.Errorf("rename failed: %s", err.Error())
+		return fmt.Errorf("rename failed: %w", err)
 	}
 	defer writeMarkerMutex.Unlock(req.ctx, req.renameMask, req.blobbers, time.Minute, req.connectionID) //nolint: errcheck
 
@@ -215,7 +209,7 @@ func (req *RenameRequest) ProcessRename() error {
 	status, err := req.allocationObj.CheckAllocStatus()
 	if err != nil {
 		logger.Logger.Error("Error checking allocation status: ", err)
-		return fmt.Errorf("rename failed: %s", err.Error())
+		return fmt.Errorf("rename failed: %w", err)
 	}
 
 	if status == Repair {
@@ -282,9 +276,10 @@ func (req *RenameRequest) ProcessRename() error {
 	}
 
 	if !req.consensus.isConsensusOk() {
-		return errors.New("consensus_not_met",
+		return newConsensusError(ConsensusNotMet,
 			fmt.Sprintf("Required consensus %d got %d. Error: %s",
-				req.consensus.consensusThresh, req.consensus.consensus, errMessages))
+				req.consensus.consensusThresh, req.consensus.consensus, errMessages),
+			req.consensus.consensusThresh, req.consensus.consensus, nil, nil)
 	}
 	return nil
 }
@@ -325,14 +320,9 @@ func (ro *RenameOperation) Process(allocObj *Allocation, connectionID string) ([
 	objectTreeRefs, blobberErrors := rR.ProcessWithBlobbers()
 
 	if !rR.consensus.isConsensusOk() {
-		err := zboxutil.MajorError(blobberErrors)
-		if err != nil {
-			return nil, rR.renameMask, errors.New("rename_failed", fmt.Sprintf("Renamed failed. %s", err.Error()))
-		}
-
-		return nil, rR.renameMask, errors.New("consensus_not_met",
-			fmt.Sprintf("Rename failed. Required consensus %d, got %d",
-				rR.consensus.consensusThresh, rR.consensus.consensus))
+		return nil, rR.renameMask, newProcessError("rename_failed", "Rename failed.",
+			rR.consensus.consensusThresh, rR.consensus.consensus,
+			rR.blobbers, blobberErrors)
 	}
 	l.Logger.Info("Rename Processs Ended ")
 	return objectTreeRefs, rR.renameMask, nil
@@ -0,0 +1,361 @@
+package sdk
+
+import (
+	"encoding/json"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/common"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/0chain/gosdk/zboxcore/client"
+	"github.com/0chain/gosdk/zboxcore/fileref"
+)
+
+// Kinds of the items of a key rotation.
+const (
+	RotationAllocation = "allocation"
+	RotationWritePool  = "write_pool"
+	RotationReadPool   = "read_pool"
+	RotationStakePool  = "stake_pool"
+	RotationVesting    = "vesting_pool"
+	RotationBalance    = "balance"
+	RotationAuthTicket = "auth_ticket"
+)
+
+// Status of the items of a key rotation.
+const (
+	RotationPlanned     = "planned"
+	RotationTransferred = "transferred"
+	// RotationUnlocked is the status of tokens unlocked but not locked again,
+	// like vesting pools, which can't be re-created by the new client; the
+	// tokens are in the balance sent to the new client.
+	RotationUnlocked = "unlocked"
+	RotationRelocked = "relocked"
+	RotationReissued = "reissued"
+	// RotationPending is the status of tokens that can't be unlocked yet, like
+	// stake pools with open offers; unlock them later with the old key.
+	RotationPending = "pending"
+	RotationFailed  = "failed"
+)
+
+// RotationItem is something owned by the old client, and what the rotation did
+// with it.
+type RotationItem struct {
+	Kind         string       `json:"kind"`
+	ID           string       `json:"id"`
+	ProviderType ProviderType `json:"provider_type,omitempty"`
+	// Amount is the tokens of the item, the amount unlocked once it is.
+	Amount common.Balance `json:"amount"`
+	Status string         `json:"status"`
+	// Hash is the transaction of the last step applied to the item.
+	Hash       string `json:"hash,omitempty"`
+	AuthTicket string `json:"auth_ticket,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// RotationReport lists the items of a key rotation.
+type RotationReport struct {
+	OldClientID string          `json:"old_client_id"`
+	NewClientID string          `json:"new_client_id"`
+	Items       []*RotationItem `json:"items"`
+}
+
+// Failed returns the items the rotation failed to move, and the ones unlocked
+// but not locked again.
+func (r *RotationReport) Failed() []*RotationItem {
+	var items []*RotationItem
+	for _, item := range r.Items {
+		if item.Status == RotationFailed || item.Status == RotationPending || item.Status == RotationUnlocked {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Add adds an item to the report, with the planned status.
+func (r *RotationReport) Add(kind, id string, amount common.Balance) *RotationItem {
+	item := &RotationItem{Kind: kind, ID: id, Amount: amount, Status: RotationPlanned}
+	r.Items = append(r.Items, item)
+	return item
+}
+
+// Fail marks the item as failed with err.
+func (item *RotationItem) Fail(err error) {
+	item.Status = RotationFailed
+	item.Error = err.Error()
+}
+
+// RotationShare describes an auth ticket to reissue with the new key. Auth
+// tickets are signed by the owner of the allocation, so the ones of the old
+// client stop working once the allocation is transferred.
+type RotationShare struct {
+	AllocationID    string `json:"allocation_id"`
+	Path            string `json:"path"`
+	FileName        string `json:"file_name"`
+	RefType         string `json:"ref_type"`
+	RefereeClientID string `json:"referee_client_id,omitempty"`
+	// RefereeEncryptionPublicKey is set for private shares of encrypted files.
+	// They can't be reissued: the files stay encrypted with the old key.
+	RefereeEncryptionPublicKey string `json:"referee_encryption_public_key,omitempty"`
+	Expiration                 int64  `json:"expiration,omitempty"`
+}
+
+// KeyRotation moves everything the client of the sdk owns to a new wallet:
+// the allocations with their write pools are transferred, the read pool and
+// the stake pools are unlocked with the old key and locked again with the new
+// one, and the auth tickets are reissued.
+//
+//	r, err := NewKeyRotation(newWalletJSON, shares)
+//	err = r.Release()  // signed by the old key
+//	// send the balance of the old client to the new one
+//	err = r.Relock()   // switches the sdk to the new wallet
+//	report := r.Report
+//
+// The tokens have to reach the new client between Release and Relock for the
+// pools to be locked again, see zcncore.RotateWalletKeys.
+type KeyRotation struct {
+	Report *RotationReport
+
+	newWallet string
+	scheme    string
+	newKey    string
+	shares    []RotationShare
+}
+
+// NewKeyRotation plans the rotation of the client of the sdk to the wallet
+// newWalletJSON, listing what the client owns in Report.
+func NewKeyRotation(newWalletJSON string, shares []RotationShare) (*KeyRotation, error) {
+	if !sdkInitialized {
+		return nil, sdkNotInitialized
+	}
+	w := &zcncrypto.Wallet{}
+	if err := json.Unmarshal([]byte(newWalletJSON), w); err != nil {
+		return nil, errors.Wrap(err, "key_rotation: invalid wallet")
+	}
+	if w.ClientID == "" || w.ClientKey == "" {
+		return nil, errors.New("key_rotation", "the new wallet has no client id or key")
+	}
+	oldClientID := client.GetClientID()
+	if w.ClientID == oldClientID {
+		return nil, errors.New("key_rotation", "the new wallet is the current one")
+	}
+
+	r := &KeyRotation{
+		Report: &RotationReport{
+			OldClientID: oldClientID,
+			NewClientID: w.ClientID,
+		},
+		newWallet: newWalletJSON,
+		scheme:    client.GetClient().SignatureScheme,
+		newKey:    w.ClientKey,
+		shares:    shares,
+	}
+	if err := r.plan(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *KeyRotation) plan() error {
+	clientID := r.Report.OldClientID
+	allocs, err := GetAllocationsForClient(clientID)
+	if err != nil {
+		return errors.Wrap(err, "key_rotation: failed to list the allocations")
+	}
+	for _, alloc := range allocs {
+		r.Report.Add(RotationAllocation, alloc.ID, 0)
+		if alloc.WritePool > 0 {
+			r.Report.Add(RotationWritePool, alloc.ID, alloc.WritePool)
+		}
+	}
+
+	rp, err := GetReadPoolInfo(clientID)
+	if err != nil {
+		return errors.Wrap(err, "key_rotation: failed to get the read pool")
+	}
+	if rp.Balance > 0 {
+		r.Report.Add(RotationReadPool, clientID, rp.Balance)
+	}
+
+	const limit = 20
+	for offset := 0; ; offset += limit {
+		info, err := GetStakePoolUserInfo(clientID, offset, limit)
+		if err != nil {
+			return errors.Wrap(err, "key_rotation: failed to list the stake pools")
+		}
+		count := 0
+		for providerID, pools := range info.Pools {
+			for _, pool := range pools {
+				count++
+				item := r.Report.Add(RotationStakePool, string(providerID), pool.Balance)
+				item.ProviderType = pool.ProviderType
+				if item.ProviderType == 0 {
+					item.ProviderType = stakePoolProviderType(string(providerID))
+				}
+			}
+		}
+		if count < limit {
+			break
+		}
+	}
+
+	for _, share := range r.shares {
+		r.Report.Add(RotationAuthTicket, share.AllocationID+":"+share.Path, 0)
+	}
+	return nil
+}
+
+// stakePoolProviderType finds whether the stake pool of providerID is the one
+// of a blobber or of a validator, the only ones the storage sdk stakes on.
+func stakePoolProviderType(providerID string) ProviderType {
+	if _, err := GetStakePoolInfo(ProviderBlobber, providerID); err == nil {
+		return ProviderBlobber
+	}
+	return ProviderValidator
+}
+
+// Release applies the steps signed by the old key: it transfers the
+// allocations and unlocks the read pool and the stake pools.
+func (r *KeyRotation) Release() error {
+	if !sdkInitialized {
+		return sdkNotInitialized
+	}
+	if client.GetClientID() != r.Report.OldClientID {
+		return errors.New("key_rotation", "the sdk doesn't use the old wallet")
+	}
+
+	for _, item := range r.Report.Items {
+		switch item.Kind {
+		case RotationAllocation:
+			hash, _, err := TransferAllocation(item.ID, r.Report.NewClientID, r.newKey)
+			item.Hash = hash
+			if err != nil {
+				item.Fail(err)
+				continue
+			}
+			item.Status = RotationTransferred
+			r.markWritePool(item.ID)
+		case RotationReadPool:
+			hash, _, err := ReadPoolUnlock(client.TxnFee())
+			item.Hash = hash
+			if err != nil {
+				item.Fail(err)
+				continue
+			}
+			item.Status = RotationUnlocked
+		case RotationStakePool:
+			unstake, _, err := StakePoolUnlock(item.ProviderType, item.ID, client.TxnFee())
+			if err != nil {
+				item.Fail(err)
+				continue
+			}
+			if unstake <= 0 {
+				item.Status = RotationPending
+				continue
+			}
+			item.Amount = common.Balance(unstake)
+			item.Status = RotationUnlocked
+		}
+	}
+	return nil
+}
+
+// markWritePool marks the write pool of the allocation as transferred with it.
+func (r *KeyRotation) markWritePool(allocationID string) {
+	for _, item := range r.Report.Items {
+		if item.Kind == RotationWritePool && item.ID == allocationID {
+			item.Status = RotationTransferred
+		}
+	}
+}
+
+// Relock switches the sdk to the new wallet, locks again the tokens unlocked by
+// Release, and reissues the auth tickets.
+func (r *KeyRotation) Relock() error {
+	if !sdkInitialized {
+		return sdkNotInitialized
+	}
+	if client.GetClientID() != r.Report.NewClientID {
+		// a signer set with client.SetSigner signs for the old key
+		client.SetSigner(nil)
+		if err := client.PopulateClient(r.newWallet, r.scheme); err != nil {
+			return errors.Wrap(err, "key_rotation: failed to switch to the new wallet")
+		}
+	}
+
+	for _, item := range r.Report.Items {
+		if item.Status != RotationUnlocked {
+			continue
+		}
+		switch item.Kind {
+		case RotationReadPool:
+			hash, _, err := ReadPoolLock(uint64(item.Amount), client.TxnFee())
+			item.Hash = hash
+			if err != nil {
+				item.Fail(err)
+				continue
+			}
+			item.Status = RotationRelocked
+		case RotationStakePool:
+			hash, _, err := StakePoolLock(item.ProviderType, item.ID, uint64(item.Amount), client.TxnFee())
+			item.Hash = hash
+			if err != nil {
+				item.Fail(err)
+				continue
+			}
+			item.Status = RotationRelocked
+		default:
+			item.Error = "the " + item.Kind + " can't be locked again by the new client"
+		}
+	}
+
+	r.reissueShares()
+	return nil
+}
+
+func (r *KeyRotation) reissueShares() {
+	allocs := make(map[string]*Allocation)
+	for _, share := range r.shares {
+		item := r.findShare(share)
+		if share.RefereeEncryptionPublicKey != "" {
+			item.Fail(errors.New("key_rotation", "encrypted files stay shared with the old key"))
+			continue
+		}
+
+		alloc, ok := allocs[share.AllocationID]
+		if !ok {
+			var err error
+			if alloc, err = GetAllocation(share.AllocationID); err != nil {
+				item.Fail(err)
+				continue
+			}
+			allocs[share.AllocationID] = alloc
+		}
+		if alloc.Owner != r.Report.NewClientID {
+			item.Fail(errors.New("key_rotation", "the allocation wasn't transferred"))
+			continue
+		}
+
+		refType := share.RefType
+		if refType == "" {
+			refType = fileref.FILE
+		}
+		at, err := alloc.GetAuthTicket(share.Path, share.FileName, refType,
+			share.RefereeClientID, "", share.Expiration, nil)
+		if err != nil {
+			item.Fail(err)
+			continue
+		}
+		item.AuthTicket = at
+		item.Status = RotationReissued
+	}
+}
+
+func (r *KeyRotation) findShare(share RotationShare) *RotationItem {
+	id := share.AllocationID + ":" + share.Path
+	for _, item := range r.Report.Items {
+		if item.Kind == RotationAuthTicket && item.ID == id && item.Status == RotationPlanned {
+			return item
+		}
+	}
+	return r.Report.Add(RotationAuthTicket, id, 0)
+}
@@ -0,0 +1,209 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/common"
+	"github.com/0chain/gosdk/core/conf"
+	"github.com/0chain/gosdk/core/sys"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/0chain/gosdk/zboxcore/blockchain"
+	"github.com/0chain/gosdk/zboxcore/client"
+	"github.com/0chain/gosdk/zboxcore/zboxutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotationReport(t *testing.T) {
+	report := &RotationReport{}
+	alloc := report.Add(RotationAllocation, "alloc", 0)
+	pool := report.Add(RotationStakePool, "blobber", 10)
+	ticket := report.Add(RotationAuthTicket, "alloc:/file", 0)
+	require.Equal(t, RotationPlanned, alloc.Status)
+	require.Len(t, report.Items, 3)
+
+	alloc.Status = RotationTransferred
+	pool.Status = RotationPending
+	ticket.Fail(errors.New("key_rotation", "failed"))
+	require.Equal(t, "key_rotation: failed", ticket.Error)
+	require.Equal(t, []*RotationItem{pool, ticket}, report.Failed())
+}
+
+func TestNewKeyRotation(t *testing.T) {
+	initialized := sdkInitialized
+	defer func() { sdkInitialized = initialized }()
+
+	sdkInitialized = false
+	_, err := NewKeyRotation(`{"client_id":"new","client_key":"key"}`, nil)
+	require.Equal(t, sdkNotInitialized, err)
+
+	sdkInitialized = true
+	_, err = NewKeyRotation(`{}`, nil)
+	require.Error(t, err)
+}
+
+// rotationChain is a miner and sharder confirming the transactions sent to it,
+// failing the ones named in fail.
+type rotationChain struct {
+	mu    sync.Mutex
+	owner string
+	fail  map[string]bool
+	txns  map[string]*transaction.Transaction
+	sent  []string
+}
+
+func (c *rotationChain) serve(t *testing.T) http.HandlerFunc {
+	sc := "/" + zboxutil.SC_REST_API_URL + STORAGE_SCADDRESS
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		var resp interface{}
+		switch r.URL.Path {
+		case sc + "/allocations":
+			resp = []*Allocation{{ID: "alloc", Owner: c.owner, WritePool: 5}}
+		case sc + "/allocation":
+			resp = &Allocation{ID: "alloc", Owner: c.owner}
+		case sc + "/getReadPoolStat":
+			resp = &ReadPool{Balance: 7}
+		case sc + "/getUserStakePoolStat":
+			resp = &StakePoolUserInfo{Pools: map[common.Key][]*StakePoolDelegatePoolInfo{
+				"blobber":   {{Balance: 10, ProviderType: ProviderBlobber}},
+				"validator": {{Balance: 3, ProviderType: ProviderValidator}},
+			}}
+		case "/" + transaction.TXN_SUBMIT_URL:
+			txn := &transaction.Transaction{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(txn))
+			var sn transaction.SmartContractTxnData
+			require.NoError(t, json.Unmarshal([]byte(txn.TransactionData), &sn))
+			input, _ := json.Marshal(sn.InputArgs)
+			c.sent = append(c.sent, fmt.Sprint(txn.ClientID, " ", sn.Name, " ", string(input), " ", txn.Value))
+
+			txn.Status = transaction.TxnSuccess
+			switch {
+			case c.fail[sn.Name]:
+				txn.Status, txn.TransactionOutput = transaction.TxnFail, "failed"
+			case sn.Name == transaction.STORAGESC_STAKE_POOL_UNLOCK:
+				var req stakePoolRequest
+				require.NoError(t, json.Unmarshal(input, &req))
+				// the validator stake has open offers
+				amount := int64(0)
+				if req.ProviderID == "blobber" {
+					amount = 10
+				}
+				out, _ := json.Marshal(&stakePoolLock{Amount: amount})
+				txn.TransactionOutput = string(out)
+			case sn.Name == transaction.STORAGESC_UPDATE_ALLOCATION:
+				c.owner = sn.InputArgs.(map[string]interface{})["owner_id"].(string)
+			}
+			c.txns[txn.Hash] = txn
+			resp = txn
+		case "/" + strings.TrimSuffix(transaction.TXN_VERIFY_URL, "?hash="):
+			resp = map[string]interface{}{"txn": c.txns[r.URL.Query().Get("hash")]}
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}
+}
+
+func newRotationWallet(t *testing.T) string {
+	w, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	s, err := w.Marshal()
+	require.NoError(t, err)
+	return s
+}
+
+// setupRotationChain initializes the sdk with oldWallet on a network of chain
+// until the end of the test.
+func setupRotationChain(t *testing.T, chain *rotationChain, oldWallet string) {
+	server := httptest.NewServer(chain.serve(t))
+	previous, err := json.Marshal(client.GetClient())
+	require.NoError(t, err)
+	miners, sharders := blockchain.GetMiners(), blockchain.GetSharders()
+	sleep, initialized := sys.Sleep, sdkInitialized
+	t.Cleanup(func() {
+		server.Close()
+		require.NoError(t, client.PopulateClient(string(previous), client.GetClient().SignatureScheme))
+		blockchain.SetMiners(miners)
+		blockchain.SetSharders(sharders)
+		sys.Sleep, sdkInitialized = sleep, initialized
+	})
+
+	conf.InitClientConfig(&conf.Config{MinConfirmation: 50})
+	blockchain.SetMiners([]string{server.URL})
+	blockchain.SetSharders([]string{server.URL})
+	transaction.InitCache([]string{server.URL})
+	sys.Sleep = func(time.Duration) {}
+	sdkInitialized = true
+	client.SetSigner(nil)
+	require.NoError(t, client.PopulateClient(oldWallet, "bls0chain"))
+	client.SetTxnFee(1)
+	transaction.Cache.Set(client.GetClientID(), 0)
+}
+
+func TestKeyRotationReleaseRelock(t *testing.T) {
+	oldWallet, newWallet := newRotationWallet(t), newRotationWallet(t)
+	chain := &rotationChain{fail: map[string]bool{}, txns: map[string]*transaction.Transaction{}}
+	setupRotationChain(t, chain, oldWallet)
+	oldID := client.GetClientID()
+	chain.owner = oldID
+
+	r, err := NewKeyRotation(newWallet, nil)
+	require.NoError(t, err)
+	newID := r.Report.NewClientID
+	transaction.Cache.Set(newID, 0)
+	// a pool the new client can't lock again
+	vesting := r.Report.Add(RotationVesting, "vesting", 4)
+
+	require.NoError(t, r.Release())
+	statuses := func() map[string]string {
+		m := make(map[string]string)
+		for _, item := range r.Report.Items {
+			m[item.Kind+" "+item.ID] = item.Status
+		}
+		return m
+	}
+	require.Equal(t, map[string]string{
+		"allocation alloc":     RotationTransferred,
+		"write_pool alloc":     RotationTransferred,
+		"read_pool " + oldID:   RotationUnlocked,
+		"stake_pool blobber":   RotationUnlocked,
+		"stake_pool validator": RotationPending,
+		"vesting_pool vesting": RotationPlanned,
+	}, statuses())
+	require.Equal(t, newID, chain.owner)
+
+	vesting.Status = RotationUnlocked
+	chain.fail[transaction.STORAGESC_STAKE_POOL_LOCK] = true
+	chain.sent = nil
+	require.NoError(t, r.Relock())
+	require.Equal(t, newID, client.GetClientID())
+	require.Equal(t, []string{
+		newID + " " + transaction.STORAGESC_READ_POOL_LOCK + " null 7",
+		newID + " " + transaction.STORAGESC_STAKE_POOL_LOCK + ` {"provider_id":"blobber","provider_type":3} 10`,
+	}, chain.sent)
+
+	require.Equal(t, RotationRelocked, statuses()["read_pool "+oldID])
+	failed := make(map[string]string)
+	for _, item := range r.Report.Failed() {
+		failed[item.Kind+" "+item.ID] = item.Status
+	}
+	require.Equal(t, map[string]string{
+		"stake_pool blobber":   RotationFailed,
+		"stake_pool validator": RotationPending,
+		"vesting_pool vesting": RotationUnlocked,
+	}, failed)
+	require.NotEmpty(t, vesting.Error)
+}
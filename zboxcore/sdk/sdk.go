@@ -15,6 +15,7 @@ import (
 	"github.com/0chain/common/core/currency"
 	"github.com/0chain/errors"
 	"github.com/0chain/gosdk/core/conf"
+	"github.com/0chain/gosdk/core/keystore"
 	"github.com/0chain/gosdk/core/logger"
 	"github.com/0chain/gosdk/core/sys"
 	"go.uber.org/zap"
@@ -110,6 +111,30 @@ func InitStorageSDK(walletJSON string,
 	return nil
 }
 
+// InitStorageSDKFromKeystore initializes the storage sdk like InitStorageSDK,
+// with the wallet stored under name in the keystore directory, decrypted with
+// password. The decrypted wallet is kept by the sdk for the process lifetime,
+// whatever the timeout of Keystore.Unlock.
+func InitStorageSDKFromKeystore(keystoreDir, name, password string,
+	blockWorker, chainID, signatureScheme string,
+	preferredBlobbers []string,
+	nonce int64,
+	fee ...uint64) error {
+	ks, err := keystore.New(keystoreDir, nil)
+	if err != nil {
+		return err
+	}
+	w, err := ks.Get(name, password)
+	if err != nil {
+		return err
+	}
+	walletJSON, err := w.Marshal()
+	if err != nil {
+		return err
+	}
+	return InitStorageSDK(walletJSON, blockWorker, chainID, signatureScheme, preferredBlobbers, nonce, fee...)
+}
+
 func GetNetwork() *Network {
 	return &Network{
 		Miners:   blockchain.GetMiners(),
@@ -255,11 +280,12 @@ type StakePoolRewardsInfo struct {
 
 // StakePoolDelegatePoolInfo represents delegate pool of a stake pool info.
 type StakePoolDelegatePoolInfo struct {
-	ID         common.Key     `json:"id"`          // blobber ID
-	Balance    common.Balance `json:"balance"`     // current balance
-	DelegateID common.Key     `json:"delegate_id"` // wallet
-	Rewards    common.Balance `json:"rewards"`     // current
-	UnStake    bool           `json:"unstake"`     // want to unstake
+	ID           common.Key     `json:"id"`                      // blobber ID
+	ProviderType ProviderType   `json:"provider_type,omitempty"` // blobber or validator
+	Balance      common.Balance `json:"balance"`                 // current balance
+	DelegateID   common.Key     `json:"delegate_id"`             // wallet
+	Rewards      common.Balance `json:"rewards"`                 // current
+	UnStake      bool           `json:"unstake"`                 // want to unstake
 
 	TotalReward  common.Balance   `json:"total_reward"`
 	TotalPenalty common.Balance   `json:"total_penalty"`
@@ -822,6 +848,9 @@ func GetAllocationFromAuthTicket(authTicket string) (*Allocation, error) {
 	if err != nil {
 		return nil, errors.New("auth_ticket_decode_error", "Error unmarshaling the auth ticket."+err.Error())
 	}
+	if _, ok := decodePasswordAuthTicket(authTicket); ok {
+		return nil, ErrPasswordRequired
+	}
 	return GetAllocation(at.AllocationID)
 }
 
@@ -967,11 +996,24 @@ type CreateAllocationOptions struct {
 	BlobberIds           []string
 	ThirdPartyExtendable bool
 	FileOptionsParams    *FileOptionsParameters
+	// BlobberSelector, if set, picks the blobbers of the allocation missing
+	// from BlobberIds. They are preferred over the ones returned by the chain.
+	BlobberSelector BlobberSelector
 }
 
 func CreateAllocationWith(options CreateAllocationOptions) (
 	string, int64, *transaction.Transaction, error) {
 
+	if options.BlobberSelector != nil {
+		ids, err := selectAllocationBlobbers(context.Background(), options.BlobberSelector,
+			options.DataShards, options.ParityShards, options.Size,
+			options.ReadPrice, options.WritePrice, options.BlobberIds)
+		if err != nil {
+			return "", 0, nil, errors.New("failed_select_allocation_blobbers", "failed to select blobbers for allocation: "+err.Error())
+		}
+		options.BlobberIds = ids
+	}
+
 	return CreateAllocationForOwner(client.GetClientID(),
 		client.GetClientPublicKey(), options.DataShards, options.ParityShards,
 		options.Size, options.ReadPrice, options.WritePrice, options.Lock,
@@ -1435,10 +1477,13 @@ func smartContractTxnValueFee(sn transaction.SmartContractTxnData,
 	}
 
 	if txn.TransactionNonce == 0 {
-		txn.TransactionNonce = transaction.Cache.GetNextNonce(txn.ClientID)
+		if txn.TransactionNonce, err = transaction.Cache.ReserveNextNonce(txn.ClientID); err != nil {
+			return
+		}
 	}
 
 	if err = txn.ComputeHashAndSign(client.Sign); err != nil {
+		transaction.Cache.Release(txn.ClientID, txn.TransactionNonce)
 		return
 	}
 
@@ -1475,6 +1520,8 @@ func smartContractTxnValueFee(sn transaction.SmartContractTxnData,
 			"Failed to get the transaction confirmation")
 	}
 
+	transaction.Cache.Confirm(txn.ClientID, txn.TransactionNonce)
+
 	if t.Status == transaction.TxnFail {
 		return t.Hash, t.TransactionOutput, 0, t, errors.New("", t.TransactionOutput)
 	}
@@ -1555,14 +1602,20 @@ func GetAllocationMinLock(
 	size int64,
 	readPrice, writePrice PriceRange,
 ) (int64, error) {
-	baSize := int64(math.Ceil(float64(size) / float64(datashards)))
-	totalSize := baSize * int64(datashards+parityshards)
+	timeunit, err := getStorageTimeUnit()
+	if err != nil {
+		return 0, err
+	}
+	return allocationMinLock(datashards, parityshards, size, readPrice, writePrice, timeunit)
+}
+
+// getStorageTimeUnit reads the time unit from the storage SC config.
+func getStorageTimeUnit() (time.Duration, error) {
 	config, err := GetStorageSCConfig()
 	if err != nil {
 		return 0, err
 	}
-	t := config.Fields["time_unit"]
-	timeunitStr, ok := t.(string)
+	timeunitStr, ok := config.Fields["time_unit"].(string)
 	if !ok {
 		return 0, fmt.Errorf("bad time_unit type")
 	}
@@ -1570,6 +1623,17 @@ func GetAllocationMinLock(
 	if err != nil {
 		return 0, fmt.Errorf("bad time_unit format")
 	}
+	return timeunit, nil
+}
+
+func allocationMinLock(
+	datashards, parityshards int,
+	size int64,
+	readPrice, writePrice PriceRange,
+	timeunit time.Duration,
+) (int64, error) {
+	baSize := int64(math.Ceil(float64(size) / float64(datashards)))
+	totalSize := baSize * int64(datashards+parityshards)
 
 	expiry := common.Timestamp(time.Now().Add(timeunit).Unix())
 	duration := expiry / common.Timestamp(timeunit.Milliseconds())
@@ -0,0 +1,412 @@
+package sdk
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/common"
+	"github.com/0chain/gosdk/zboxcore/encryption"
+	"github.com/0chain/gosdk/zboxcore/fileref"
+	"github.com/0chain/gosdk/zboxcore/zboxutil"
+)
+
+// ShareGroupsPath is the hidden file of an allocation the
+// AllocationShareStore keeps the share groups in, encrypted.
+const ShareGroupsPath = "/.share_groups.json"
+
+// ShareGroupMember is a member of a ShareGroup.
+type ShareGroupMember struct {
+	ClientID            string `json:"client_id"`
+	EncryptionPublicKey string `json:"encryption_public_key"`
+}
+
+// GroupTicket is an auth ticket issued to a member of a ShareGroup.
+type GroupTicket struct {
+	Path       string           `json:"path"`
+	ClientID   string           `json:"client_id"`
+	AuthTicket string           `json:"auth_ticket"`
+	IssuedAt   common.Timestamp `json:"issued_at"`
+}
+
+// ShareGroup is a named group of clients the owner of an allocation shares
+// paths with. Every member gets a private auth ticket, with the PRE re-key to
+// its encryption key, for every path of the group. The ticket of a directory
+// lets the blobbers re-encrypt any file under it, so the files added later are
+// shared without new tickets.
+type ShareGroup struct {
+	Name    string              `json:"name"`
+	Members []*ShareGroupMember `json:"members"`
+	Paths   []string            `json:"paths"`
+	Tickets []*GroupTicket      `json:"tickets"`
+}
+
+// Member returns the member clientID, nil if it isn't one.
+func (g *ShareGroup) Member(clientID string) *ShareGroupMember {
+	for _, m := range g.Members {
+		if m.ClientID == clientID {
+			return m
+		}
+	}
+	return nil
+}
+
+// MemberTickets returns the tickets of the member clientID, to give to it.
+func (g *ShareGroup) MemberTickets(clientID string) []*GroupTicket {
+	var tickets []*GroupTicket
+	for _, t := range g.Tickets {
+		if t.ClientID == clientID {
+			tickets = append(tickets, t)
+		}
+	}
+	return tickets
+}
+
+func (g *ShareGroup) ticket(path, clientID string) *GroupTicket {
+	for _, t := range g.Tickets {
+		if t.Path == path && t.ClientID == clientID {
+			return t
+		}
+	}
+	return nil
+}
+
+func (g *ShareGroup) hasPath(path string) bool {
+	for _, p := range g.Paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// ShareGroupStore persists the share groups of the allocations.
+// FileShareStore and AllocationShareStore implement it.
+type ShareGroupStore interface {
+	// LoadGroups returns the share groups of a.
+	LoadGroups(a *Allocation) ([]*ShareGroup, error)
+	// SaveGroups replaces the share groups of a.
+	SaveGroups(a *Allocation, groups []*ShareGroup) error
+}
+
+// LoadGroups implements ShareGroupStore.
+func (s *FileShareStore) LoadGroups(a *Allocation) ([]*ShareGroup, error) {
+	var groups []*ShareGroup
+	err := readLocalJSON(s.file(a, ".groups"), &groups)
+	return groups, err
+}
+
+// SaveGroups implements ShareGroupStore.
+func (s *FileShareStore) SaveGroups(a *Allocation, groups []*ShareGroup) error {
+	return writeLocalJSON(s.file(a, ".groups"), groups)
+}
+
+// LoadGroups implements ShareGroupStore.
+func (s *AllocationShareStore) LoadGroups(a *Allocation) ([]*ShareGroup, error) {
+	var groups []*ShareGroup
+	err := readAllocationJSON(a, ShareGroupsPath, &groups)
+	return groups, err
+}
+
+// SaveGroups implements ShareGroupStore.
+func (s *AllocationShareStore) SaveGroups(a *Allocation, groups []*ShareGroup) error {
+	return writeAllocationJSON(a, ShareGroupsPath, s.Workdir, groups)
+}
+
+var (
+	groupMu         sync.Mutex
+	shareGroupStore ShareGroupStore
+	// groupLocks serializes the updates of the share groups of each
+	// allocation, guarded by groupMu.
+	groupLocks = make(map[string]*sync.Mutex)
+)
+
+// SetShareGroupStore sets the store of the share groups manifest, nil
+// disables the share groups.
+func SetShareGroupStore(s ShareGroupStore) {
+	groupMu.Lock()
+	shareGroupStore = s
+	groupMu.Unlock()
+}
+
+// GetShareGroupStore returns the store of the share groups, nil if they are
+// disabled.
+func GetShareGroupStore() ShareGroupStore {
+	groupMu.Lock()
+	defer groupMu.Unlock()
+	return shareGroupStore
+}
+
+// lockGroups locks the share groups of the allocation id, it returns the
+// function unlocking it.
+func lockGroups(id string) func() {
+	groupMu.Lock()
+	mu, ok := groupLocks[id]
+	if !ok {
+		mu = &sync.Mutex{}
+		groupLocks[id] = mu
+	}
+	groupMu.Unlock()
+
+	mu.Lock()
+	return mu.Unlock
+}
+
+// ShareGroupSync is what a change of a share group issued and revoked.
+type ShareGroupSync struct {
+	Issued  []*GroupTicket `json:"issued"`
+	Revoked []*GroupTicket `json:"revoked"`
+	// Errors are the tickets that couldn't be issued or revoked, done again by
+	// the next SyncShareGroup.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// updateGroups runs update on the share groups of a, under a lock, and saves
+// them.
+func (a *Allocation) updateGroups(update func(groups []*ShareGroup) ([]*ShareGroup, error)) error {
+	store := GetShareGroupStore()
+	if store == nil {
+		return errors.New("share_groups_disabled", "no share group store is set")
+	}
+	defer lockGroups(a.ID)()
+
+	groups, err := store.LoadGroups(a)
+	if err != nil {
+		return err
+	}
+	groups, err = update(groups)
+	if err != nil {
+		return err
+	}
+	return store.SaveGroups(a, groups)
+}
+
+// updateGroup is updateGroups for the group name, synced after update.
+func (a *Allocation) updateGroup(name string, update func(g *ShareGroup) error) (*ShareGroupSync, error) {
+	var sync *ShareGroupSync
+	err := a.updateGroups(func(groups []*ShareGroup) ([]*ShareGroup, error) {
+		g := findGroup(groups, name)
+		if g == nil {
+			return nil, errors.New("share_group_not_found", "no share group "+name)
+		}
+		if update != nil {
+			if err := update(g); err != nil {
+				return nil, err
+			}
+		}
+		sync = a.syncGroup(groups, g)
+		return groups, nil
+	})
+	return sync, err
+}
+
+func findGroup(groups []*ShareGroup, name string) *ShareGroup {
+	for _, g := range groups {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// ListShareGroups returns the share groups of the allocation.
+func (a *Allocation) ListShareGroups() ([]*ShareGroup, error) {
+	store := GetShareGroupStore()
+	if store == nil {
+		return nil, errors.New("share_groups_disabled", "no share group store is set")
+	}
+	defer lockGroups(a.ID)()
+	return store.LoadGroups(a)
+}
+
+// CreateShareGroup creates the empty share group name.
+func (a *Allocation) CreateShareGroup(name string) error {
+	if name == "" {
+		return errors.New("invalid_share_group", "name is required")
+	}
+	return a.updateGroups(func(groups []*ShareGroup) ([]*ShareGroup, error) {
+		if findGroup(groups, name) != nil {
+			return nil, errors.New("share_group_exists", "share group "+name+" already exists")
+		}
+		return append(groups, &ShareGroup{Name: name}), nil
+	})
+}
+
+// DeleteShareGroup revokes the tickets of the group name and deletes it.
+func (a *Allocation) DeleteShareGroup(name string) (*ShareGroupSync, error) {
+	var sync *ShareGroupSync
+	err := a.updateGroups(func(groups []*ShareGroup) ([]*ShareGroup, error) {
+		g := findGroup(groups, name)
+		if g == nil {
+			return nil, errors.New("share_group_not_found", "no share group "+name)
+		}
+		g.Members, g.Paths = nil, nil
+		sync = a.syncGroup(groups, g)
+		if len(g.Tickets) > 0 {
+			// kept to be revoked by the next sync
+			return groups, nil
+		}
+		kept := groups[:0]
+		for _, o := range groups {
+			if o != g {
+				kept = append(kept, o)
+			}
+		}
+		return kept, nil
+	})
+	return sync, err
+}
+
+// AddShareGroupMember adds the client to the group name and issues its tickets
+// for the paths of the group.
+func (a *Allocation) AddShareGroupMember(name, clientID, encryptionPublicKey string) (*ShareGroupSync, error) {
+	if clientID == "" {
+		return nil, errors.New("invalid_share_group_member", "client id is required")
+	}
+	if _, err := encryption.UnmarshallPublicKey(encryptionPublicKey); err != nil {
+		return nil, errors.Wrap(err, "invalid_share_group_member: invalid encryption public key")
+	}
+	return a.updateGroup(name, func(g *ShareGroup) error {
+		if m := g.Member(clientID); m != nil {
+			if m.EncryptionPublicKey != encryptionPublicKey {
+				// the tickets of the old key are reissued
+				m.EncryptionPublicKey = encryptionPublicKey
+				g.Tickets = removeTickets(g.Tickets, "", clientID)
+			}
+			return nil
+		}
+		g.Members = append(g.Members, &ShareGroupMember{ClientID: clientID, EncryptionPublicKey: encryptionPublicKey})
+		return nil
+	})
+}
+
+// RemoveShareGroupMember removes the client from the group name and revokes
+// its tickets.
+func (a *Allocation) RemoveShareGroupMember(name, clientID string) (*ShareGroupSync, error) {
+	return a.updateGroup(name, func(g *ShareGroup) error {
+		members := g.Members[:0]
+		for _, m := range g.Members {
+			if m.ClientID != clientID {
+				members = append(members, m)
+			}
+		}
+		g.Members = members
+		return nil
+	})
+}
+
+// ShareWithGroup shares path, a directory or an encrypted file, with the
+// members of the group name, present and future.
+func (a *Allocation) ShareWithGroup(name, path string) (*ShareGroupSync, error) {
+	path = zboxutil.RemoteClean(path)
+	if !zboxutil.IsRemoteAbs(path) {
+		return nil, errors.New("invalid_path", "Path should be valid and absolute")
+	}
+	return a.updateGroup(name, func(g *ShareGroup) error {
+		if !g.hasPath(path) {
+			g.Paths = append(g.Paths, path)
+		}
+		return nil
+	})
+}
+
+// UnshareWithGroup stops sharing path with the group name, revoking the
+// tickets of the members.
+func (a *Allocation) UnshareWithGroup(name, path string) (*ShareGroupSync, error) {
+	path = zboxutil.RemoteClean(path)
+	return a.updateGroup(name, func(g *ShareGroup) error {
+		paths := g.Paths[:0]
+		for _, p := range g.Paths {
+			if p != path {
+				paths = append(paths, p)
+			}
+		}
+		g.Paths = paths
+		return nil
+	})
+}
+
+// SyncShareGroup issues the missing tickets of the group name, for the members
+// and paths whose tickets failed before, and revokes the tickets of the
+// removed ones.
+func (a *Allocation) SyncShareGroup(name string) (*ShareGroupSync, error) {
+	return a.updateGroup(name, nil)
+}
+
+// syncGroup makes the tickets of g match its members and paths.
+func (a *Allocation) syncGroup(groups []*ShareGroup, g *ShareGroup) *ShareGroupSync {
+	sync := &ShareGroupSync{}
+
+	// revoke the tickets of the removed members and paths, unless another
+	// group shares the same path with the same client: the blobbers keep one
+	// share per path and client
+	kept := g.Tickets[:0]
+	for _, t := range g.Tickets {
+		if g.hasPath(t.Path) && g.Member(t.ClientID) != nil {
+			kept = append(kept, t)
+			continue
+		}
+		if !sharedByOtherGroup(groups, g, t) {
+			if err := a.RevokeShare(t.Path, t.ClientID); err != nil && !isShareNotFound(err) {
+				sync.Errors = append(sync.Errors, "revoke "+t.Path+" of "+t.ClientID+": "+err.Error())
+				kept = append(kept, t)
+				continue
+			}
+		}
+		sync.Revoked = append(sync.Revoked, t)
+	}
+	g.Tickets = kept
+
+	refTypes := make(map[string]string)
+	for _, path := range g.Paths {
+		for _, m := range g.Members {
+			if g.ticket(path, m.ClientID) != nil {
+				continue
+			}
+			refType, ok := refTypes[path]
+			if !ok {
+				meta, err := a.GetFileMeta(path)
+				if err != nil {
+					sync.Errors = append(sync.Errors, "share "+path+": "+err.Error())
+					break
+				}
+				refType = meta.Type
+				refTypes[path] = refType
+			}
+			if refType != fileref.DIRECTORY {
+				refType = fileref.FILE
+			}
+
+			at, err := a.GetAuthTicket(path, filepath.Base(path), refType, m.ClientID, m.EncryptionPublicKey, 0, nil)
+			if err != nil {
+				sync.Errors = append(sync.Errors, "share "+path+" with "+m.ClientID+": "+err.Error())
+				continue
+			}
+			t := &GroupTicket{Path: path, ClientID: m.ClientID, AuthTicket: at, IssuedAt: common.Now()}
+			g.Tickets = append(g.Tickets, t)
+			sync.Issued = append(sync.Issued, t)
+		}
+	}
+	return sync
+}
+
+func sharedByOtherGroup(groups []*ShareGroup, g *ShareGroup, t *GroupTicket) bool {
+	for _, o := range groups {
+		if o != g && o.ticket(t.Path, t.ClientID) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// removeTickets removes the tickets of clientID, of path too if not empty.
+func removeTickets(tickets []*GroupTicket, path, clientID string) []*GroupTicket {
+	kept := tickets[:0]
+	for _, t := range tickets {
+		if t.ClientID == clientID && (path == "" || t.Path == path) {
+			continue
+		}
+		kept = append(kept, t)
+	}
+	return kept
+}
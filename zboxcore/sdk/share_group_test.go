@@ -0,0 +1,306 @@
+package sdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/0chain/gosdk/zboxcore/blockchain"
+	"github.com/0chain/gosdk/zboxcore/client"
+	"github.com/0chain/gosdk/zboxcore/encryption"
+	"github.com/0chain/gosdk/zboxcore/fileref"
+	"github.com/0chain/gosdk/zboxcore/marker"
+	"github.com/0chain/gosdk/zboxcore/zboxutil"
+	"github.com/stretchr/testify/require"
+)
+
+type memShareGroupStore map[string][]*ShareGroup
+
+func (m memShareGroupStore) LoadGroups(a *Allocation) ([]*ShareGroup, error) {
+	return m[a.ID], nil
+}
+
+func (m memShareGroupStore) SaveGroups(a *Allocation, groups []*ShareGroup) error {
+	m[a.ID] = groups
+	return nil
+}
+
+func encryptionPublicKey(t *testing.T) string {
+	w, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	enc := encryption.NewEncryptionScheme()
+	_, err = enc.Initialize(w.Mnemonic)
+	require.NoError(t, err)
+	key, err := enc.GetPublicKey()
+	require.NoError(t, err)
+	return key
+}
+
+func TestShareGroups(t *testing.T) {
+	a := &Allocation{ID: "alloc"}
+	require.Error(t, a.CreateShareGroup("team"))
+
+	store := memShareGroupStore{}
+	SetShareGroupStore(store)
+	defer SetShareGroupStore(nil)
+
+	require.Error(t, a.CreateShareGroup(""))
+	require.NoError(t, a.CreateShareGroup("team"))
+	require.Error(t, a.CreateShareGroup("team"))
+
+	_, err := a.AddShareGroupMember("team", "bob", "not a key")
+	require.Error(t, err)
+	_, err = a.AddShareGroupMember("other", "bob", encryptionPublicKey(t))
+	require.Error(t, err)
+
+	// no paths yet, nothing to issue
+	key := encryptionPublicKey(t)
+	sync, err := a.AddShareGroupMember("team", "bob", key)
+	require.NoError(t, err)
+	require.Empty(t, sync.Issued)
+	require.Empty(t, sync.Errors)
+	_, err = a.AddShareGroupMember("team", "bob", key)
+	require.NoError(t, err)
+
+	groups, err := a.ListShareGroups()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0].Members, 1)
+	require.Equal(t, key, groups[0].Member("bob").EncryptionPublicKey)
+
+	_, err = a.ShareWithGroup("team", "docs")
+	require.Error(t, err)
+
+	_, err = a.RemoveShareGroupMember("team", "bob")
+	require.NoError(t, err)
+	require.Nil(t, store["alloc"][0].Member("bob"))
+
+	_, err = a.DeleteShareGroup("team")
+	require.NoError(t, err)
+	require.Empty(t, store["alloc"])
+	_, err = a.DeleteShareGroup("team")
+	require.Error(t, err)
+}
+
+func TestShareGroupTickets(t *testing.T) {
+	team := &ShareGroup{Name: "team", Tickets: []*GroupTicket{
+		{Path: "/docs", ClientID: "bob"},
+		{Path: "/docs", ClientID: "carol"},
+		{Path: "/music", ClientID: "bob"},
+	}}
+	other := &ShareGroup{Name: "other", Tickets: []*GroupTicket{{Path: "/music", ClientID: "bob"}}}
+	groups := []*ShareGroup{team, other}
+
+	require.Len(t, team.MemberTickets("bob"), 2)
+	require.True(t, sharedByOtherGroup(groups, team, team.Tickets[2]))
+	require.False(t, sharedByOtherGroup(groups, team, team.Tickets[0]))
+	require.True(t, sharedByOtherGroup(groups, other, other.Tickets[0]))
+
+	tickets := removeTickets(team.Tickets, "/docs", "bob")
+	require.Len(t, tickets, 2)
+	tickets = removeTickets(tickets, "", "bob")
+	require.Len(t, tickets, 1)
+	require.Equal(t, "carol", tickets[0].ClientID)
+}
+
+// blockingShareGroupStore blocks the saves of the allocation blocked until
+// release is closed.
+type blockingShareGroupStore struct {
+	memShareGroupStore
+	blocked string
+	saving  chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingShareGroupStore) SaveGroups(a *Allocation, groups []*ShareGroup) error {
+	if a.ID == b.blocked {
+		close(b.saving)
+		<-b.release
+	}
+	return b.memShareGroupStore.SaveGroups(a, groups)
+}
+
+func TestShareGroupLocks(t *testing.T) {
+	store := &blockingShareGroupStore{
+		memShareGroupStore: memShareGroupStore{},
+		blocked:            "slow",
+		saving:             make(chan struct{}),
+		release:            make(chan struct{}),
+	}
+	SetShareGroupStore(store)
+	defer SetShareGroupStore(nil)
+
+	slow := make(chan error)
+	go func() {
+		slow <- (&Allocation{ID: "slow"}).CreateShareGroup("team")
+	}()
+	<-store.saving
+
+	// the sync of an allocation doesn't block the groups of the others
+	done := make(chan error)
+	go func() {
+		done <- (&Allocation{ID: "fast"}).CreateShareGroup("team")
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the groups of fast are blocked by the save of slow")
+	}
+
+	close(store.release)
+	require.NoError(t, <-slow)
+}
+
+// shareBlobbers serves the file meta and the shares of the directories paths
+// of the allocation alloc, counting the tickets uploaded and revoked by path
+// and client.
+type shareBlobbers struct {
+	mu       sync.Mutex
+	paths    map[string]string
+	uploaded map[string]int
+	revoked  map[string]int
+	// keys are the encryption keys of the last tickets uploaded.
+	keys map[string]string
+}
+
+func newShareBlobbers(paths ...string) *shareBlobbers {
+	b := &shareBlobbers{
+		paths:    make(map[string]string),
+		uploaded: make(map[string]int),
+		revoked:  make(map[string]int),
+		keys:     make(map[string]string),
+	}
+	for _, p := range paths {
+		b.paths[fileref.GetReferenceLookup("alloc", p)] = p
+	}
+	return b
+}
+
+func (b *shareBlobbers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case strings.Contains(r.URL.Path, zboxutil.FILE_META_ENDPOINT):
+		path, ok := b.paths[r.FormValue("path_hash")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writeShareJSON(w, &fileref.FileRef{Ref: fileref.Ref{Type: fileref.DIRECTORY, Path: path, FileMetaHash: path}})
+	case strings.Contains(r.URL.Path, zboxutil.SHARE_ENDPOINT) && r.Method == http.MethodPost:
+		at := &marker.AuthTicket{}
+		if err := json.Unmarshal([]byte(r.FormValue("auth_ticket")), at); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		key := b.paths[at.FilePathHash] + " " + at.ClientID
+		b.uploaded[key]++
+		b.keys[key] = r.FormValue("encryption_public_key")
+		writeShareJSON(w, map[string]interface{}{"status": http.StatusOK})
+	case strings.Contains(r.URL.Path, zboxutil.SHARE_ENDPOINT) && r.Method == http.MethodDelete:
+		b.revoked[r.FormValue("path")+" "+r.FormValue("refereeClientID")]++
+		writeShareJSON(w, map[string]interface{}{"status": http.StatusOK})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeShareJSON(w http.ResponseWriter, v interface{}) {
+	buf, _ := json.Marshal(v)
+	w.Write(buf) //nolint: errcheck
+}
+
+func ticketKeys(tickets []*GroupTicket) []string {
+	keys := make([]string, 0, len(tickets))
+	for _, t := range tickets {
+		keys = append(keys, t.Path+" "+t.ClientID)
+	}
+	return keys
+}
+
+func TestSyncShareGroup(t *testing.T) {
+	previous, err := json.Marshal(client.GetClient())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.PopulateClient(string(previous), client.GetClient().SignatureScheme))
+	})
+	client.SetSigner(nil)
+	require.NoError(t, client.PopulateClient(newRotationWallet(t), "bls0chain"))
+	useHTTPClient(t)
+
+	blobbers := newShareBlobbers("/docs", "/music")
+	server := httptest.NewServer(blobbers)
+	defer server.Close()
+
+	a := &Allocation{ID: "alloc", Tx: "alloc", DataShards: 2, ParityShards: 1}
+	setupMockAllocation(t, a)
+	for i := 0; i < 3; i++ {
+		a.Blobbers = append(a.Blobbers, &blockchain.StorageNode{
+			ID:      "blobber_" + strconv.Itoa(i),
+			Baseurl: server.URL + "/" + strconv.Itoa(i),
+		})
+	}
+	SetShareGroupStore(memShareGroupStore{})
+	defer SetShareGroupStore(nil)
+
+	require.NoError(t, a.CreateShareGroup("team"))
+	_, err = a.ShareWithGroup("team", "/docs")
+	require.NoError(t, err)
+	_, err = a.ShareWithGroup("team", "/music")
+	require.NoError(t, err)
+	bobKey, carolKey := encryptionPublicKey(t), encryptionPublicKey(t)
+	_, err = a.AddShareGroupMember("team", "bob", bobKey)
+	require.NoError(t, err)
+	sync, err := a.AddShareGroupMember("team", "carol", carolKey)
+	require.NoError(t, err)
+	require.Empty(t, sync.Errors)
+	require.ElementsMatch(t, []string{"/docs carol", "/music carol"}, ticketKeys(sync.Issued))
+
+	// every member gets a ticket of every path
+	groups, err := a.ListShareGroups()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"/docs bob", "/music bob", "/docs carol", "/music carol"}, ticketKeys(groups[0].Tickets))
+	for _, key := range []string{"/docs bob", "/music bob", "/docs carol", "/music carol"} {
+		require.Equal(t, len(a.Blobbers), blobbers.uploaded[key], key)
+	}
+	require.Equal(t, bobKey, blobbers.keys["/docs bob"])
+
+	// removing a member revokes its tickets
+	sync, err = a.RemoveShareGroupMember("team", "carol")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"/docs carol", "/music carol"}, ticketKeys(sync.Revoked))
+	require.Equal(t, len(a.Blobbers), blobbers.revoked["/docs carol"])
+	require.Equal(t, len(a.Blobbers), blobbers.revoked["/music carol"])
+
+	// removing a path revokes its tickets
+	sync, err = a.UnshareWithGroup("team", "/music")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"/music bob"}, ticketKeys(sync.Revoked))
+	require.Equal(t, len(a.Blobbers), blobbers.revoked["/music bob"])
+
+	// a new key of a member reissues its tickets
+	newKey := encryptionPublicKey(t)
+	sync, err = a.AddShareGroupMember("team", "bob", newKey)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"/docs bob"}, ticketKeys(sync.Issued))
+	require.Equal(t, 2*len(a.Blobbers), blobbers.uploaded["/docs bob"])
+	require.Equal(t, newKey, blobbers.keys["/docs bob"])
+
+	// a path shared with the member by another group isn't revoked
+	require.NoError(t, a.CreateShareGroup("other"))
+	_, err = a.ShareWithGroup("other", "/docs")
+	require.NoError(t, err)
+	_, err = a.AddShareGroupMember("other", "bob", newKey)
+	require.NoError(t, err)
+	sync, err = a.UnshareWithGroup("team", "/docs")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"/docs bob"}, ticketKeys(sync.Revoked))
+	require.Zero(t, blobbers.revoked["/docs bob"])
+}
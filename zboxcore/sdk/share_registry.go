@@ -0,0 +1,501 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/constants"
+	"github.com/0chain/gosdk/core/common"
+	"github.com/0chain/gosdk/core/sys"
+	"github.com/0chain/gosdk/zboxcore/marker"
+	"github.com/0chain/gosdk/zboxcore/zboxutil"
+)
+
+// ShareRegistryPath is the hidden file of an allocation the
+// AllocationShareStore keeps the shares in, encrypted.
+const ShareRegistryPath = "/.shares.json"
+
+// ShareRecord is an auth ticket issued by the owner of an allocation.
+type ShareRecord struct {
+	Path                       string `json:"path"`
+	FileName                   string `json:"file_name"`
+	RefType                    string `json:"reference_type"`
+	RefereeClientID            string `json:"referee_client_id,omitempty"`
+	RefereeEncryptionPublicKey string `json:"referee_encryption_public_key,omitempty"`
+	// AuthTicket is the base64 ticket given to the referee. The ticket of the
+	// blobbers is the same with the re-encryption key of private shares, which
+	// isn't recorded.
+	AuthTicket     string           `json:"auth_ticket"`
+	AvailableAfter int64            `json:"available_after,omitempty"`
+	CreatedAt      common.Timestamp `json:"created_at"`
+	// Expiration is the time the ticket expires at, zero if it never does.
+	Expiration common.Timestamp `json:"expiration,omitempty"`
+	RevokedAt  common.Timestamp `json:"revoked_at,omitempty"`
+	// ReconciledAt is the last time ReconcileShares synced the share with the
+	// blobbers.
+	ReconciledAt common.Timestamp `json:"reconciled_at,omitempty"`
+}
+
+// IsRevoked reports whether the share was revoked.
+func (r *ShareRecord) IsRevoked() bool {
+	return r.RevokedAt > 0
+}
+
+// IsExpired reports whether the ticket has expired.
+func (r *ShareRecord) IsExpired() bool {
+	return r.Expiration > 0 && r.Expiration <= common.Now()
+}
+
+// IsActive reports whether the ticket can still be used.
+func (r *ShareRecord) IsActive() bool {
+	return !r.IsRevoked() && !r.IsExpired()
+}
+
+// blobberAuthTicket returns the ticket of the share to upload to the blobbers,
+// the one of the referee with a new re-encryption key for a private share.
+func (r *ShareRecord) blobberAuthTicket() (string, error) {
+	buf, err := base64.StdEncoding.DecodeString(r.AuthTicket)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid_auth_ticket")
+	}
+	at := &marker.AuthTicket{}
+	if err := json.Unmarshal(buf, at); err != nil {
+		return "", errors.Wrap(err, "invalid_auth_ticket")
+	}
+	if r.RefereeEncryptionPublicKey != "" {
+		if at.ReEncryptionKey, err = reEncryptionKey(r.RefereeEncryptionPublicKey); err != nil {
+			return "", err
+		}
+		if err := at.Sign(); err != nil {
+			return "", err
+		}
+	}
+	buf, err = json.Marshal(at)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// matches reports whether the record is a share of path or of an object under
+// it.
+func (r *ShareRecord) matches(path string) bool {
+	if path == "/" || r.Path == path {
+		return true
+	}
+	return strings.HasPrefix(r.Path, strings.TrimSuffix(path, "/")+"/")
+}
+
+// ShareStore persists the share registry of the allocations.
+type ShareStore interface {
+	// Load returns the shares of a, none if it has no registry yet.
+	Load(a *Allocation) ([]*ShareRecord, error)
+	// Save replaces the shares of a.
+	Save(a *Allocation, shares []*ShareRecord) error
+}
+
+var (
+	shareMu    sync.Mutex
+	shareStore ShareStore
+	// shareLocks serializes the updates of the registry of each allocation,
+	// guarded by shareMu.
+	shareLocks = make(map[string]*sync.Mutex)
+)
+
+// SetShareStore sets the store the auth tickets issued by GetAuthTicket are
+// recorded in, nil disables the share registry.
+func SetShareStore(s ShareStore) {
+	shareMu.Lock()
+	shareStore = s
+	shareMu.Unlock()
+}
+
+// GetShareStore returns the store of the share registry, nil if it is
+// disabled.
+func GetShareStore() ShareStore {
+	shareMu.Lock()
+	defer shareMu.Unlock()
+	return shareStore
+}
+
+// lockShares locks the share registry of the allocation id, it returns the
+// function unlocking it.
+func lockShares(id string) func() {
+	shareMu.Lock()
+	mu, ok := shareLocks[id]
+	if !ok {
+		mu = &sync.Mutex{}
+		shareLocks[id] = mu
+	}
+	shareMu.Unlock()
+
+	mu.Lock()
+	return mu.Unlock
+}
+
+// FileShareStore keeps the shares of every allocation in a json file of a local
+// directory.
+type FileShareStore struct {
+	dir string
+}
+
+// NewFileShareStore creates a FileShareStore in dir, ~/.zcn/shares if empty.
+func NewFileShareStore(dir string) (*FileShareStore, error) {
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.Wrap(err, "share_store_error")
+		}
+		dir = filepath.Join(home, ".zcn", "shares")
+	}
+	if err := sys.Files.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "share_store_error")
+	}
+	return &FileShareStore{dir: dir}, nil
+}
+
+func (s *FileShareStore) file(a *Allocation, suffix string) string {
+	return filepath.Join(s.dir, a.ID+suffix+".json")
+}
+
+// Load implements ShareStore.
+func (s *FileShareStore) Load(a *Allocation) ([]*ShareRecord, error) {
+	var shares []*ShareRecord
+	err := readLocalJSON(s.file(a, ""), &shares)
+	return shares, err
+}
+
+// Save implements ShareStore.
+func (s *FileShareStore) Save(a *Allocation, shares []*ShareRecord) error {
+	return writeLocalJSON(s.file(a, ""), shares)
+}
+
+// readLocalJSON unmarshals the json file name into v, left unchanged if the
+// file doesn't exist.
+func readLocalJSON(name string, v interface{}) error {
+	buf, err := sys.Files.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "share_store_error")
+	}
+	if err := json.Unmarshal(buf, v); err != nil {
+		return errors.Wrap(err, "share_store_error")
+	}
+	return nil
+}
+
+func writeLocalJSON(name string, v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "share_store_error")
+	}
+	if err := sys.Files.WriteFile(name, buf, 0600); err != nil {
+		return errors.Wrap(err, "share_store_error")
+	}
+	return nil
+}
+
+// AllocationShareStore keeps the shares of an allocation in its hidden file
+// ShareRegistryPath, encrypted with the keys of the owner so that they follow
+// the allocation across devices. The blobbers re-encrypt the file for the
+// referee of a private share of a directory holding it like any other, so
+// GetAuthTicket refuses those shares while it is set, see ErrShareStoreExposed.
+//
+// Every ticket recorded downloads and uploads the whole registry again: prefer
+// a FileShareStore to issue many tickets.
+type AllocationShareStore struct {
+	// Workdir is the directory the upload progress is kept in, the home
+	// directory if empty.
+	Workdir string
+}
+
+// Load implements ShareStore.
+func (s *AllocationShareStore) Load(a *Allocation) ([]*ShareRecord, error) {
+	var shares []*ShareRecord
+	err := readAllocationJSON(a, ShareRegistryPath, &shares)
+	return shares, err
+}
+
+// Save implements ShareStore.
+func (s *AllocationShareStore) Save(a *Allocation, shares []*ShareRecord) error {
+	return writeAllocationJSON(a, ShareRegistryPath, s.Workdir, shares)
+}
+
+// ErrShareStoreExposed is returned for a private share of a path that would
+// let the referee decrypt the files of AllocationShareStore.
+var ErrShareStoreExposed = errors.New("invalid_private_share", "a private share of the path would expose the share registry")
+
+// exposesShareStore reports whether a private share of path covers the hidden
+// files of the AllocationShareStore set as share or share group store.
+func exposesShareStore(path string) bool {
+	var paths []string
+	if _, ok := GetShareStore().(*AllocationShareStore); ok {
+		paths = append(paths, ShareRegistryPath)
+	}
+	if _, ok := GetShareGroupStore().(*AllocationShareStore); ok {
+		paths = append(paths, ShareGroupsPath)
+	}
+	for _, p := range paths {
+		if path == "/" || path == p || strings.HasPrefix(p, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// readAllocationJSON unmarshals the json file remotePath of a into v, left
+// unchanged if the file doesn't exist.
+func readAllocationJSON(a *Allocation, remotePath string, v interface{}) error {
+	res, err := a.GetRefs(remotePath, "", "", "", "", "regular", 0, 1)
+	if err != nil {
+		return errors.Wrap(err, "share_store_error")
+	}
+	if len(res.Refs) == 0 {
+		return nil
+	}
+
+	r, err := a.GetAllocationFileReader(remotePath, "", "", DOWNLOAD_CONTENT_FULL, true, 0)
+	if err != nil {
+		return errors.Wrap(err, "share_store_error")
+	}
+	defer r.Close()
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return errors.Wrap(err, "share_store_error")
+	}
+	if err := json.Unmarshal(buf, v); err != nil {
+		return errors.Wrap(err, "share_store_error")
+	}
+	return nil
+}
+
+// writeAllocationJSON uploads v as the encrypted json file remotePath of a.
+func writeAllocationJSON(a *Allocation, remotePath, workdir string, v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "share_store_error")
+	}
+	res, err := a.GetRefs(remotePath, "", "", "", "", "regular", 0, 1)
+	if err != nil {
+		return errors.Wrap(err, "share_store_error")
+	}
+
+	if workdir == "" {
+		if workdir, err = os.UserHomeDir(); err != nil {
+			return errors.Wrap(err, "share_store_error")
+		}
+	}
+	op := OperationRequest{
+		OperationType: constants.FileOperationInsert,
+		RemotePath:    remotePath,
+		Workdir:       workdir,
+		FileMeta: FileMeta{
+			MimeType:   "application/json",
+			ActualSize: int64(len(buf)),
+			RemoteName: filepath.Base(remotePath),
+			RemotePath: remotePath,
+		},
+		FileReader: bytes.NewReader(buf),
+		Opts:       []ChunkedUploadOption{WithEncrypt(true)},
+	}
+	if len(res.Refs) > 0 {
+		op.OperationType = constants.FileOperationUpdate
+	}
+	if err := a.DoMultiOperation([]OperationRequest{op}); err != nil {
+		return errors.Wrap(err, "share_store_error")
+	}
+	return nil
+}
+
+// updateShares loads the shares of a, lets update change them and saves them
+// if it returns true.
+func (a *Allocation) updateShares(update func(shares []*ShareRecord) ([]*ShareRecord, bool)) error {
+	store := GetShareStore()
+	if store == nil {
+		return nil
+	}
+	defer lockShares(a.ID)()
+
+	shares, err := store.Load(a)
+	if err != nil {
+		return err
+	}
+	shares, changed := update(shares)
+	if !changed {
+		return nil
+	}
+	return store.Save(a, shares)
+}
+
+// recordShare adds an issued ticket to the registry, replacing the previous
+// one of the same path and referee, which the blobbers override. It loads and
+// saves the whole registry.
+func (a *Allocation) recordShare(r *ShareRecord) error {
+	return a.updateShares(func(shares []*ShareRecord) ([]*ShareRecord, bool) {
+		for i, s := range shares {
+			if s.Path == r.Path && s.RefereeClientID == r.RefereeClientID {
+				shares[i] = r
+				return shares, true
+			}
+		}
+		return append(shares, r), true
+	})
+}
+
+// markRevoked records the revocation of the shares of path to refereeClientID.
+func (a *Allocation) markRevoked(path, refereeClientID string) error {
+	now := common.Now()
+	return a.updateShares(func(shares []*ShareRecord) ([]*ShareRecord, bool) {
+		changed := false
+		for _, s := range shares {
+			if s.Path == path && s.RefereeClientID == refereeClientID && !s.IsRevoked() {
+				s.RevokedAt = now
+				changed = true
+			}
+		}
+		return shares, changed
+	})
+}
+
+// ListShares returns the auth tickets of the allocation recorded in the share
+// registry, the revoked and expired ones included.
+func (a *Allocation) ListShares() ([]*ShareRecord, error) {
+	store := GetShareStore()
+	if store == nil {
+		return nil, errors.New("share_registry_disabled", "no share store is set")
+	}
+	defer lockShares(a.ID)()
+	return store.Load(a)
+}
+
+// RevokeAll revokes the active shares of path and, for a directory, of the
+// objects under it. Only the shares recorded in the registry are known. It
+// returns the revoked shares, and the first error if some couldn't be.
+func (a *Allocation) RevokeAll(path string) ([]*ShareRecord, error) {
+	path = zboxutil.RemoteClean(path)
+	shares, err := a.ListShares()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		revoked  []*ShareRecord
+		firstErr error
+	)
+	for _, s := range shares {
+		if !s.matches(path) || s.IsRevoked() {
+			continue
+		}
+		if err := a.RevokeShare(s.Path, s.RefereeClientID); err != nil && !isShareNotFound(err) {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		revoked = append(revoked, s)
+	}
+	return revoked, firstErr
+}
+
+// ExtendExpiry issues a new auth ticket for the share of path to
+// refereeClientID, expiring expiration seconds from now or never if zero, and
+// returns it. The expiration is signed in the ticket, so the referee must be
+// given the new one.
+func (a *Allocation) ExtendExpiry(path, refereeClientID string, expiration int64) (string, error) {
+	path = zboxutil.RemoteClean(path)
+	shares, err := a.ListShares()
+	if err != nil {
+		return "", err
+	}
+	for _, s := range shares {
+		if s.Path != path || s.RefereeClientID != refereeClientID {
+			continue
+		}
+		if s.IsRevoked() {
+			return "", errors.New("share_revoked", "the share of "+path+" was revoked")
+		}
+		var availableAfter *time.Time
+		if s.AvailableAfter > 0 {
+			t := time.Unix(s.AvailableAfter, 0)
+			availableAfter = &t
+		}
+		return a.GetAuthTicket(s.Path, s.FileName, s.RefType, s.RefereeClientID,
+			s.RefereeEncryptionPublicKey, expiration, availableAfter)
+	}
+	return "", errors.New("share_not_found", "no share of "+path+" to "+refereeClientID)
+}
+
+// ShareReconcileResult is what ReconcileShares did for a share.
+type ShareReconcileResult struct {
+	Share *ShareRecord `json:"share"`
+	// Action is "uploaded" for an active share, "revoked" for a revoked or
+	// expired one.
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReconcileShares syncs the blobbers with the share registry: the active
+// tickets are uploaded again to SHARE_ENDPOINT, for the blobbers that missed
+// them or replaced a blobber, and the revoked and expired ones are revoked
+// again, for the blobbers that missed the revocation.
+func (a *Allocation) ReconcileShares() ([]*ShareReconcileResult, error) {
+	shares, err := a.ListShares()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ShareReconcileResult, 0, len(shares))
+	for _, s := range shares {
+		res := &ShareReconcileResult{Share: s}
+		if s.IsActive() {
+			res.Action = "uploaded"
+			var availableAfter *time.Time
+			if s.AvailableAfter > 0 {
+				t := time.Unix(s.AvailableAfter, 0)
+				availableAfter = &t
+			}
+			var ticket string
+			if ticket, err = s.blobberAuthTicket(); err == nil {
+				err = a.UploadAuthTicketToBlobber(ticket, s.RefereeEncryptionPublicKey, availableAfter)
+			}
+		} else {
+			res.Action = "revoked"
+			err = a.revokeShare(a.ctx, s.Path, s.RefereeClientID)
+			if isShareNotFound(err) {
+				err = nil
+			}
+		}
+		if err != nil {
+			res.Error = err.Error()
+		} else {
+			s.ReconciledAt = common.Now()
+		}
+		results = append(results, res)
+	}
+
+	err = a.updateShares(func(current []*ShareRecord) ([]*ShareRecord, bool) {
+		for _, c := range current {
+			for _, s := range shares {
+				if c.Path == s.Path && c.RefereeClientID == s.RefereeClientID && c.CreatedAt == s.CreatedAt {
+					c.ReconciledAt = s.ReconciledAt
+				}
+			}
+		}
+		return current, true
+	})
+	return results, err
+}
+
+func isShareNotFound(err error) bool {
+	return err != nil && errors.Is(err, errShareNotFound)
+}
@@ -0,0 +1,214 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/0chain/gosdk/core/common"
+	"github.com/0chain/gosdk/core/encryption"
+	"github.com/0chain/gosdk/zboxcore/client"
+	"github.com/0chain/gosdk/zboxcore/fileref"
+	"github.com/0chain/gosdk/zboxcore/marker"
+	"github.com/stretchr/testify/require"
+)
+
+type memShareStore map[string][]*ShareRecord
+
+func (m memShareStore) Load(a *Allocation) ([]*ShareRecord, error) {
+	return m[a.ID], nil
+}
+
+func (m memShareStore) Save(a *Allocation, shares []*ShareRecord) error {
+	m[a.ID] = shares
+	return nil
+}
+
+func TestShareRecord(t *testing.T) {
+	now := common.Now()
+	r := &ShareRecord{Path: "/docs/a.txt"}
+	require.True(t, r.IsActive())
+
+	r.Expiration = now - 1
+	require.True(t, r.IsExpired())
+	require.False(t, r.IsActive())
+
+	r.Expiration = now + 100
+	r.RevokedAt = now
+	require.True(t, r.IsRevoked())
+	require.False(t, r.IsActive())
+
+	require.True(t, r.matches("/"))
+	require.True(t, r.matches("/docs"))
+	require.True(t, r.matches("/docs/"))
+	require.True(t, r.matches("/docs/a.txt"))
+	require.False(t, r.matches("/doc"))
+	require.False(t, r.matches("/docs/a"))
+}
+
+func TestShareRegistry(t *testing.T) {
+	a := &Allocation{ID: "alloc"}
+	_, err := a.ListShares()
+	require.Error(t, err)
+
+	store := memShareStore{}
+	SetShareStore(store)
+	defer SetShareStore(nil)
+
+	require.NoError(t, a.recordShare(&ShareRecord{Path: "/a.txt", AuthTicket: "t1", CreatedAt: 1}))
+	require.NoError(t, a.recordShare(&ShareRecord{Path: "/a.txt", RefereeClientID: "bob", AuthTicket: "t2", CreatedAt: 2}))
+	// a new ticket of the same path and referee replaces the previous one
+	require.NoError(t, a.recordShare(&ShareRecord{Path: "/a.txt", AuthTicket: "t3", CreatedAt: 3}))
+
+	shares, err := a.ListShares()
+	require.NoError(t, err)
+	require.Len(t, shares, 2)
+	require.Equal(t, "t3", shares[0].AuthTicket)
+
+	require.NoError(t, a.markRevoked("/a.txt", "bob"))
+	require.True(t, store["alloc"][1].IsRevoked())
+	require.False(t, store["alloc"][0].IsRevoked())
+
+	_, err = a.ExtendExpiry("/a.txt", "bob", 100)
+	require.Error(t, err)
+	_, err = a.ExtendExpiry("/b.txt", "", 100)
+	require.Error(t, err)
+
+	// nothing active under /other
+	revoked, err := a.RevokeAll("/other")
+	require.NoError(t, err)
+	require.Empty(t, revoked)
+}
+
+func TestFileShareStore(t *testing.T) {
+	s, err := NewFileShareStore(t.TempDir())
+	require.NoError(t, err)
+	a := &Allocation{ID: "alloc"}
+
+	shares, err := s.Load(a)
+	require.NoError(t, err)
+	require.Empty(t, shares)
+
+	want := []*ShareRecord{{Path: "/a.txt", RefereeClientID: "bob", Expiration: 10}}
+	require.NoError(t, s.Save(a, want))
+	shares, err = s.Load(a)
+	require.NoError(t, err)
+	require.Equal(t, want, shares)
+}
+
+// blockingShareStore blocks the saves of the allocation blocked until release
+// is closed.
+type blockingShareStore struct {
+	memShareStore
+	blocked string
+	saving  chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingShareStore) Save(a *Allocation, shares []*ShareRecord) error {
+	if a.ID == b.blocked {
+		close(b.saving)
+		<-b.release
+	}
+	return nil
+}
+
+func TestShareRegistryLocks(t *testing.T) {
+	store := &blockingShareStore{
+		memShareStore: memShareStore{},
+		blocked:       "slow",
+		saving:        make(chan struct{}),
+		release:       make(chan struct{}),
+	}
+	SetShareStore(store)
+	defer SetShareStore(nil)
+
+	slow := make(chan error)
+	go func() {
+		slow <- (&Allocation{ID: "slow"}).recordShare(&ShareRecord{Path: "/a.txt"})
+	}()
+	<-store.saving
+
+	// the save of an allocation doesn't block the registry of the others
+	done := make(chan error)
+	go func() {
+		done <- (&Allocation{ID: "fast"}).recordShare(&ShareRecord{Path: "/a.txt"})
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the registry of fast is blocked by the save of slow")
+	}
+
+	close(store.release)
+	require.NoError(t, <-slow)
+}
+
+func TestShareRecordBlobberAuthTicket(t *testing.T) {
+	previous, err := json.Marshal(client.GetClient())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.PopulateClient(string(previous), client.GetClient().SignatureScheme))
+	})
+	client.SetSigner(nil)
+	require.NoError(t, client.PopulateClient(newRotationWallet(t), "bls0chain"))
+
+	ticket := func(at *marker.AuthTicket) string {
+		require.NoError(t, at.Sign())
+		buf, err := json.Marshal(at)
+		require.NoError(t, err)
+		return base64.StdEncoding.EncodeToString(buf)
+	}
+	blobberTicket := func(r *ShareRecord) *marker.AuthTicket {
+		s, err := r.blobberAuthTicket()
+		require.NoError(t, err)
+		at := &marker.AuthTicket{}
+		require.NoError(t, json.Unmarshal([]byte(s), at))
+		ok, err := client.VerifySignature(at.Signature, encryption.Hash(at.GetHashData()))
+		require.NoError(t, err)
+		require.True(t, ok)
+		return at
+	}
+
+	// a public share is uploaded as given to the referee
+	public := &marker.AuthTicket{AllocationID: "alloc", OwnerID: client.GetClientID(), FileName: "a.txt", Timestamp: 1}
+	at := blobberTicket(&ShareRecord{AuthTicket: ticket(public)})
+	require.Equal(t, public, at)
+
+	// a private share gets a new re-encryption key
+	private := &marker.AuthTicket{AllocationID: "alloc", OwnerID: client.GetClientID(), ClientID: "bob",
+		FileName: "a.txt", Timestamp: 1, Expiration: 10, Encrypted: true}
+	r := &ShareRecord{AuthTicket: ticket(private), RefereeEncryptionPublicKey: encryptionPublicKey(t)}
+	at = blobberTicket(r)
+	require.NotEmpty(t, at.ReEncryptionKey)
+	at.ReEncryptionKey, at.Signature = "", private.Signature
+	require.Equal(t, private, at)
+
+	_, err = (&ShareRecord{AuthTicket: "not a ticket"}).blobberAuthTicket()
+	require.Error(t, err)
+}
+
+func TestShareStoreExposed(t *testing.T) {
+	require.False(t, exposesShareStore("/"))
+
+	SetShareStore(&AllocationShareStore{})
+	defer SetShareStore(nil)
+	require.True(t, exposesShareStore("/"))
+	require.True(t, exposesShareStore(ShareRegistryPath))
+	require.False(t, exposesShareStore(ShareGroupsPath))
+	require.False(t, exposesShareStore("/docs"))
+	require.False(t, exposesShareStore("/.shares"))
+
+	SetShareGroupStore(&AllocationShareStore{})
+	defer SetShareGroupStore(nil)
+	require.True(t, exposesShareStore(ShareGroupsPath))
+
+	a := &Allocation{ID: "alloc", Tx: "alloc"}
+	setupMockAllocation(t, a)
+	_, err := a.GetAuthTicket("/", "", fileref.DIRECTORY, "bob", "key", 0, nil)
+	require.ErrorIs(t, err, ErrShareStoreExposed)
+	_, err = a.GetAuthTicket(ShareRegistryPath, ".shares.json", fileref.FILE, "bob", "key", 0, nil)
+	require.ErrorIs(t, err, ErrShareStoreExposed)
+}
@@ -67,12 +67,7 @@ func (req *ShareRequest) getAuthTicket(clientID, encPublicKey string) (*marker.A
 	}
 
 	if encPublicKey != "" { // file is encrypted
-		encScheme := encryption.NewEncryptionScheme()
-		if _, err := encScheme.Initialize((client.GetClient().Mnemonic)); err != nil {
-			return nil, err
-		}
-
-		reKey, err := encScheme.GetReGenKey(encPublicKey, "filetype:audio")
+		reKey, err := reEncryptionKey(encPublicKey)
 		if err != nil {
 			return nil, err
 		}
@@ -87,3 +82,14 @@ func (req *ShareRequest) getAuthTicket(clientID, encPublicKey string) (*marker.A
 
 	return at, nil
 }
+
+// reEncryptionKey generates the key the blobbers re-encrypt the files of the
+// client for the owner of encPublicKey with. It is random, a new one is as
+// good as the previous.
+func reEncryptionKey(encPublicKey string) (string, error) {
+	encScheme := encryption.NewEncryptionScheme()
+	if _, err := encScheme.Initialize(client.GetClient().Mnemonic); err != nil {
+		return "", err
+	}
+	return encScheme.GetReGenKey(encPublicKey, "filetype:audio")
+}
@@ -61,11 +61,16 @@ func CreateWriteMarkerMutex(client *client.Client, allocationObj *Allocation) (*
 	}, nil
 }
 
+// Unlock releases the write marker locks of the blobbers of mask. They are
+// released even if ctx is canceled, before or during the unlock, otherwise the
+// allocation stays locked until the blobbers expire them: the requests keep
+// the values of ctx but not its cancellation, and each is bounded by timeOut.
 func (wmMu *WriteMarkerMutex) Unlock(
 	ctx context.Context, mask zboxutil.Uint128,
 	blobbers []*blockchain.StorageNode,
 	timeOut time.Duration, connID string,
 ) {
+	ctx = detachedContext{ctx}
 	wg := &sync.WaitGroup{}
 	var pos uint64
 	for i := mask; !i.Equals64(0); i = i.And(zboxutil.NewUint128(1).Lsh(pos).Not()) {
@@ -199,13 +204,24 @@ func (wmMu *WriteMarkerMutex) Lock(
 		select {
 		case <-methodCtx.Done():
 			logger.Logger.Error("Locking blobber: ", leadBlobber.Baseurl, " context timeout exceeded")
-			return errors.New("lock_timeout", "Locking blobber: "+leadBlobber.Baseurl+" context timeout exceeded")
+			return &LockConflictError{
+				ConnectionID: connID,
+				Required:     consensus.consensusThresh,
+				Got:          consensus.getConsensus() - addConsensus,
+				Msg:          "Locking blobber: " + leadBlobber.Baseurl + " context timeout exceeded",
+				Timeout:      true,
+			}
 		default:
 		}
 	}
 
 	if consensus.getConsensus()-addConsensus != 1 {
-		return errors.New("lock_consensus_not_met", "Failed to lock the lead blobber after retries")
+		return &LockConflictError{
+			ConnectionID: connID,
+			Required:     consensus.consensusThresh,
+			Got:          consensus.getConsensus() - addConsensus,
+			Msg:          "Failed to lock the lead blobber after retries",
+		}
 	}
 
 	// Once the lead blobber is locked successfully, lock the other blobbers
@@ -220,11 +236,15 @@ func (wmMu *WriteMarkerMutex) Lock(
 		go wmMu.lockBlobber(ctx, mask, maskMu, consensus, blobber, pos, connID, timeOut, wg)
 	}
 	wg.Wait()
-	if !consensus.isConsensusOk() {
+	if !consensus.recordConsensus(ctx, "writemarker_lock") {
 		wmMu.Unlock(ctx, *mask, blobbers, timeOut, connID)
-		return errors.New("lock_consensus_not_met",
-			fmt.Sprintf("Required consensus %d got %d",
-				consensus.consensusThresh, consensus.getConsensus()))
+		return &LockConflictError{
+			ConnectionID: connID,
+			Required:     consensus.consensusThresh,
+			Got:          consensus.getConsensus(),
+			Msg: fmt.Sprintf("Required consensus %d got %d",
+				consensus.consensusThresh, consensus.getConsensus()),
+		}
 	}
 
 	/* This goroutine will refresh lock after 30 seconds have passed. It will only complete if context is
@@ -366,3 +386,17 @@ func (wmMu *WriteMarkerMutex) lockBlobber(
 		}
 	}
 }
+
+// detachedContext is ctx without its deadline and cancellation, like
+// context.WithoutCancel of go 1.21.
+type detachedContext struct {
+	ctx context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.ctx.Value(key)
+}
@@ -19,8 +19,10 @@ import (
 	"github.com/0chain/errors"
 	"github.com/0chain/gosdk/core/conf"
 	"github.com/0chain/gosdk/core/encryption"
+	"github.com/0chain/gosdk/core/health"
 	"github.com/0chain/gosdk/core/logger"
-	"github.com/0chain/gosdk/core/util"
+	"github.com/0chain/gosdk/core/resty"
+	"github.com/0chain/gosdk/core/telemetry"
 	"github.com/0chain/gosdk/zboxcore/blockchain"
 	"github.com/0chain/gosdk/zboxcore/client"
 )
@@ -811,7 +813,7 @@ func MakeSCRestAPICall(scAddress string, relativePath string, params map[string]
 		sharderConsensous = conf.DefaultSharderConsensous
 	}
 	if numSharders > sharderConsensous {
-		sharders = util.Shuffle(sharders)[:sharderConsensous]
+		sharders = health.Default.Best(sharders, sharderConsensous)
 	}
 	for _, sharder := range sharders {
 		wg.Add(1)
@@ -825,11 +827,29 @@ func MakeSCRestAPICall(scAddress string, relativePath string, params map[string]
 			}
 			urlObj.RawQuery = q.Encode()
 			client := &http.Client{Transport: DefaultTransport}
+			if err := resty.DefaultCircuitBreaker.Allow(resty.NodeOf(urlObj)); err != nil {
+				return
+			}
+			start := time.Now()
 			response, err := client.Get(urlObj.String())
+			recordNodeHealth(telemetry.KindSharder, urlObj, start, response, err)
+			r := telemetry.Request{
+				Kind:    telemetry.KindSharder,
+				Method:  http.MethodGet,
+				Host:    urlObj.Host,
+				Path:    urlObj.Path,
+				Start:   start,
+				Attempt: 1,
+				Err:     err,
+			}
 
 			if err == nil {
 				defer response.Body.Close()
 				entityBytes, _ := ioutil.ReadAll(response.Body)
+				r.StatusCode = response.StatusCode
+				r.BytesReceived = int64(len(entityBytes))
+				r.Duration = time.Since(start)
+				telemetry.RecordRequest(context.Background(), r)
 				mu.Lock()
 				responses[response.StatusCode]++
 				if responses[response.StatusCode] > maxCount {
@@ -843,12 +863,23 @@ func MakeSCRestAPICall(scAddress string, relativePath string, params map[string]
 
 				entityResult[sharder] = entityBytes
 				mu.Unlock()
+				return
 			}
+			r.Duration = time.Since(start)
+			telemetry.RecordRequest(context.Background(), r)
 		}(sharder)
 	}
 	wg.Wait()
 
 	rate := float32(maxCount*100) / float32(sharderConsensous)
+	telemetry.RecordConsensus(context.Background(), telemetry.Consensus{
+		Operation: relativePath,
+		Kind:      telemetry.KindSharder,
+		Required:  sharderConsensous,
+		Got:       maxCount,
+		Total:     len(sharders),
+		OK:        rate > consensusThresh,
+	})
 	if rate < consensusThresh {
 		err = errors.New("consensus_failed", "consensus failed on sharders")
 	}
@@ -887,10 +918,18 @@ func HttpDo(ctx context.Context, cncl context.CancelFunc, req *http.Request, f f
 		// indefinitely try if io.EOF error occurs. As per some research over google
 		// it occurs when client http tries to send byte stream in connection that is
 		// closed by the server
-		for {
+		for attempt := 1; ; attempt++ {
 			var resp *http.Response
+			if err = resty.DefaultCircuitBreaker.Allow(resty.NodeOf(req.URL)); err != nil {
+				err = f(nil, err)
+				break
+			}
+			start := time.Now()
 			resp, err = Client.Do(req.WithContext(ctx))
+			recordNodeHealth(telemetry.KindBlobber, req.URL, start, resp, err)
+			telemetry.RecordRequest(ctx, telemetry.NewRequest(telemetry.KindBlobber, req, resp, start, attempt, err))
 			if errors.Is(err, io.EOF) {
+				telemetry.RecordRetry(ctx, telemetry.Retry{Operation: "blobber.http", Attempt: attempt + 1, Err: err})
 				continue
 			}
 
@@ -912,6 +951,19 @@ func HttpDo(ctx context.Context, cncl context.CancelFunc, req *http.Request, f f
 	}
 }
 
+// recordNodeHealth records the outcome of a request to u, sent at start, in
+// the circuit breaker and the health registry shared with the sharder and
+// miner calls.
+func recordNodeHealth(kind telemetry.Kind, u *url.URL, start time.Time, resp *http.Response, err error) {
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	node := resty.NodeOf(u)
+	resty.DefaultCircuitBreaker.Record(node, statusCode, err)
+	health.Default.Record(kind, node, time.Since(start), statusCode, err)
+}
+
 // isCurrentDominantStatus determines whether the current response status is the dominant status among responses.
 //
 // The dominant status is where the response status is counted the most.
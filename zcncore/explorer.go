@@ -0,0 +1,559 @@
+//go:build !mobile
+// +build !mobile
+
+package zcncore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/block"
+	"github.com/0chain/gosdk/core/common"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/core/util"
+)
+
+// DefaultExplorerPageSize is the number of items an Explorer fetches per
+// request.
+const DefaultExplorerPageSize = 50
+
+// ExplorerTransaction is a transaction indexed by the sharders, with the block
+// it was finalized in.
+type ExplorerTransaction struct {
+	transaction.Transaction
+	BlockHash string `json:"block_hash"`
+	Round     int64  `json:"round"`
+}
+
+// UnmarshalJSON decodes a transaction as indexed by the sharders, whose fields
+// aren't named as in transaction.Transaction.
+func (t *ExplorerTransaction) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Hash              string `json:"hash"`
+		BlockHash         string `json:"block_hash"`
+		Round             int64  `json:"round"`
+		Version           string `json:"version"`
+		ClientID          string `json:"client_id"`
+		ToClientID        string `json:"to_client_id"`
+		TransactionData   string `json:"transaction_data"`
+		Value             uint64 `json:"value"`
+		Signature         string `json:"signature"`
+		CreationDate      int64  `json:"creation_date"`
+		Fee               uint64 `json:"fee"`
+		Nonce             int64  `json:"nonce"`
+		TransactionType   int    `json:"transaction_type"`
+		TransactionOutput string `json:"transaction_output"`
+		OutputHash        string `json:"output_hash"`
+		Status            int    `json:"status"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*t = ExplorerTransaction{
+		Transaction: transaction.Transaction{
+			Hash:              v.Hash,
+			Version:           v.Version,
+			ClientID:          v.ClientID,
+			ToClientID:        v.ToClientID,
+			TransactionData:   v.TransactionData,
+			Value:             v.Value,
+			Signature:         v.Signature,
+			CreationDate:      v.CreationDate,
+			TransactionType:   v.TransactionType,
+			TransactionOutput: v.TransactionOutput,
+			TransactionFee:    v.Fee,
+			TransactionNonce:  v.Nonce,
+			OutputHash:        v.OutputHash,
+			Status:            v.Status,
+		},
+		BlockHash: v.BlockHash,
+		Round:     v.Round,
+	}
+	return nil
+}
+
+// Snapshot is the state of the network at a round.
+type Snapshot struct {
+	Round                int64          `json:"round"`
+	TotalMint            common.Balance `json:"total_mint"`
+	TotalChallengePools  common.Balance `json:"total_challenge_pools"`
+	ActiveAllocatedDelta int64          `json:"active_allocated_delta"`
+	ZCNSupply            common.Balance `json:"zcn_supply"`
+	TotalValueLocked     common.Balance `json:"total_value_locked"`
+	ClientLocks          common.Balance `json:"client_locks"`
+	MinedTotal           common.Balance `json:"mined_total"`
+	AverageWritePrice    int64          `json:"average_write_price"`
+	TotalStaked          common.Balance `json:"total_staked"`
+	TotalRewards         common.Balance `json:"total_rewards"`
+	SuccessfulChallenges int64          `json:"successful_challenges"`
+	TotalChallenges      int64          `json:"total_challenges"`
+	AllocatedStorage     int64          `json:"allocated_storage"`
+	MaxCapacityStorage   int64          `json:"max_capacity_storage"`
+	StakedStorage        int64          `json:"staked_storage"`
+	UsedStorage          int64          `json:"used_storage"`
+	TransactionsCount    int64          `json:"transactions_count"`
+	UniqueAddresses      int64          `json:"unique_addresses"`
+	BlockCount           int64          `json:"block_count"`
+	CreatedAt            int64          `json:"created_at"`
+}
+
+// BlobberSnapshot is the state of a blobber at a round.
+type BlobberSnapshot struct {
+	BlobberID           string         `json:"blobber_id"`
+	Round               int64          `json:"round"`
+	URL                 string         `json:"url"`
+	WritePrice          common.Balance `json:"write_price"`
+	Capacity            int64          `json:"capacity"`
+	Allocated           int64          `json:"allocated"`
+	SavedData           int64          `json:"saved_data"`
+	ReadData            int64          `json:"read_data"`
+	OffersTotal         common.Balance `json:"offers_total"`
+	TotalStake          common.Balance `json:"total_stake"`
+	TotalRewards        common.Balance `json:"total_rewards"`
+	TotalServiceCharge  common.Balance `json:"total_service_charge"`
+	ChallengesPassed    uint64         `json:"challenges_passed"`
+	ChallengesCompleted uint64         `json:"challenges_completed"`
+	OpenChallenges      uint64         `json:"open_challenges"`
+	Downtime            uint64         `json:"downtime"`
+	RankMetric          float64        `json:"rank_metric"`
+	IsKilled            bool           `json:"is_killed"`
+	IsShutdown          bool           `json:"is_shutdown"`
+}
+
+// MinerSnapshot is the state of a miner at a round.
+type MinerSnapshot struct {
+	MinerID       string         `json:"miner_id"`
+	Round         int64          `json:"round"`
+	Fees          common.Balance `json:"fees"`
+	TotalStake    common.Balance `json:"total_stake"`
+	TotalRewards  common.Balance `json:"total_rewards"`
+	ServiceCharge float64        `json:"service_charge"`
+	IsKilled      bool           `json:"is_killed"`
+	IsShutdown    bool           `json:"is_shutdown"`
+}
+
+// SharderSnapshot is the state of a sharder at a round.
+type SharderSnapshot struct {
+	SharderID     string         `json:"sharder_id"`
+	Round         int64          `json:"round"`
+	Fees          common.Balance `json:"fees"`
+	TotalStake    common.Balance `json:"total_stake"`
+	TotalRewards  common.Balance `json:"total_rewards"`
+	ServiceCharge float64        `json:"service_charge"`
+	IsKilled      bool           `json:"is_killed"`
+	IsShutdown    bool           `json:"is_shutdown"`
+}
+
+// ValidatorSnapshot is the state of a validator at a round.
+type ValidatorSnapshot struct {
+	ValidatorID   string         `json:"validator_id"`
+	Round         int64          `json:"round"`
+	TotalStake    common.Balance `json:"total_stake"`
+	TotalRewards  common.Balance `json:"total_rewards"`
+	ServiceCharge float64        `json:"service_charge"`
+	IsKilled      bool           `json:"is_killed"`
+	IsShutdown    bool           `json:"is_shutdown"`
+}
+
+// AuthorizerSnapshot is the state of an authorizer at a round.
+type AuthorizerSnapshot struct {
+	AuthorizerID  string         `json:"authorizer_id"`
+	Round         int64          `json:"round"`
+	Fee           common.Balance `json:"fee"`
+	TotalStake    common.Balance `json:"total_stake"`
+	TotalRewards  common.Balance `json:"total_rewards"`
+	ServiceCharge float64        `json:"service_charge"`
+	IsKilled      bool           `json:"is_killed"`
+	IsShutdown    bool           `json:"is_shutdown"`
+}
+
+// UserSnapshot is the state of a user at a round.
+type UserSnapshot struct {
+	UserID          string         `json:"user_id"`
+	Round           int64          `json:"round"`
+	CollectedReward common.Balance `json:"collected_reward"`
+	TotalStake      common.Balance `json:"total_stake"`
+	ReadPoolTotal   common.Balance `json:"read_pool_total"`
+	WritePoolTotal  common.Balance `json:"write_pool_total"`
+	PayedFees       common.Balance `json:"payed_fees"`
+}
+
+// TransactionFilter selects the transactions listed by an Explorer, empty
+// fields match any value.
+type TransactionFilter struct {
+	ClientID   string
+	ToClientID string
+	BlockHash  string
+	// StartRound and EndRound bound the rounds of the transactions, zero for
+	// no bound.
+	StartRound int64
+	EndRound   int64
+	// Sort is "asc" or "desc".
+	Sort string
+}
+
+// Explorer reads blocks, transactions and snapshots from the sharders.
+type Explorer struct {
+	// PageSize is the number of items fetched per request.
+	PageSize int
+}
+
+// NewExplorer creates an Explorer on the sharders of the network set with
+// Init.
+func NewExplorer() (*Explorer, error) {
+	if err := checkSdkInit(); err != nil {
+		return nil, err
+	}
+	return &Explorer{PageSize: DefaultExplorerPageSize}, nil
+}
+
+// LatestBlock returns the header of the latest finalized block.
+func (e *Explorer) LatestBlock(ctx context.Context) (*block.Header, error) {
+	return GetLatestFinalized(ctx, len(_config.chain.Sharders))
+}
+
+// Block returns the finalized block of round, with its transactions.
+func (e *Explorer) Block(ctx context.Context, round int64) (*block.Block, error) {
+	return GetBlockByRound(ctx, len(_config.chain.Sharders), round)
+}
+
+// Blocks iterates over the finalized blocks from round from to round to
+// included, to the latest finalized round if to is zero.
+func (e *Explorer) Blocks(from, to int64) *BlockIterator {
+	return &BlockIterator{explorer: e, next: from, to: to}
+}
+
+// Transactions iterates over the transactions matching filter.
+func (e *Explorer) Transactions(filter TransactionFilter) *TransactionIterator {
+	it := &TransactionIterator{}
+	it.init(e, func(ctx context.Context, offset, limit int) ([]json.RawMessage, error) {
+		params := Params{
+			"offset": strconv.Itoa(offset),
+			"limit":  strconv.Itoa(limit),
+		}
+		if filter.ClientID != "" {
+			params["client_id"] = filter.ClientID
+		}
+		if filter.ToClientID != "" {
+			params["to_client_id"] = filter.ToClientID
+		}
+		if filter.BlockHash != "" {
+			params["block_hash"] = filter.BlockHash
+		}
+		if filter.StartRound > 0 {
+			params["start"] = strconv.FormatInt(filter.StartRound, 10)
+		}
+		if filter.EndRound > 0 {
+			params["end"] = strconv.FormatInt(filter.EndRound, 10)
+		}
+		if filter.Sort != "" {
+			params["sort"] = filter.Sort
+		}
+		return e.getPage(ctx, withParams(STORAGESC_GET_TRANSACTIONS, params), false)
+	}, func(raw json.RawMessage) (interface{}, error) {
+		txn := &ExplorerTransaction{}
+		return txn, json.Unmarshal(raw, txn)
+	})
+	return it
+}
+
+// Snapshots iterates over the network snapshots from round.
+func (e *Explorer) Snapshots(round int64) *SnapshotIterator {
+	it := &SnapshotIterator{}
+	// this endpoint pages by round, not by offset
+	next := round
+	it.init(e, func(ctx context.Context, offset, limit int) ([]json.RawMessage, error) {
+		page, err := e.getPage(ctx, withParams(STORAGE_GET_SNAPSHOT, Params{
+			"round": strconv.FormatInt(next, 10),
+			"limit": strconv.Itoa(limit),
+		}), true)
+		if err != nil || len(page) == 0 {
+			return page, err
+		}
+		var last Snapshot
+		if err := json.Unmarshal(page[len(page)-1], &last); err != nil {
+			return nil, err
+		}
+		next = last.Round + 1
+		return page, nil
+	}, func(raw json.RawMessage) (interface{}, error) {
+		s := &Snapshot{}
+		return s, json.Unmarshal(raw, s)
+	})
+	return it
+}
+
+// BlobberSnapshots iterates over the blobber snapshots of round.
+func (e *Explorer) BlobberSnapshots(round int64) *BlobberSnapshotIterator {
+	it := &BlobberSnapshotIterator{}
+	it.init(e, e.snapshotPages(STORAGE_GET_BLOBBER_SNAPSHOT, round), func(raw json.RawMessage) (interface{}, error) {
+		s := &BlobberSnapshot{}
+		return s, json.Unmarshal(raw, s)
+	})
+	return it
+}
+
+// MinerSnapshots iterates over the miner snapshots of round.
+func (e *Explorer) MinerSnapshots(round int64) *MinerSnapshotIterator {
+	it := &MinerSnapshotIterator{}
+	it.init(e, e.snapshotPages(STORAGE_GET_MINER_SNAPSHOT, round), func(raw json.RawMessage) (interface{}, error) {
+		s := &MinerSnapshot{}
+		return s, json.Unmarshal(raw, s)
+	})
+	return it
+}
+
+// SharderSnapshots iterates over the sharder snapshots of round.
+func (e *Explorer) SharderSnapshots(round int64) *SharderSnapshotIterator {
+	it := &SharderSnapshotIterator{}
+	it.init(e, e.snapshotPages(STORAGE_GET_SHARDER_SNAPSHOT, round), func(raw json.RawMessage) (interface{}, error) {
+		s := &SharderSnapshot{}
+		return s, json.Unmarshal(raw, s)
+	})
+	return it
+}
+
+// ValidatorSnapshots iterates over the validator snapshots of round.
+func (e *Explorer) ValidatorSnapshots(round int64) *ValidatorSnapshotIterator {
+	it := &ValidatorSnapshotIterator{}
+	it.init(e, e.snapshotPages(STORAGE_GET_VALIDATOR_SNAPSHOT, round), func(raw json.RawMessage) (interface{}, error) {
+		s := &ValidatorSnapshot{}
+		return s, json.Unmarshal(raw, s)
+	})
+	return it
+}
+
+// AuthorizerSnapshots iterates over the authorizer snapshots of round.
+func (e *Explorer) AuthorizerSnapshots(round int64) *AuthorizerSnapshotIterator {
+	it := &AuthorizerSnapshotIterator{}
+	it.init(e, e.snapshotPages(STORAGE_GET_AUTHORIZER_SNAPSHOT, round), func(raw json.RawMessage) (interface{}, error) {
+		s := &AuthorizerSnapshot{}
+		return s, json.Unmarshal(raw, s)
+	})
+	return it
+}
+
+// UserSnapshots iterates over the user snapshots of round.
+func (e *Explorer) UserSnapshots(round int64) *UserSnapshotIterator {
+	it := &UserSnapshotIterator{}
+	it.init(e, e.snapshotPages(STORAGE_GET_USER_SNAPSHOT, round), func(raw json.RawMessage) (interface{}, error) {
+		s := &UserSnapshot{}
+		return s, json.Unmarshal(raw, s)
+	})
+	return it
+}
+
+func (e *Explorer) snapshotPages(uri string, round int64) pageFunc {
+	return func(ctx context.Context, offset, limit int) ([]json.RawMessage, error) {
+		return e.getPage(ctx, withParams(uri, Params{
+			"round":  strconv.FormatInt(round, 10),
+			"limit":  strconv.Itoa(limit),
+			"offset": strconv.Itoa(offset),
+		}), true)
+	}
+}
+
+func (e *Explorer) getPage(ctx context.Context, query string, fromAny bool) ([]json.RawMessage, error) {
+	body, err := getFromSharders(ctx, query, fromAny)
+	if err != nil {
+		return nil, err
+	}
+
+	var page []json.RawMessage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, errors.Wrap(err, "explorer: invalid response")
+	}
+	return page, nil
+}
+
+func (e *Explorer) pageSize() int {
+	if e.PageSize > 0 {
+		return e.PageSize
+	}
+	return DefaultExplorerPageSize
+}
+
+// getFromSharders returns the consensus response of the sharders to query, or
+// the response of a single sharder if fromAny is set.
+func getFromSharders(ctx context.Context, query string, fromAny bool) ([]byte, error) {
+	tq, err := NewTransactionQuery(util.Shuffle(_config.chain.Sharders), []string{})
+	if err != nil {
+		return nil, err
+	}
+	if fromAny {
+		qr, err := tq.FromAny(ctx, query, ProviderSharder)
+		if err != nil {
+			return nil, err
+		}
+		if qr.StatusCode != http.StatusOK {
+			return nil, errors.New("explorer_query_failed", string(qr.Content))
+		}
+		return qr.Content, nil
+	}
+	qr, err := tq.GetInfo(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return qr.Content, nil
+}
+
+type pageFunc func(ctx context.Context, offset, limit int) ([]json.RawMessage, error)
+
+// pageIterator iterates over the items of paginated responses.
+type pageIterator struct {
+	fetch    pageFunc
+	decode   func(raw json.RawMessage) (interface{}, error)
+	limit    int
+	offset   int
+	page     []json.RawMessage
+	current  interface{}
+	err      error
+	lastPage bool
+}
+
+func (it *pageIterator) init(e *Explorer, fetch pageFunc, decode func(raw json.RawMessage) (interface{}, error)) {
+	it.fetch = fetch
+	it.decode = decode
+	it.limit = e.pageSize()
+}
+
+// Next advances to the next item, it returns false at the end or on error.
+func (it *pageIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 {
+		if it.lastPage {
+			return false
+		}
+		it.page, it.err = it.fetch(ctx, it.offset, it.limit)
+		if it.err != nil {
+			return false
+		}
+		it.offset += len(it.page)
+		it.lastPage = len(it.page) < it.limit
+		if len(it.page) == 0 {
+			return false
+		}
+	}
+
+	it.current, it.err = it.decode(it.page[0])
+	it.page = it.page[1:]
+	return it.err == nil
+}
+
+// Err returns the error that stopped the iteration.
+func (it *pageIterator) Err() error {
+	return it.err
+}
+
+// TransactionIterator iterates over transactions.
+type TransactionIterator struct{ pageIterator }
+
+// Transaction returns the current transaction.
+func (it *TransactionIterator) Transaction() *ExplorerTransaction {
+	return it.current.(*ExplorerTransaction)
+}
+
+// SnapshotIterator iterates over network snapshots.
+type SnapshotIterator struct{ pageIterator }
+
+// Snapshot returns the current snapshot.
+func (it *SnapshotIterator) Snapshot() *Snapshot {
+	return it.current.(*Snapshot)
+}
+
+// BlobberSnapshotIterator iterates over blobber snapshots.
+type BlobberSnapshotIterator struct{ pageIterator }
+
+// Snapshot returns the current snapshot.
+func (it *BlobberSnapshotIterator) Snapshot() *BlobberSnapshot {
+	return it.current.(*BlobberSnapshot)
+}
+
+// MinerSnapshotIterator iterates over miner snapshots.
+type MinerSnapshotIterator struct{ pageIterator }
+
+// Snapshot returns the current snapshot.
+func (it *MinerSnapshotIterator) Snapshot() *MinerSnapshot {
+	return it.current.(*MinerSnapshot)
+}
+
+// SharderSnapshotIterator iterates over sharder snapshots.
+type SharderSnapshotIterator struct{ pageIterator }
+
+// Snapshot returns the current snapshot.
+func (it *SharderSnapshotIterator) Snapshot() *SharderSnapshot {
+	return it.current.(*SharderSnapshot)
+}
+
+// ValidatorSnapshotIterator iterates over validator snapshots.
+type ValidatorSnapshotIterator struct{ pageIterator }
+
+// Snapshot returns the current snapshot.
+func (it *ValidatorSnapshotIterator) Snapshot() *ValidatorSnapshot {
+	return it.current.(*ValidatorSnapshot)
+}
+
+// AuthorizerSnapshotIterator iterates over authorizer snapshots.
+type AuthorizerSnapshotIterator struct{ pageIterator }
+
+// Snapshot returns the current snapshot.
+func (it *AuthorizerSnapshotIterator) Snapshot() *AuthorizerSnapshot {
+	return it.current.(*AuthorizerSnapshot)
+}
+
+// UserSnapshotIterator iterates over user snapshots.
+type UserSnapshotIterator struct{ pageIterator }
+
+// Snapshot returns the current snapshot.
+func (it *UserSnapshotIterator) Snapshot() *UserSnapshot {
+	return it.current.(*UserSnapshot)
+}
+
+// BlockIterator iterates over finalized blocks.
+type BlockIterator struct {
+	explorer *Explorer
+	next     int64
+	to       int64
+	current  *block.Block
+	err      error
+}
+
+// Next advances to the next block, it returns false at the end or on error.
+func (it *BlockIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if it.to == 0 {
+		lfb, err := it.explorer.LatestBlock(ctx)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.to = lfb.Round
+	}
+	if it.next > it.to {
+		return false
+	}
+
+	it.current, it.err = it.explorer.Block(ctx, it.next)
+	if it.err != nil {
+		return false
+	}
+	it.next++
+	return true
+}
+
+// Block returns the current block.
+func (it *BlockIterator) Block() *block.Block {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration.
+func (it *BlockIterator) Err() error {
+	return it.err
+}
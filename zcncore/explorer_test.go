@@ -0,0 +1,154 @@
+//go:build !mobile
+// +build !mobile
+
+package zcncore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/0chain/gosdk/core/block"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSharder serves handler as the only sharder and miner of the network
+// until the end of the test.
+func mockSharder(t *testing.T, handler http.HandlerFunc) {
+	server := httptest.NewServer(handler)
+	config := _config
+	_config.chain.Sharders = []string{server.URL}
+	_config.chain.Miners = []string{server.URL}
+	_config.isConfigured = true
+	// the stable miners are picked from the miners of the network
+	ResetStableMiners()
+	t.Cleanup(func() {
+		server.Close()
+		_config = config
+		ResetStableMiners()
+	})
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(t, json.NewEncoder(w).Encode(v))
+}
+
+func queryInt(r *http.Request, name string) int64 {
+	v, _ := strconv.ParseInt(r.URL.Query().Get(name), 10, 64)
+	return v
+}
+
+func TestPageIterator(t *testing.T) {
+	var fetched [][2]int
+	it := &pageIterator{}
+	it.init(&Explorer{PageSize: 2}, func(_ context.Context, offset, limit int) ([]json.RawMessage, error) {
+		fetched = append(fetched, [2]int{offset, limit})
+		var page []json.RawMessage
+		for i := offset; i < 5 && i < offset+limit; i++ {
+			page = append(page, json.RawMessage(strconv.Itoa(i)))
+		}
+		return page, nil
+	}, func(raw json.RawMessage) (interface{}, error) {
+		var v int
+		return v, json.Unmarshal(raw, &v)
+	})
+
+	var got []int
+	for it.Next(context.Background()) {
+		got = append(got, it.current.(int))
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []int{0, 1, 2, 3, 4}, got)
+	// the short last page ends the iteration without a further request
+	require.Equal(t, [][2]int{{0, 2}, {2, 2}, {4, 2}}, fetched)
+	require.False(t, it.Next(context.Background()))
+}
+
+func TestExplorerTransactions(t *testing.T) {
+	mockSharder(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, STORAGESC_GET_TRANSACTIONS, r.URL.Path)
+		require.Equal(t, "alice", r.URL.Query().Get("client_id"))
+		var page []map[string]interface{}
+		for i := queryInt(r, "offset"); i < 3 && i < queryInt(r, "offset")+queryInt(r, "limit"); i++ {
+			page = append(page, map[string]interface{}{
+				"hash": fmt.Sprint("txn", i), "round": 10 + i, "block_hash": "b",
+				"client_id": "alice", "value": 5, "fee": 1, "nonce": i, "status": 1,
+			})
+		}
+		writeJSON(t, w, page)
+	})
+
+	it := (&Explorer{PageSize: 2}).Transactions(TransactionFilter{ClientID: "alice"})
+	var hashes []string
+	for it.Next(context.Background()) {
+		txn := it.Transaction()
+		hashes = append(hashes, txn.Hash)
+		require.Equal(t, uint64(5), txn.Value)
+		require.Equal(t, uint64(1), txn.TransactionFee)
+		require.Equal(t, 10+txn.TransactionNonce, txn.Round)
+		require.Equal(t, "b", txn.BlockHash)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"txn0", "txn1", "txn2"}, hashes)
+}
+
+func TestExplorerSnapshots(t *testing.T) {
+	rounds := []int64{100, 200, 300, 400, 500}
+	mockSharder(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, STORAGE_GET_SNAPSHOT, r.URL.Path)
+		// the endpoint pages by round, the offset is never sent
+		require.Empty(t, r.URL.Query().Get("offset"))
+		var page []*Snapshot
+		for _, round := range rounds {
+			if round >= queryInt(r, "round") && int64(len(page)) < queryInt(r, "limit") {
+				page = append(page, &Snapshot{Round: round})
+			}
+		}
+		writeJSON(t, w, page)
+	})
+
+	it := (&Explorer{PageSize: 2}).Snapshots(150)
+	var got []int64
+	for it.Next(context.Background()) {
+		got = append(got, it.Snapshot().Round)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []int64{200, 300, 400, 500}, got)
+}
+
+func TestBlockIterator(t *testing.T) {
+	mockSharder(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case GET_LATEST_FINALIZED:
+			writeJSON(t, w, &block.Header{Hash: "h12", Round: 12})
+		case "/v1/block/get":
+			round := queryInt(r, "round")
+			hash := fmt.Sprint("h", round)
+			writeJSON(t, w, map[string]interface{}{
+				"block":  map[string]interface{}{"hash": hash, "round": round},
+				"header": &block.Header{Hash: hash, Round: round},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	e := &Explorer{}
+	it := e.Blocks(10, 0)
+	var got []int64
+	for it.Next(context.Background()) {
+		got = append(got, it.Block().Round)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []int64{10, 11, 12}, got)
+
+	it = e.Blocks(11, 11)
+	require.True(t, it.Next(context.Background()))
+	require.Equal(t, "h11", string(it.Block().Hash))
+	require.False(t, it.Next(context.Background()))
+}
@@ -0,0 +1,121 @@
+//go:build !mobile
+// +build !mobile
+
+package zcncore
+
+import (
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/0chain/gosdk/zboxcore/sdk"
+)
+
+// DefaultDiscoveryGap is the number of consecutive unused accounts after which
+// DiscoverAccounts stops.
+const DefaultDiscoveryGap = 20
+
+// DerivedAccount is an account derived from a mnemonic.
+type DerivedAccount struct {
+	Index  uint32 `json:"index"`
+	Path   string `json:"path"`
+	Wallet string `json:"wallet"`
+
+	ClientID    string `json:"client_id"`
+	Balance     int64  `json:"balance"`
+	Nonce       int64  `json:"nonce"`
+	Allocations int    `json:"allocations"`
+}
+
+// DiscoverOptions configures DiscoverAccounts.
+type DiscoverOptions struct {
+	// Password protects the seed of the mnemonic, like for NewHDWallet.
+	Password string
+	// Gap is the number of consecutive unused accounts to stop after.
+	Gap int
+	// CheckAllocations also looks for allocations owned by the accounts, the
+	// storage sdk must be initialized.
+	CheckAllocations bool
+}
+
+// DeriveWallet derives the wallet at path from the mnemonic, with the
+// signature scheme of the network, and returns it as json.
+//   - path: a derivation path like m/44'/36363'/0'/0'/0', see
+//     zcncrypto.ParseDerivationPath
+func DeriveWallet(mnemonic, password, path string) (string, error) {
+	if err := checkSdkInit(); err != nil {
+		return "", err
+	}
+	p, err := zcncrypto.ParseDerivationPath(path)
+	if err != nil {
+		return "", err
+	}
+	hd, err := zcncrypto.NewHDWallet(_config.chain.SignatureScheme, mnemonic, password)
+	if err != nil {
+		return "", err
+	}
+	w, err := hd.Derive(p)
+	if err != nil {
+		return "", err
+	}
+	return w.Marshal()
+}
+
+// DiscoverAccounts derives the accounts of the mnemonic in order and returns
+// the used ones: with a balance, a nonce or, optionally, allocations. It stops
+// after opts.Gap consecutive unused accounts, so that one backup mnemonic
+// recovers all the wallets derived from it.
+func DiscoverAccounts(mnemonic string, opts DiscoverOptions) ([]*DerivedAccount, error) {
+	if err := checkSdkInit(); err != nil {
+		return nil, err
+	}
+	hd, err := zcncrypto.NewHDWallet(_config.chain.SignatureScheme, mnemonic, opts.Password)
+	if err != nil {
+		return nil, err
+	}
+	gap := opts.Gap
+	if gap <= 0 {
+		gap = DefaultDiscoveryGap
+	}
+
+	var accounts []*DerivedAccount
+	for index, unused := uint32(0), 0; unused < gap; index++ {
+		w, err := hd.Account(index)
+		if err != nil {
+			return nil, err
+		}
+		account := &DerivedAccount{
+			Index:    index,
+			Path:     zcncrypto.AccountPath(index).String(),
+			ClientID: w.ClientID,
+		}
+		if err := discoverAccount(account, opts.CheckAllocations); err != nil {
+			return nil, err
+		}
+		if account.Balance == 0 && account.Nonce == 0 && account.Allocations == 0 {
+			unused++
+			continue
+		}
+
+		unused = 0
+		if account.Wallet, err = w.Marshal(); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+func discoverAccount(account *DerivedAccount, checkAllocations bool) error {
+	values, _, err := getBalanceFieldsFromSharders(account.ClientID, "balance", "nonce")
+	if err != nil {
+		return errors.Wrap(err, "failed to get the balance of "+account.ClientID)
+	}
+	account.Balance, account.Nonce = values[0], values[1]
+	if checkAllocations {
+		allocs, err := sdk.GetAllocationsForClient(account.ClientID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get the allocations of "+account.ClientID)
+		}
+		account.Allocations = len(allocs)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+//go:build !mobile
+// +build !mobile
+
+package zcncore
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverAccounts(t *testing.T) {
+	mnemonic := "glare mistake gun joke bid spare across diagram wrap cube swear cactus cave repeat you brave few best wild lion pitch pole original wasp"
+	hd, err := zcncrypto.NewHDWallet("bls0chain", mnemonic, "")
+	require.NoError(t, err)
+	used, err := hd.Account(1)
+	require.NoError(t, err)
+
+	scheme := _config.chain.SignatureScheme
+	_config.chain.SignatureScheme = "bls0chain"
+	defer func() { _config.chain.SignatureScheme = scheme }()
+	var requests int
+	mockSharder(t, func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasPrefix(GET_BALANCE, r.URL.Path+"?"))
+		requests++
+		if r.URL.Query().Get("client_id") != used.ClientID {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"value not present"}`)) //nolint: errcheck
+			return
+		}
+		writeJSON(t, w, map[string]int64{"balance": 0, "nonce": 3})
+	})
+
+	accounts, err := DiscoverAccounts(mnemonic, DiscoverOptions{Gap: 2})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, used.ClientID, accounts[0].ClientID)
+	require.Equal(t, int64(3), accounts[0].Nonce)
+	// one request per account, accounts 2 and 3 are the unused gap
+	require.Equal(t, 4, requests)
+}
@@ -0,0 +1,439 @@
+//go:build !mobile
+// +build !mobile
+
+package zcncore
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/core/zcncrypto"
+)
+
+// MSProposalVersion is the version of the proposal format written by this sdk.
+const MSProposalVersion = 1
+
+const (
+	// MSProposalTransfer a proposal to transfer tokens from the multisig wallet.
+	MSProposalTransfer = "transfer"
+	// MSProposalSCCall a proposal to call a smart contract from the multisig
+	// wallet.
+	MSProposalSCCall = "sc_call"
+)
+
+// MSPartialSignature is the signature of a proposal by one signer.
+type MSPartialSignature struct {
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+// MSProposal is a transaction of a multisig wallet waiting for the signatures
+// of its signers. It is a portable document: it is passed from signer to
+// signer, or to each signer and merged, and each one adds its signature,
+// offline if needed. Once enough signers signed, their signatures are combined
+// into the signature of the multisig wallet and the transaction is submitted
+// like any other.
+//
+//	p, _ := CreateMSTransferProposal(msWallet, toClientID, value, 0, fee)
+//	p, _ = SignMSProposal(p, signerWallet1)
+//	p, _ = SignMSProposal(p, signerWallet2)
+//	hash, _ := SubmitMSProposal(p)
+//	status, _ := GetMSProposalStatus(p)
+type MSProposal struct {
+	Version int    `json:"version"`
+	Kind    string `json:"kind"`
+	// Wallet is the multisig wallet, its signers and the number of signatures
+	// required.
+	Wallet MultisigSCWallet `json:"wallet"`
+	// Envelope is the transaction of the multisig wallet, its hash is what the
+	// signers sign.
+	Envelope *transaction.Envelope `json:"envelope"`
+	Partials []MSPartialSignature  `json:"partials,omitempty"`
+}
+
+// MSProposalStatus is the progress of a proposal.
+type MSProposalStatus struct {
+	// Hash is the hash of the transaction of the proposal.
+	Hash string `json:"hash"`
+	Kind string `json:"kind"`
+	// Signed is the number of signers who signed, out of Required.
+	Signed   int `json:"signed"`
+	Required int `json:"required"`
+	// Pending are the public keys of the signers who didn't sign yet.
+	Pending []string `json:"pending"`
+	// Ready is true once enough signers signed to submit the proposal.
+	Ready bool `json:"ready"`
+	// Confirmed is true once the transaction is in a finalized block, its
+	// status and output are then set.
+	Confirmed bool   `json:"confirmed"`
+	Status    int    `json:"status,omitempty"`
+	Output    string `json:"output,omitempty"`
+}
+
+// CreateMSTransferProposal creates a proposal to transfer value tokens from the
+// multisig wallet to toClientID.
+//   - msWallet: the multisig wallet, as returned by CreateMSWallet or
+//     GetMultisigPayload
+//   - nonce: the nonce of the transaction, the next nonce of the multisig
+//     wallet if zero
+func CreateMSTransferProposal(msWallet, toClientID string, value uint64, nonce int64, fee uint64) (string, error) {
+	if toClientID == "" {
+		return "", errors.New("", "toClientID cannot be empty")
+	}
+	return createMSProposal(msWallet, MSProposalTransfer, transaction.UnsignedTxn{
+		ToClientID:      toClientID,
+		Value:           value,
+		TransactionType: transaction.TxnTypeSend,
+		Nonce:           nonce,
+		Fee:             fee,
+	})
+}
+
+// CreateMSSCCallProposal creates a proposal to call the method of the smart
+// contract at address from the multisig wallet. The fee is estimated if zero.
+// See CreateMSTransferProposal.
+func CreateMSSCCallProposal(msWallet, address, methodName string, input interface{}, value uint64, nonce int64, fee uint64) (string, error) {
+	data, err := json.Marshal(transaction.SmartContractTxnData{Name: methodName, InputArgs: input})
+	if err != nil {
+		return "", errors.Wrap(err, "create smart contract failed due to invalid data")
+	}
+	return createMSProposal(msWallet, MSProposalSCCall, transaction.UnsignedTxn{
+		ToClientID:      address,
+		Value:           value,
+		TransactionType: transaction.TxnTypeSmartContract,
+		TransactionData: string(data),
+		Nonce:           nonce,
+		Fee:             fee,
+	})
+}
+
+func createMSProposal(msWallet, kind string, req transaction.UnsignedTxn) (string, error) {
+	if err := checkSdkInit(); err != nil {
+		return "", err
+	}
+	w, err := parseMultisigWallet(msWallet)
+	if err != nil {
+		return "", err
+	}
+
+	if req.Nonce == 0 {
+		nonce, _, err := getNonceFromSharders(w.ClientID)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get the nonce of the multisig wallet")
+		}
+		req.Nonce = nonce + 1
+	}
+	req.ClientID = w.ClientID
+	req.PublicKey = w.PublicKey
+	req.ChainID = _config.chain.ChainID
+	req.SignatureScheme = w.SignatureScheme
+
+	env, err := transaction.BuildUnsigned(req, _config.chain.Miners)
+	if err != nil {
+		return "", err
+	}
+	return (&MSProposal{
+		Version:  MSProposalVersion,
+		Kind:     kind,
+		Wallet:   *w,
+		Envelope: env,
+	}).marshal()
+}
+
+// SignMSProposal adds the signature of a signer to the proposal. It doesn't
+// need the sdk to be initialized, so it can run on an offline machine.
+//   - signerWallet: the wallet of the signer, as returned by CreateMSWallet
+func SignMSProposal(proposal, signerWallet string) (string, error) {
+	p, err := parseMSProposal(proposal)
+	if err != nil {
+		return "", err
+	}
+	w, err := getWallet(signerWallet)
+	if err != nil {
+		return "", err
+	}
+	if len(w.Keys) == 0 {
+		return "", errors.New("", "the signer wallet has no keys")
+	}
+	publicKey := w.Keys[0].PublicKey
+	if p.signerIndex(publicKey) < 0 {
+		return "", errors.New("", "the wallet is not a signer of the multisig wallet")
+	}
+
+	scheme := zcncrypto.NewSignatureScheme(p.Wallet.SignatureScheme)
+	if err := scheme.SetPrivateKey(w.Keys[0].PrivateKey); err != nil {
+		return "", err
+	}
+	sig, err := scheme.Sign(p.Envelope.Transaction.Hash)
+	if err != nil {
+		return "", err
+	}
+	if err := p.addPartial(MSPartialSignature{PublicKey: publicKey, Signature: sig}); err != nil {
+		return "", err
+	}
+	return p.marshal()
+}
+
+// MergeMSProposals merges the signatures of copies of the same proposal
+// signed separately.
+func MergeMSProposals(proposals ...string) (string, error) {
+	if len(proposals) == 0 {
+		return "", errors.New("", "no proposal to merge")
+	}
+	merged, err := parseMSProposal(proposals[0])
+	if err != nil {
+		return "", err
+	}
+	for _, proposal := range proposals[1:] {
+		p, err := parseMSProposal(proposal)
+		if err != nil {
+			return "", err
+		}
+		if p.Envelope.Transaction.Hash != merged.Envelope.Transaction.Hash {
+			return "", errors.New("", "the proposals are not for the same transaction")
+		}
+		for _, partial := range p.Partials {
+			if err := merged.addPartial(partial); err != nil {
+				return "", err
+			}
+		}
+	}
+	return merged.marshal()
+}
+
+// CombineMSProposal combines the signatures of the proposal into the signature
+// of the multisig wallet and returns the signed envelope of the transaction,
+// see BroadcastTxnEnvelope.
+func CombineMSProposal(proposal string) (string, error) {
+	p, err := parseMSProposal(proposal)
+	if err != nil {
+		return "", err
+	}
+	if err := p.combine(); err != nil {
+		return "", err
+	}
+	buf, err := p.Envelope.Marshal()
+	return string(buf), err
+}
+
+// SubmitMSProposal combines the signatures of the proposal and sends its
+// transaction to the miners. It returns the hash of the transaction, see
+// GetMSProposalStatus to follow it.
+func SubmitMSProposal(proposal string) (string, error) {
+	if err := checkSdkInit(); err != nil {
+		return "", err
+	}
+	p, err := parseMSProposal(proposal)
+	if err != nil {
+		return "", err
+	}
+	if err := p.combine(); err != nil {
+		return "", err
+	}
+	return transaction.BroadcastEnvelope(p.Envelope, GetStableMiners())
+}
+
+// GetMSProposalStatus returns the progress of the proposal. The transaction is
+// looked up on the sharders if the sdk is initialized.
+func GetMSProposalStatus(proposal string) (*MSProposalStatus, error) {
+	p, err := parseMSProposal(proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MSProposalStatus{
+		Hash:     p.Envelope.Transaction.Hash,
+		Kind:     p.Kind,
+		Signed:   len(p.Partials),
+		Required: p.Wallet.NumRequired,
+		Pending:  []string{},
+	}
+	status.Ready = status.Signed >= status.Required
+	for _, pk := range p.Wallet.SignerPublicKeys {
+		if p.partialIndex(pk) < 0 {
+			status.Pending = append(status.Pending, pk)
+		}
+	}
+
+	if checkSdkInit() == nil {
+		txn, err := transaction.VerifyTransaction(status.Hash, _config.chain.Sharders)
+		if err == nil && txn != nil {
+			status.Confirmed = true
+			status.Status = txn.Status
+			status.Output = txn.TransactionOutput
+		}
+	}
+	return status, nil
+}
+
+func parseMultisigWallet(msWallet string) (*MultisigSCWallet, error) {
+	w := &MultisigSCWallet{}
+	if err := json.Unmarshal([]byte(msWallet), w); err != nil {
+		return nil, errors.Wrap(err, "invalid multisig wallet")
+	}
+	if w.ClientID == "" {
+		// a wallet created by CreateMSWallet
+		payload, err := GetMultisigPayload(msWallet)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid multisig wallet")
+		}
+		scw := payload.(MultisigSCWallet)
+		w = &scw
+	}
+	if err := validateMultisigWallet(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func validateMultisigWallet(w *MultisigSCWallet) error {
+	if w.ClientID == "" || w.PublicKey == "" {
+		return errors.New("", "invalid multisig wallet: missing client id or public key")
+	}
+	if w.SignatureScheme != "bls0chain" {
+		return errors.New("", "invalid multisig wallet: the signature scheme must be bls0chain")
+	}
+	n := len(w.SignerPublicKeys)
+	if w.NumRequired < 1 || w.NumRequired > n {
+		return errors.New("", fmt.Sprintf("invalid multisig wallet: %d signatures required out of %d signers", w.NumRequired, n))
+	}
+	if len(w.SignerThresholdIDs) == 0 && w.NumRequired != n {
+		return errors.New("", "invalid multisig wallet: the signers have no threshold ids, all of them must sign")
+	}
+	if len(w.SignerThresholdIDs) > 0 && len(w.SignerThresholdIDs) != n {
+		return errors.New("", "invalid multisig wallet: one threshold id per signer is required")
+	}
+	return nil
+}
+
+func parseMSProposal(proposal string) (*MSProposal, error) {
+	var raw struct {
+		Version  int                  `json:"version"`
+		Kind     string               `json:"kind"`
+		Wallet   MultisigSCWallet     `json:"wallet"`
+		Envelope json.RawMessage      `json:"envelope"`
+		Partials []MSPartialSignature `json:"partials"`
+	}
+	if err := json.Unmarshal([]byte(proposal), &raw); err != nil {
+		return nil, errors.Wrap(err, "invalid multisig proposal")
+	}
+	if raw.Version != MSProposalVersion {
+		return nil, errors.New("", fmt.Sprintf("invalid multisig proposal: unsupported version %d", raw.Version))
+	}
+	if err := validateMultisigWallet(&raw.Wallet); err != nil {
+		return nil, err
+	}
+	// the hash of the transaction is checked, so that signers sign what the
+	// proposal shows
+	env, err := transaction.UnmarshalEnvelope(raw.Envelope)
+	if err != nil {
+		return nil, err
+	}
+	if env.Transaction.ClientID != raw.Wallet.ClientID || env.Transaction.PublicKey != raw.Wallet.PublicKey {
+		return nil, errors.New("", "invalid multisig proposal: the transaction is not from the multisig wallet")
+	}
+
+	p := &MSProposal{
+		Version:  raw.Version,
+		Kind:     raw.Kind,
+		Wallet:   raw.Wallet,
+		Envelope: env,
+	}
+	for _, partial := range raw.Partials {
+		if err := p.addPartial(partial); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// addPartial verifies the signature of a signer and adds it, replacing the
+// previous one of the signer.
+func (p *MSProposal) addPartial(partial MSPartialSignature) error {
+	if p.signerIndex(partial.PublicKey) < 0 {
+		return errors.New("", "invalid multisig proposal: signature of an unknown signer")
+	}
+	scheme := zcncrypto.NewSignatureScheme(p.Wallet.SignatureScheme)
+	if err := scheme.SetPublicKey(partial.PublicKey); err != nil {
+		return err
+	}
+	ok, err := scheme.Verify(partial.Signature, p.Envelope.Transaction.Hash)
+	if err != nil || !ok {
+		return errors.New("", "invalid multisig proposal: invalid signature of signer "+partial.PublicKey)
+	}
+
+	if i := p.partialIndex(partial.PublicKey); i >= 0 {
+		p.Partials[i] = partial
+		return nil
+	}
+	p.Partials = append(p.Partials, partial)
+	return nil
+}
+
+// combine sets the signature of the transaction from the signatures of the
+// signers: recovered from the threshold key shares, or added if all the
+// signers must sign.
+func (p *MSProposal) combine() error {
+	if len(p.Partials) < p.Wallet.NumRequired {
+		return errors.New("", fmt.Sprintf("not enough signatures: %d out of %d required", len(p.Partials), p.Wallet.NumRequired))
+	}
+
+	var (
+		sig string
+		err error
+	)
+	if len(p.Wallet.SignerThresholdIDs) > 0 {
+		partials := p.Partials[:p.Wallet.NumRequired]
+		ids := make([]string, len(partials))
+		sigs := make([]string, len(partials))
+		for i, partial := range partials {
+			ids[i] = p.Wallet.SignerThresholdIDs[p.signerIndex(partial.PublicKey)]
+			sigs[i] = partial.Signature
+		}
+		sig, err = zcncrypto.RecoverThresholdSignature(ids, sigs)
+	} else {
+		sigs := make([]string, len(p.Partials))
+		for i, partial := range p.Partials {
+			sigs[i] = partial.Signature
+		}
+		sig, err = zcncrypto.AggregateSignatures(sigs...)
+	}
+	if err != nil {
+		return err
+	}
+
+	p.Envelope.Transaction.Signature = sig
+	if err := p.Envelope.VerifySignature(); err != nil {
+		p.Envelope.Transaction.Signature = ""
+		return errors.Wrap(err, "the combined signature doesn't match the multisig wallet")
+	}
+	return nil
+}
+
+func (p *MSProposal) signerIndex(publicKey string) int {
+	for i, pk := range p.Wallet.SignerPublicKeys {
+		if pk == publicKey {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *MSProposal) partialIndex(publicKey string) int {
+	for i, partial := range p.Partials {
+		if partial.PublicKey == publicKey {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *MSProposal) marshal() (string, error) {
+	buf, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
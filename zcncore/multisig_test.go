@@ -0,0 +1,121 @@
+//go:build !mobile
+// +build !mobile
+
+package zcncore
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+// newMSWallet creates a t-of-n multisig wallet, it returns the wallet and the
+// wallets of its signers.
+func newMSWallet(t *testing.T, threshold, n int) (string, []string) {
+	scheme := _config.chain.SignatureScheme
+	_config.chain.SignatureScheme = "bls0chain"
+	t.Cleanup(func() { _config.chain.SignatureScheme = scheme })
+
+	msw, _, wallets, err := CreateMSWallet(threshold, n)
+	require.NoError(t, err)
+	// the first wallet is the one of the group
+	return msw, wallets[1:]
+}
+
+func TestMSProposal(t *testing.T) {
+	msw, signers := newMSWallet(t, 2, 3)
+	var submitted *transaction.Transaction
+	mockSharder(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/"+transaction.TXN_SUBMIT_URL, r.URL.Path)
+		buf, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		submitted = &transaction.Transaction{}
+		require.NoError(t, json.Unmarshal(buf, submitted))
+		writeJSON(t, w, map[string]interface{}{"entity": submitted})
+	})
+
+	p, err := CreateMSTransferProposal(msw, "bob", 10, 1, 5)
+	require.NoError(t, err)
+
+	// the signers sign separately, their signatures are merged
+	p0, err := SignMSProposal(p, signers[0])
+	require.NoError(t, err)
+	_, err = CombineMSProposal(p0)
+	require.Error(t, err)
+	p2, err := SignMSProposal(p, signers[2])
+	require.NoError(t, err)
+	merged, err := MergeMSProposals(p0, p2)
+	require.NoError(t, err)
+
+	status, err := GetMSProposalStatus(merged)
+	require.NoError(t, err)
+	require.Equal(t, 2, status.Signed)
+	require.True(t, status.Ready)
+	require.Len(t, status.Pending, 1)
+
+	// the threshold signature recovered from any 2 signers is the one of the
+	// multisig wallet
+	envJSON, err := CombineMSProposal(merged)
+	require.NoError(t, err)
+	env, err := transaction.UnmarshalEnvelope([]byte(envJSON))
+	require.NoError(t, err)
+	require.NoError(t, env.VerifySignature())
+	require.Equal(t, "bob", env.Transaction.ToClientID)
+
+	hash, err := SubmitMSProposal(merged)
+	require.NoError(t, err)
+	require.Equal(t, env.Transaction.Hash, hash)
+	require.Equal(t, env.Transaction.Signature, submitted.Signature)
+}
+
+func TestMSProposalInvalidSignatures(t *testing.T) {
+	msw, signers := newMSWallet(t, 2, 3)
+	other, otherSigners := newMSWallet(t, 2, 3)
+	mockSharder(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	})
+
+	p, err := CreateMSTransferProposal(msw, "bob", 10, 1, 5)
+	require.NoError(t, err)
+
+	// not a signer of the multisig wallet
+	_, err = SignMSProposal(p, otherSigners[0])
+	require.Error(t, err)
+
+	// the signature of an unknown signer, or invalid, is rejected
+	signed, err := SignMSProposal(p, signers[0])
+	require.NoError(t, err)
+	tamper := func(f func(p *MSProposal)) string {
+		var raw MSProposal
+		require.NoError(t, json.Unmarshal([]byte(signed), &raw))
+		f(&raw)
+		buf, err := json.Marshal(&raw)
+		require.NoError(t, err)
+		return string(buf)
+	}
+	otherP, err := CreateMSTransferProposal(other, "bob", 10, 1, 5)
+	require.NoError(t, err)
+	otherSigned, err := SignMSProposal(otherP, otherSigners[0])
+	require.NoError(t, err)
+	var otherPartial MSProposal
+	require.NoError(t, json.Unmarshal([]byte(otherSigned), &otherPartial))
+
+	_, err = parseMSProposal(tamper(func(p *MSProposal) { p.Partials = otherPartial.Partials }))
+	require.Error(t, err)
+	_, err = parseMSProposal(tamper(func(p *MSProposal) { p.Partials[0].Signature = otherPartial.Partials[0].Signature }))
+	require.Error(t, err)
+	_, err = MergeMSProposals(signed, otherSigned)
+	require.Error(t, err)
+
+	// the transaction changed after the hash was computed
+	_, err = SignMSProposal(tamper(func(p *MSProposal) { p.Envelope.Transaction.ToClientID = "mallory" }), signers[1])
+	require.Error(t, err)
+	_, err = parseMSProposal(tamper(func(p *MSProposal) { p.Envelope.Transaction.Value = 1000 }))
+	require.Error(t, err)
+	_, err = parseMSProposal(tamper(func(p *MSProposal) { p.Envelope.Transaction.Hash = otherPartial.Envelope.Transaction.Hash }))
+	require.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package zcncore
+
+import (
+	"encoding/json"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/signer"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/core/zcncrypto"
+)
+
+// BuildUnsignedTxn builds the envelope of an unsigned smart contract transaction
+// of the wallet, to be signed offline with SignTxnEnvelope. The nonce must be
+// given, the fee is estimated if zero.
+func BuildUnsignedTxn(address, methodName string, input interface{}, value uint64, nonce int64, fee uint64) (string, error) {
+	if err := CheckConfig(); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(transaction.SmartContractTxnData{Name: methodName, InputArgs: input})
+	if err != nil {
+		return "", errors.Wrap(err, "create smart contract failed due to invalid data")
+	}
+
+	env, err := transaction.BuildUnsigned(transaction.UnsignedTxn{
+		ClientID:        _config.wallet.ClientID,
+		PublicKey:       _config.wallet.ClientKey,
+		ChainID:         _config.chain.ChainID,
+		SignatureScheme: _config.chain.SignatureScheme,
+		ToClientID:      address,
+		Value:           value,
+		TransactionType: transaction.TxnTypeSmartContract,
+		TransactionData: string(data),
+		Nonce:           nonce,
+		Fee:             fee,
+	}, _config.chain.Miners)
+	if err != nil {
+		return "", err
+	}
+	buf, err := env.Marshal()
+	return string(buf), err
+}
+
+// SignTxnEnvelope signs the transaction of the envelope with the private key.
+// It doesn't need the sdk to be initialized, so it can run on an offline
+// machine.
+func SignTxnEnvelope(envelope, privateKey string) (string, error) {
+	env, err := transaction.UnmarshalEnvelope([]byte(envelope))
+	if err != nil {
+		return "", err
+	}
+	scheme := zcncrypto.NewSignatureScheme(env.SignatureScheme)
+	if err := scheme.SetPrivateKey(privateKey); err != nil {
+		return "", err
+	}
+	if err := env.Sign(scheme); err != nil {
+		return "", err
+	}
+	buf, err := env.Marshal()
+	return string(buf), err
+}
+
+// SignTxnEnvelopeWith signs the transaction of the envelope with s, a remote
+// signer for example.
+func SignTxnEnvelopeWith(envelope string, s signer.Signer) (string, error) {
+	env, err := transaction.UnmarshalEnvelope([]byte(envelope))
+	if err != nil {
+		return "", err
+	}
+	if err := env.SignWith(s); err != nil {
+		return "", err
+	}
+	buf, err := env.Marshal()
+	return string(buf), err
+}
+
+// BroadcastTxnEnvelope sends the signed transaction of the envelope to the
+// miners and returns its hash, use Transaction.SetTransactionHash and
+// Transaction.Verify to wait for it.
+func BroadcastTxnEnvelope(envelope string) (string, error) {
+	if err := checkSdkInit(); err != nil {
+		return "", err
+	}
+	env, err := transaction.UnmarshalEnvelope([]byte(envelope))
+	if err != nil {
+		return "", err
+	}
+	return transaction.BroadcastEnvelope(env, GetStableMiners())
+}
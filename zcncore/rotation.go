@@ -0,0 +1,215 @@
+//go:build !mobile
+// +build !mobile
+
+package zcncore
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/common"
+	"github.com/0chain/gosdk/core/signer"
+	"github.com/0chain/gosdk/core/sys"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/zboxcore/sdk"
+)
+
+// rotationVerifyRetries is the number of times a rotation transaction is
+// looked up on the sharders before giving up.
+const rotationVerifyRetries = 10
+
+// RotateWalletKeys moves everything the wallet owns to newWallet, a compromised
+// wallet to a new one for example, and then uses newWallet. Both the wallet and
+// the storage sdk must be initialized with the old wallet.
+//
+// The allocations are transferred, the read pool, stake pools and vesting
+// pools are unlocked, the balance is sent to the new wallet, the read pool and
+// stake pools are locked again by the new wallet and the shares are reissued.
+// The vesting pools aren't re-created: their tokens stay in the balance of the
+// new wallet. The report lists every item with what happened to it; see
+// RotationReport.Failed for the ones left to handle.
+//   - newWallet: json of the new wallet
+//   - shares: the auth tickets to reissue
+//   - fee: fee of the transfer of the balance, estimated if zero
+func RotateWalletKeys(newWallet string, shares []sdk.RotationShare, fee uint64) (*sdk.RotationReport, error) {
+	if err := checkSdkInit(); err != nil {
+		return nil, err
+	}
+	if !_config.isValidWallet {
+		return nil, errors.New("", "wallet info not found. set wallet info")
+	}
+	w, err := getWallet(newWallet)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := sdk.NewKeyRotation(newWallet, shares)
+	if err != nil {
+		return nil, err
+	}
+	if r.Report.OldClientID != _config.wallet.ClientID {
+		return nil, errors.New("key_rotation", "the storage sdk and the wallet use different clients")
+	}
+	if err := planVestingRotation(r.Report); err != nil {
+		return nil, err
+	}
+
+	if err := r.Release(); err != nil {
+		return r.Report, err
+	}
+	for _, item := range r.Report.Items {
+		if item.Kind != sdk.RotationVesting {
+			continue
+		}
+		if item.Amount == 0 {
+			// nothing vested for the owner yet
+			item.Status = sdk.RotationPending
+			continue
+		}
+		hash, err := executeRotationTxn(VestingSmartContractAddress, transaction.TxnTypeSmartContract,
+			transaction.SmartContractTxnData{
+				Name:      transaction.VESTING_UNLOCK,
+				InputArgs: vestingRequest{PoolID: common.Key(item.ID)},
+			}, 0, 0, false)
+		item.Hash = hash
+		if err != nil {
+			item.Fail(err)
+			continue
+		}
+		item.Status = sdk.RotationUnlocked
+	}
+
+	item := r.Report.Add(sdk.RotationBalance, _config.wallet.ClientID, 0)
+	item.Hash, err = executeRotationTxn(w.ClientID, transaction.TxnTypeSend,
+		SendTxnData{Note: "key rotation"}, 0, fee, true)
+	if err != nil {
+		item.Fail(err)
+		return r.Report, err
+	}
+	item.Status = sdk.RotationTransferred
+
+	if err := r.Relock(); err != nil {
+		return r.Report, err
+	}
+	_config.signer = nil
+	return r.Report, SetWalletInfo(newWallet, _config.isSplitWallet)
+}
+
+// planVestingRotation adds the vesting pools of the wallet to the report, with
+// the amount the owner can unlock.
+func planVestingRotation(report *sdk.RotationReport) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultWaitSeconds*rotationVerifyRetries)
+	defer cancel()
+
+	buf, err := getFromSharders(ctx, WithParams(GET_VESTING_CLIENT_POOLS, Params{
+		"client_id": _config.wallet.ClientID,
+	}), false)
+	if err != nil {
+		return errors.Wrap(err, "key_rotation: failed to list the vesting pools")
+	}
+	var list VestingClientList
+	if err := json.Unmarshal(buf, &list); err != nil {
+		return errors.Wrap(err, "key_rotation: invalid vesting pools")
+	}
+
+	for _, poolID := range list.Pools {
+		buf, err := getFromSharders(ctx, WithParams(GET_VESTING_POOL_INFO, Params{
+			"pool_id": string(poolID),
+		}), false)
+		if err != nil {
+			return errors.Wrap(err, "key_rotation: failed to get the vesting pool "+string(poolID))
+		}
+		var info VestingPoolInfo
+		if err := json.Unmarshal(buf, &info); err != nil {
+			return errors.Wrap(err, "key_rotation: invalid vesting pool "+string(poolID))
+		}
+		report.Add(sdk.RotationVesting, string(poolID), info.Left)
+	}
+	return nil
+}
+
+// executeRotationTxn sends a transaction signed by the wallet and waits for it.
+// If sendAll is set, the value is the balance of the wallet minus the fee.
+func executeRotationTxn(toClientID string, txnType int, data interface{}, value, fee uint64, sendAll bool) (string, error) {
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	clientID := _config.wallet.ClientID
+	nonce, err := transaction.Cache.ReserveNextNonce(clientID)
+	if err != nil {
+		return "", err
+	}
+	env, err := transaction.BuildUnsigned(transaction.UnsignedTxn{
+		ClientID:        clientID,
+		PublicKey:       _config.wallet.ClientKey,
+		ChainID:         _config.chain.ChainID,
+		SignatureScheme: _config.chain.SignatureScheme,
+		ToClientID:      toClientID,
+		Value:           value,
+		TransactionType: txnType,
+		TransactionData: string(buf),
+		Nonce:           nonce,
+		Fee:             fee,
+	}, _config.chain.Miners)
+	if err != nil {
+		transaction.Cache.Release(clientID, nonce)
+		return "", err
+	}
+
+	txn := env.Transaction
+	if txn.TransactionFee == 0 {
+		if txn.TransactionFee, err = transaction.EstimateFee(txn, _config.chain.Miners, 0.2); err != nil {
+			transaction.Cache.Release(clientID, nonce)
+			return "", errors.Wrap(err, "key_rotation: failed to estimate the fee")
+		}
+	}
+	if sendAll {
+		balance, _, err := getBalanceFromSharders(clientID)
+		if err != nil {
+			transaction.Cache.Release(clientID, nonce)
+			return "", errors.Wrap(err, "failed to get the balance of "+clientID)
+		}
+		if balance <= int64(txn.TransactionFee) {
+			transaction.Cache.Release(clientID, nonce)
+			return "", errors.New("key_rotation", "the balance doesn't cover the fee")
+		}
+		txn.Value = uint64(balance) - txn.TransactionFee
+	}
+	txn.ComputeHashData()
+
+	s := _config.signer
+	if s == nil {
+		local, err := signer.NewLocalSigner(_config.chain.SignatureScheme, &_config.wallet)
+		if err != nil {
+			transaction.Cache.Release(clientID, nonce)
+			return "", err
+		}
+		s = local
+	}
+	if err := env.SignWith(s); err != nil {
+		transaction.Cache.Release(clientID, nonce)
+		return "", err
+	}
+	hash, err := transaction.BroadcastEnvelope(env, GetStableMiners())
+	if err != nil {
+		transaction.Cache.Release(clientID, nonce)
+		return "", err
+	}
+
+	for i := 0; i < rotationVerifyRetries; i++ {
+		sys.Sleep(defaultWaitSeconds)
+		t, err := transaction.VerifyTransaction(hash, _config.chain.Sharders)
+		if err != nil {
+			continue
+		}
+		transaction.Cache.Confirm(clientID, nonce)
+		if t.Status != transaction.TxnSuccess {
+			return hash, errors.New("key_rotation", t.TransactionOutput)
+		}
+		return hash, nil
+	}
+	transaction.Cache.Evict(clientID)
+	return hash, errors.New("key_rotation", "the transaction "+hash+" wasn't confirmed")
+}
@@ -0,0 +1,130 @@
+//go:build !mobile
+// +build !mobile
+
+package zcncore
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0chain/gosdk/core/common"
+	"github.com/0chain/gosdk/core/conf"
+	"github.com/0chain/gosdk/core/sys"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/0chain/gosdk/zboxcore/blockchain"
+	"github.com/0chain/gosdk/zboxcore/client"
+	"github.com/0chain/gosdk/zboxcore/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+// rotationNetwork is the block worker, miner and sharder of a client owning
+// two vesting pools and nothing else.
+type rotationNetwork struct {
+	mu      sync.Mutex
+	url     string
+	balance int64
+	txns    map[string]*transaction.Transaction
+	sent    []*transaction.Transaction
+}
+
+func (n *rotationNetwork) serve(t *testing.T) http.HandlerFunc {
+	storage := "/v1/screst/" + StorageSmartContractAddress
+	return func(w http.ResponseWriter, r *http.Request) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		var resp interface{}
+		switch r.URL.Path {
+		case "/network":
+			resp = map[string][]string{"miners": {n.url}, "sharders": {n.url}}
+		case storage + "/allocations":
+			resp = []interface{}{}
+		case storage + "/getReadPoolStat":
+			resp = map[string]int64{"balance": 0}
+		case storage + "/getUserStakePoolStat":
+			resp = map[string]interface{}{"pools": map[string]interface{}{}}
+		case GET_VESTING_CLIENT_POOLS:
+			resp = &VestingClientList{Pools: []common.Key{"vested", "unvested"}}
+		case GET_VESTING_POOL_INFO:
+			info := &VestingPoolInfo{ID: common.Key(r.URL.Query().Get("pool_id"))}
+			if info.ID == "vested" {
+				info.Left = 4
+			}
+			resp = info
+		case transaction.FEES_TABLE:
+			resp = map[string]map[string]int64{VestingSmartContractAddress: {transaction.VESTING_UNLOCK: 2}}
+		case strings.TrimSuffix(GET_BALANCE, "?client_id="):
+			resp = map[string]int64{"balance": n.balance, "nonce": 0}
+		case "/" + transaction.TXN_SUBMIT_URL:
+			txn := &transaction.Transaction{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(txn))
+			txn.Status = transaction.TxnSuccess
+			n.txns[txn.Hash] = txn
+			n.sent = append(n.sent, txn)
+			resp = txn
+		case "/" + strings.TrimSuffix(transaction.TXN_VERIFY_URL, "?hash="):
+			resp = map[string]interface{}{"txn": n.txns[r.URL.Query().Get("hash")]}
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(t, w, resp)
+	}
+}
+
+func TestRotateWalletKeys(t *testing.T) {
+	oldWallet, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	oldJSON, err := oldWallet.Marshal()
+	require.NoError(t, err)
+	newWallet, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	newJSON, err := newWallet.Marshal()
+	require.NoError(t, err)
+
+	network := &rotationNetwork{balance: 20, txns: make(map[string]*transaction.Transaction)}
+	mockSharder(t, network.serve(t))
+	network.url = _config.chain.Miners[0]
+	_config.chain.SignatureScheme = "bls0chain"
+	require.NoError(t, SetWalletInfo(oldJSON, false))
+
+	previous, err := json.Marshal(client.GetClient())
+	require.NoError(t, err)
+	miners, sharders, sleep := blockchain.GetMiners(), blockchain.GetSharders(), sys.Sleep
+	t.Cleanup(func() {
+		require.NoError(t, client.PopulateClient(string(previous), client.GetClient().SignatureScheme))
+		blockchain.SetMiners(miners)
+		blockchain.SetSharders(sharders)
+		sys.Sleep = sleep
+	})
+	sys.Sleep = func(time.Duration) {}
+	conf.InitClientConfig(&conf.Config{MinConfirmation: 50})
+	require.NoError(t, sdk.InitStorageSDK(oldJSON, network.url, "", "bls0chain", nil, 0, 1))
+	transaction.Cache.Set(oldWallet.ClientID, 0)
+
+	report, err := RotateWalletKeys(newJSON, nil, 1)
+	require.NoError(t, err)
+	require.Equal(t, newWallet.ClientID, _config.wallet.ClientID)
+	require.Equal(t, newWallet.ClientID, client.GetClientID())
+
+	// the vested tokens are unlocked, then the balance is sent
+	require.Len(t, network.sent, 2)
+	require.Equal(t, VestingSmartContractAddress, network.sent[0].ToClientID)
+	require.Equal(t, newWallet.ClientID, network.sent[1].ToClientID)
+	require.Equal(t, uint64(19), network.sent[1].Value)
+
+	// the vesting pools aren't re-created, the unvested one is left to unlock
+	statuses := make(map[string]string)
+	for _, item := range report.Failed() {
+		statuses[item.Kind+" "+item.ID] = item.Status
+	}
+	require.Equal(t, map[string]string{
+		"vesting_pool vested":   sdk.RotationUnlocked,
+		"vesting_pool unvested": sdk.RotationPending,
+	}, statuses)
+}
@@ -0,0 +1,536 @@
+//go:build !mobile
+// +build !mobile
+
+package zcncore
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/common"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/zboxcore/marker"
+	"github.com/0chain/gosdk/zboxcore/sdk"
+)
+
+// DefaultSubscriptionPollInterval is the time between two polls of the latest
+// finalized round by a Subscription.
+const DefaultSubscriptionPollInterval = 2 * time.Second
+
+// DefaultSubscriptionMaxRounds is the number of rounds a Subscription
+// processes at most per poll, so that resuming from an old cursor catches up
+// over several polls.
+const DefaultSubscriptionMaxRounds = 1000
+
+// EventType is the type of a subscription Event.
+type EventType string
+
+const (
+	// EventBalance the balance of the client changed.
+	EventBalance EventType = "balance"
+	// EventTransfer the client received tokens.
+	EventTransfer EventType = "transfer"
+	// EventAllocation a watched allocation was updated on chain.
+	EventAllocation EventType = "allocation"
+	// EventWriteMarker a blobber redeemed a new write marker of a watched
+	// allocation.
+	EventWriteMarker EventType = "write_marker"
+	// EventFile a file was added to a watched allocation.
+	EventFile EventType = "file"
+)
+
+// Cursor is the position of a Subscription, to resume it from.
+type Cursor struct {
+	// Round is the last finalized round fully processed.
+	Round int64 `json:"round"`
+	// FilesFrom is the creation date the files of the allocations without
+	// Files are reported from.
+	FilesFrom int64 `json:"files_from"`
+	// Files is the position of the files reported of each allocation.
+	Files map[string]FilesCursor `json:"files,omitempty"`
+}
+
+// FilesCursor is the position of the files reported of an allocation.
+type FilesCursor struct {
+	// From is the creation date of the last file reported, files created
+	// before are not reported.
+	From int64 `json:"from"`
+	// Seen are the lookup hashes of the files reported created at From.
+	Seen []string `json:"seen,omitempty"`
+}
+
+func (c *FilesCursor) seen(lookupHash string) bool {
+	for _, h := range c.Seen {
+		if h == lookupHash {
+			return true
+		}
+	}
+	return false
+}
+
+// files returns the position of the files of the allocation allocID.
+func (c *Cursor) files(allocID string) FilesCursor {
+	if f, ok := c.Files[allocID]; ok {
+		return f
+	}
+	return FilesCursor{From: c.FilesFrom}
+}
+
+// clone returns a copy of c not sharing the files positions.
+func (c Cursor) clone() Cursor {
+	if c.Files == nil {
+		return c
+	}
+	files := make(map[string]FilesCursor, len(c.Files))
+	for id, f := range c.Files {
+		f.Seen = append([]string(nil), f.Seen...)
+		files[id] = f
+	}
+	c.Files = files
+	return c
+}
+
+// Event is a change reported by a Subscription.
+type Event struct {
+	Type         EventType `json:"type"`
+	AllocationID string    `json:"allocation_id,omitempty"`
+	// Cursor is where to resume the subscription from to receive this event
+	// again, so events are delivered at least once.
+	Cursor Cursor `json:"cursor"`
+
+	// Balance is set on EventBalance.
+	Balance int64 `json:"balance,omitempty"`
+	// Transaction is set on EventTransfer, EventAllocation and
+	// EventWriteMarker.
+	Transaction *transaction.Transaction `json:"transaction,omitempty"`
+	// Allocation is set on EventAllocation.
+	Allocation *sdk.Allocation `json:"allocation,omitempty"`
+	// WriteMarker is set on EventWriteMarker.
+	WriteMarker *marker.WriteMarker `json:"write_marker,omitempty"`
+	// File is set on EventFile.
+	File *sdk.ORef `json:"file,omitempty"`
+}
+
+// SubscribeOptions configures a Subscription.
+type SubscribeOptions struct {
+	// ClientID is the client whose balance and transfers are watched, the
+	// wallet if empty.
+	ClientID string
+	// Allocations are the allocations watched. Their updates are read with the
+	// storage sdk, which must be initialized.
+	Allocations []string
+	// Cursor is the position to resume from, the latest finalized round if
+	// zero.
+	Cursor       Cursor
+	PollInterval time.Duration
+	// MaxRounds is the number of rounds processed at most per poll,
+	// DefaultSubscriptionMaxRounds if zero.
+	MaxRounds int64
+	// Buffer is the capacity of the events channel.
+	Buffer int
+}
+
+// Subscription delivers the changes of a client and of allocations. It asks
+// the sharders for the transactions of the client, and for the storage
+// transactions of the owners and blobbers of the watched allocations, in the
+// rounds finalized since the last poll: blocks are never downloaded.
+type Subscription struct {
+	// C receives the events, it is closed when the subscription stops.
+	C <-chan *Event
+
+	events      chan *Event
+	opts        SubscribeOptions
+	allocations map[string]bool
+	explorer    *Explorer
+
+	// getAllocation and recentRefs read the watched allocations, with the
+	// storage sdk by default.
+	getAllocation func(id string) (*sdk.Allocation, error)
+	recentRefs    func(alloc *sdk.Allocation, page int, from int64, limit int) ([]sdk.ORef, error)
+
+	mu      sync.Mutex
+	cursor  Cursor
+	err     error
+	balance int64
+	// clients are the owners and blobbers of the watched allocations, whose
+	// storage transactions are followed
+	clients map[string]bool
+}
+
+// Subscribe starts a Subscription, it runs until ctx is done.
+func Subscribe(ctx context.Context, opts SubscribeOptions) (*Subscription, error) {
+	if err := checkSdkInit(); err != nil {
+		return nil, err
+	}
+	if opts.ClientID == "" {
+		if err := checkWalletConfig(); err != nil {
+			return nil, err
+		}
+		opts.ClientID = _config.wallet.ClientID
+	}
+
+	s := newSubscription(opts)
+	if s.cursor.Round == 0 {
+		lfb, err := GetLatestFinalized(ctx, len(_config.chain.Sharders))
+		if err != nil {
+			return nil, err
+		}
+		s.cursor.Round = lfb.Round
+	}
+	if s.cursor.FilesFrom == 0 {
+		s.cursor.FilesFrom = int64(common.Now())
+	}
+
+	go s.run(ctx)
+	return s, nil
+}
+
+func newSubscription(opts SubscribeOptions) *Subscription {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultSubscriptionPollInterval
+	}
+	if opts.MaxRounds <= 0 {
+		opts.MaxRounds = DefaultSubscriptionMaxRounds
+	}
+
+	s := &Subscription{
+		events:        make(chan *Event, opts.Buffer),
+		opts:          opts,
+		allocations:   make(map[string]bool, len(opts.Allocations)),
+		explorer:      &Explorer{PageSize: DefaultExplorerPageSize},
+		getAllocation: sdk.GetAllocation,
+		recentRefs: func(alloc *sdk.Allocation, page int, from int64, limit int) ([]sdk.ORef, error) {
+			res, err := alloc.GetRecentlyAddedRefs(page, from, limit)
+			if err != nil {
+				return nil, err
+			}
+			return res.Refs, nil
+		},
+		cursor:  opts.Cursor.clone(),
+		balance: -1,
+	}
+	s.C = s.events
+	for _, id := range opts.Allocations {
+		s.allocations[id] = true
+	}
+	return s
+}
+
+// Cursor returns the position of the subscription.
+func (s *Subscription) Cursor() Cursor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cursor.clone()
+}
+
+// Err returns the reason the subscription stopped, once C is closed.
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *Subscription) run(ctx context.Context) {
+	defer close(s.events)
+
+	// report the current balance first, so that consumers start from it
+	if !s.checkBalance(ctx, s.Cursor()) {
+		s.stop(ctx.Err())
+		return
+	}
+
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				s.stop(ctx.Err())
+				return
+			}
+			logging.Error("subscription poll failed: ", err)
+		}
+		select {
+		case <-ctx.Done():
+			s.stop(ctx.Err())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Subscription) stop(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+// poll processes the rounds finalized since the cursor, MaxRounds at most.
+func (s *Subscription) poll(ctx context.Context) error {
+	lfb, err := GetLatestFinalized(ctx, len(_config.chain.Sharders))
+	if err != nil {
+		return err
+	}
+
+	from := s.Cursor().Round + 1
+	to := lfb.Round
+	if to-from+1 > s.opts.MaxRounds {
+		to = from + s.opts.MaxRounds - 1
+	}
+	if from > to {
+		return nil
+	}
+
+	txns, err := s.transactions(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	if !s.processTransactions(ctx, txns) {
+		return ctx.Err()
+	}
+	s.mu.Lock()
+	s.cursor.Round = to
+	s.mu.Unlock()
+	return nil
+}
+
+// transactions returns the transactions of the rounds from to to related to
+// the client or to the watched allocations, ordered by round.
+func (s *Subscription) transactions(ctx context.Context, from, to int64) ([]*ExplorerTransaction, error) {
+	clients, err := s.watchedClients()
+	if err != nil {
+		return nil, err
+	}
+	filters := []TransactionFilter{
+		{ClientID: s.opts.ClientID},
+		{ToClientID: s.opts.ClientID},
+	}
+	for client := range clients {
+		if client != s.opts.ClientID {
+			filters = append(filters, TransactionFilter{ClientID: client, ToClientID: StorageSmartContractAddress})
+		}
+	}
+
+	var txns []*ExplorerTransaction
+	seen := make(map[string]bool)
+	for _, filter := range filters {
+		filter.StartRound, filter.EndRound, filter.Sort = from, to, "asc"
+		it := s.explorer.Transactions(filter)
+		for it.Next(ctx) {
+			txn := it.Transaction()
+			if !seen[txn.Hash] {
+				seen[txn.Hash] = true
+				txns = append(txns, txn)
+			}
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(txns, func(i, j int) bool {
+		return txns[i].Round < txns[j].Round
+	})
+	return txns, nil
+}
+
+// watchedClients returns the owners and blobbers of the watched allocations.
+// It fails if an allocation can't be read, so that the rounds aren't processed
+// without its transactions.
+func (s *Subscription) watchedClients() (map[string]bool, error) {
+	s.mu.Lock()
+	clients := s.clients
+	s.mu.Unlock()
+	if clients != nil || len(s.allocations) == 0 {
+		return clients, nil
+	}
+
+	clients = make(map[string]bool)
+	for id := range s.allocations {
+		alloc, err := s.getAllocation(id)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the watched allocation "+id)
+		}
+		clients[alloc.Owner] = true
+		for _, b := range alloc.Blobbers {
+			clients[b.ID] = true
+		}
+	}
+	s.mu.Lock()
+	s.clients = clients
+	s.mu.Unlock()
+	return clients, nil
+}
+
+// processTransactions emits the events of txns, it returns false if ctx is
+// done.
+func (s *Subscription) processTransactions(ctx context.Context, txns []*ExplorerTransaction) bool {
+	var (
+		balanceChanged bool
+		balanceCursor  Cursor
+		changedAllocs  = make(map[string]bool)
+	)
+	for _, txn := range txns {
+		if txn.Status == transaction.TxnFail {
+			continue
+		}
+		cursor := s.Cursor()
+		cursor.Round = txn.Round - 1
+
+		if txn.ClientID == s.opts.ClientID || txn.ToClientID == s.opts.ClientID {
+			if !balanceChanged {
+				balanceChanged, balanceCursor = true, cursor
+			}
+		}
+		if txn.Status != transaction.TxnSuccess {
+			continue
+		}
+		if txn.ToClientID == s.opts.ClientID && txn.Value > 0 {
+			if !s.emit(ctx, &Event{Type: EventTransfer, Cursor: cursor, Transaction: &txn.Transaction}) {
+				return false
+			}
+		}
+
+		allocID, wm := s.allocationOf(&txn.Transaction)
+		if allocID == "" {
+			continue
+		}
+		if wm != nil {
+			if !s.emit(ctx, &Event{Type: EventWriteMarker, AllocationID: allocID, Cursor: cursor, Transaction: &txn.Transaction, WriteMarker: wm}) {
+				return false
+			}
+			if !s.emitFiles(ctx, allocID, cursor) {
+				return false
+			}
+			continue
+		}
+		if !changedAllocs[allocID] {
+			changedAllocs[allocID] = true
+			alloc, err := s.getAllocation(allocID)
+			if err != nil {
+				logging.Error("subscription get allocation failed: ", err)
+				continue
+			}
+			// the blobbers may have changed
+			s.mu.Lock()
+			s.clients = nil
+			s.mu.Unlock()
+			if !s.emit(ctx, &Event{Type: EventAllocation, AllocationID: allocID, Cursor: cursor, Transaction: &txn.Transaction, Allocation: alloc}) {
+				return false
+			}
+		}
+	}
+
+	if balanceChanged {
+		return s.checkBalance(ctx, balanceCursor)
+	}
+	return true
+}
+
+// allocationOf returns the watched allocation a storage smart contract
+// transaction is about, and the write marker it redeems if any.
+func (s *Subscription) allocationOf(txn *transaction.Transaction) (string, *marker.WriteMarker) {
+	if len(s.allocations) == 0 || txn.ToClientID != StorageSmartContractAddress {
+		return "", nil
+	}
+
+	var data struct {
+		Name  string `json:"name"`
+		Input struct {
+			WriteMarker  *marker.WriteMarker `json:"write_marker"`
+			AllocationID string              `json:"allocation_id"`
+			// ID is the allocation of an update_allocation_request, but
+			// the blobber or validator of other transactions
+			ID string `json:"id"`
+		} `json:"input"`
+	}
+	if err := json.Unmarshal([]byte(txn.TransactionData), &data); err != nil {
+		return "", nil
+	}
+
+	id := data.Input.AllocationID
+	switch {
+	case data.Input.WriteMarker != nil:
+		id = data.Input.WriteMarker.AllocationID
+	case data.Name == transaction.STORAGESC_UPDATE_ALLOCATION:
+		id = data.Input.ID
+	}
+	if !s.allocations[id] {
+		return "", nil
+	}
+	return id, data.Input.WriteMarker
+}
+
+// emitFiles reports the files added to the allocation since the cursor.
+func (s *Subscription) emitFiles(ctx context.Context, allocID string, cursor Cursor) bool {
+	alloc, err := s.getAllocation(allocID)
+	if err != nil {
+		logging.Error("subscription get allocation failed: ", err)
+		return true
+	}
+
+	from := cursor.files(allocID).From
+	const pageLimit = 100
+	for page := 1; ; page++ {
+		refs, err := s.recentRefs(alloc, page, from, pageLimit)
+		if err != nil {
+			logging.Error("subscription get recent refs failed: ", err)
+			return true
+		}
+		for i := range refs {
+			ref := &refs[i]
+			created := int64(ref.CreatedAt)
+			s.mu.Lock()
+			files := s.cursor.files(allocID)
+			if created < files.From || (created == files.From && files.seen(ref.LookupHash)) {
+				s.mu.Unlock()
+				continue
+			}
+			if created > files.From {
+				files = FilesCursor{From: created}
+			}
+			files.Seen = append(files.Seen, ref.LookupHash)
+			if s.cursor.Files == nil {
+				s.cursor.Files = make(map[string]FilesCursor)
+			}
+			s.cursor.Files[allocID] = files
+			s.mu.Unlock()
+
+			if !s.emit(ctx, &Event{Type: EventFile, AllocationID: allocID, Cursor: cursor, File: ref}) {
+				return false
+			}
+		}
+		if len(refs) < pageLimit {
+			return true
+		}
+	}
+}
+
+// checkBalance emits an EventBalance if the balance changed, it returns false
+// if ctx is done.
+func (s *Subscription) checkBalance(ctx context.Context, cursor Cursor) bool {
+	balance, _, err := getBalanceFromSharders(s.opts.ClientID)
+	if err != nil {
+		logging.Error("subscription get balance failed: ", err)
+		return ctx.Err() == nil
+	}
+	s.mu.Lock()
+	changed := balance != s.balance
+	s.balance = balance
+	s.mu.Unlock()
+	if !changed {
+		return true
+	}
+	return s.emit(ctx, &Event{Type: EventBalance, Cursor: cursor, Balance: balance})
+}
+
+func (s *Subscription) emit(ctx context.Context, e *Event) bool {
+	select {
+	case s.events <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,297 @@
+//go:build !mobile
+// +build !mobile
+
+package zcncore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/0chain/gosdk/core/block"
+	"github.com/0chain/gosdk/core/common"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/zboxcore/blockchain"
+	"github.com/0chain/gosdk/zboxcore/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+type sharderTxn struct {
+	Hash            string `json:"hash"`
+	Round           int64  `json:"round"`
+	ClientID        string `json:"client_id"`
+	ToClientID      string `json:"to_client_id"`
+	TransactionData string `json:"transaction_data"`
+	Value           int64  `json:"value"`
+	Status          int    `json:"status"`
+}
+
+// fakeChain is a sharder indexing txns.
+type fakeChain struct {
+	mu      sync.Mutex
+	round   int64
+	balance int64
+	txns    []sharderTxn
+	queries []string
+}
+
+func (c *fakeChain) serve(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		switch {
+		case r.URL.Path == GET_LATEST_FINALIZED:
+			writeJSON(t, w, &block.Header{Hash: "lfb", Round: c.round})
+		case strings.HasPrefix(GET_BALANCE, r.URL.Path+"?"):
+			writeJSON(t, w, map[string]int64{"balance": c.balance, "nonce": 1})
+		case r.URL.Path == STORAGESC_GET_TRANSACTIONS:
+			q := r.URL.Query()
+			c.queries = append(c.queries, q.Get("client_id")+">"+q.Get("to_client_id")+"@"+q.Get("start")+"-"+q.Get("end"))
+			var match []sharderTxn
+			for _, txn := range c.txns {
+				if (q.Get("client_id") == "" || txn.ClientID == q.Get("client_id")) &&
+					(q.Get("to_client_id") == "" || txn.ToClientID == q.Get("to_client_id")) &&
+					txn.Round >= queryInt(r, "start") && txn.Round <= queryInt(r, "end") {
+					match = append(match, txn)
+				}
+			}
+			page := []sharderTxn{}
+			for i := queryInt(r, "offset"); i < int64(len(match)) && i < queryInt(r, "offset")+queryInt(r, "limit"); i++ {
+				page = append(page, match[i])
+			}
+			writeJSON(t, w, page)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+// set moves the chain to round, with the balance of the client.
+func (c *fakeChain) set(round, balance int64) {
+	c.mu.Lock()
+	c.round, c.balance, c.queries = round, balance, nil
+	c.mu.Unlock()
+}
+
+func drainEvents(s *Subscription) []*Event {
+	var events []*Event
+	for {
+		select {
+		case e := <-s.events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func scTxnData(t *testing.T, name string, input interface{}) string {
+	buf, err := json.Marshal(map[string]interface{}{"name": name, "input": input})
+	require.NoError(t, err)
+	return string(buf)
+}
+
+func TestSubscriptionResume(t *testing.T) {
+	chain := &fakeChain{round: 250, balance: 10, txns: []sharderTxn{
+		{Hash: "t1", Round: 150, ClientID: "bob", ToClientID: "alice", Value: 5, Status: transaction.TxnSuccess},
+		{Hash: "t2", Round: 160, ClientID: "bob", ToClientID: "alice", Value: 7, Status: transaction.TxnFail},
+		{Hash: "t3", Round: 220, ClientID: "alice", ToClientID: "carol", Value: 3, Status: transaction.TxnSuccess},
+	}}
+	mockSharder(t, chain.serve(t))
+
+	s := newSubscription(SubscribeOptions{ClientID: "alice", Cursor: Cursor{Round: 100}, MaxRounds: 100, Buffer: 10})
+	require.True(t, s.checkBalance(context.Background(), s.Cursor()))
+	require.Len(t, drainEvents(s), 1)
+
+	// the rounds are processed 100 at most per poll
+	chain.set(250, 15)
+	require.NoError(t, s.poll(context.Background()))
+	require.Equal(t, int64(200), s.Cursor().Round)
+	events := drainEvents(s)
+	require.Len(t, events, 2)
+	require.Equal(t, EventTransfer, events[0].Type)
+	require.Equal(t, "t1", events[0].Transaction.Hash)
+	require.Equal(t, uint64(5), events[0].Transaction.Value)
+	require.Equal(t, int64(149), events[0].Cursor.Round)
+	require.Equal(t, EventBalance, events[1].Type)
+	require.Equal(t, int64(15), events[1].Balance)
+	require.Equal(t, []string{"alice>@101-200", ">alice@101-200"}, chain.queries)
+
+	// resumed from the cursor of an event, the event is received again
+	r := newSubscription(SubscribeOptions{ClientID: "alice", Cursor: events[0].Cursor, MaxRounds: 100, Buffer: 10})
+	r.balance = 15
+	chain.set(200, 15)
+	require.NoError(t, r.poll(context.Background()))
+	require.Equal(t, "t1", drainEvents(r)[0].Transaction.Hash)
+
+	chain.set(250, 12)
+	require.NoError(t, s.poll(context.Background()))
+	require.Equal(t, int64(250), s.Cursor().Round)
+	events = drainEvents(s)
+	require.Len(t, events, 1)
+	require.Equal(t, EventBalance, events[0].Type)
+	require.Equal(t, int64(219), events[0].Cursor.Round)
+	require.Equal(t, []string{"alice>@201-250", ">alice@201-250"}, chain.queries)
+
+	// nothing new
+	require.NoError(t, s.poll(context.Background()))
+	require.Empty(t, drainEvents(s))
+}
+
+func TestSubscriptionAllocations(t *testing.T) {
+	wm := func(allocID string) map[string]interface{} {
+		return map[string]interface{}{"write_marker": map[string]interface{}{"allocation_id": allocID, "allocation_root": "root"}}
+	}
+	chain := &fakeChain{round: 105, txns: []sharderTxn{
+		{Hash: "c1", Round: 101, ClientID: "blob1", ToClientID: StorageSmartContractAddress,
+			TransactionData: scTxnData(t, "commit_connection", wm("alloc1")), Status: transaction.TxnSuccess},
+		// another allocation of the same blobber
+		{Hash: "c2", Round: 102, ClientID: "blob1", ToClientID: StorageSmartContractAddress,
+			TransactionData: scTxnData(t, "commit_connection", wm("alloc2")), Status: transaction.TxnSuccess},
+		// the id isn't an allocation id outside of update_allocation_request
+		{Hash: "s1", Round: 102, ClientID: "owner", ToClientID: StorageSmartContractAddress,
+			TransactionData: scTxnData(t, "stake_pool_lock", map[string]string{"id": "alloc1"}), Status: transaction.TxnSuccess},
+		{Hash: "u1", Round: 103, ClientID: "owner", ToClientID: StorageSmartContractAddress,
+			TransactionData: scTxnData(t, transaction.STORAGESC_UPDATE_ALLOCATION, map[string]string{"id": "alloc1"}), Status: transaction.TxnSuccess},
+		{Hash: "c3", Round: 104, ClientID: "blob1", ToClientID: StorageSmartContractAddress,
+			TransactionData: scTxnData(t, "commit_connection", wm("alloc1")), Status: transaction.TxnSuccess},
+	}}
+	mockSharder(t, chain.serve(t))
+
+	ref := func(hash string, created int64) sdk.ORef {
+		r := sdk.ORef{CreatedAt: common.Timestamp(created)}
+		r.LookupHash = hash
+		return r
+	}
+	var refCalls int
+	s := newSubscription(SubscribeOptions{ClientID: "alice", Allocations: []string{"alloc1"}, Cursor: Cursor{Round: 100, FilesFrom: 10}, Buffer: 20})
+	s.balance = 0
+	s.getAllocation = func(id string) (*sdk.Allocation, error) {
+		return &sdk.Allocation{ID: id, Owner: "owner", Blobbers: []*blockchain.StorageNode{{ID: "blob1"}}}, nil
+	}
+	s.recentRefs = func(_ *sdk.Allocation, page int, from int64, _ int) ([]sdk.ORef, error) {
+		refCalls++
+		if refCalls == 1 {
+			return []sdk.ORef{ref("a", 10), ref("b", 10)}, nil
+		}
+		// the files already reported are returned again
+		return []sdk.ORef{ref("a", 10), ref("b", 10), ref("c", 10), ref("d", 11)}, nil
+	}
+
+	require.NoError(t, s.poll(context.Background()))
+	require.ElementsMatch(t, []string{"alice>@101-105", ">alice@101-105", "owner>" + StorageSmartContractAddress + "@101-105",
+		"blob1>" + StorageSmartContractAddress + "@101-105"}, chain.queries)
+
+	var got []string
+	for _, e := range drainEvents(s) {
+		require.Equal(t, "alloc1", e.AllocationID)
+		switch e.Type {
+		case EventFile:
+			got = append(got, "file "+e.File.LookupHash)
+		default:
+			got = append(got, string(e.Type)+" "+e.Transaction.Hash)
+		}
+	}
+	require.Equal(t, []string{
+		"write_marker c1", "file a", "file b",
+		"allocation u1",
+		"write_marker c3", "file c", "file d",
+	}, got)
+	require.Equal(t, FilesCursor{From: 11, Seen: []string{"d"}}, s.Cursor().Files["alloc1"])
+	require.Equal(t, int64(10), s.Cursor().FilesFrom)
+}
+
+func TestSubscriptionFilesPerAllocation(t *testing.T) {
+	wm := func(allocID string) string {
+		return scTxnData(t, "commit_connection", map[string]interface{}{"write_marker": map[string]interface{}{"allocation_id": allocID}})
+	}
+	chain := &fakeChain{round: 103, txns: []sharderTxn{
+		{Hash: "c1", Round: 101, ClientID: "blob1", ToClientID: StorageSmartContractAddress, TransactionData: wm("alloc1"), Status: transaction.TxnSuccess},
+		{Hash: "c2", Round: 102, ClientID: "blob1", ToClientID: StorageSmartContractAddress, TransactionData: wm("alloc2"), Status: transaction.TxnSuccess},
+	}}
+	mockSharder(t, chain.serve(t))
+
+	ref := func(hash string, created int64) sdk.ORef {
+		r := sdk.ORef{CreatedAt: common.Timestamp(created)}
+		r.LookupHash = hash
+		return r
+	}
+	// alloc1 gets a newer file than the one added to alloc2 meanwhile
+	refs := map[string][]sdk.ORef{
+		"alloc1": {ref("a", 10), ref("b", 20)},
+		"alloc2": {ref("a", 10), ref("c", 15)},
+	}
+	newSub := func(cursor Cursor) *Subscription {
+		s := newSubscription(SubscribeOptions{ClientID: "alice", Allocations: []string{"alloc1", "alloc2"}, Cursor: cursor, Buffer: 20})
+		s.balance = 0
+		s.getAllocation = func(id string) (*sdk.Allocation, error) {
+			return &sdk.Allocation{ID: id, Owner: "owner", Blobbers: []*blockchain.StorageNode{{ID: "blob1"}}}, nil
+		}
+		s.recentRefs = func(alloc *sdk.Allocation, _ int, from int64, _ int) ([]sdk.ORef, error) {
+			var res []sdk.ORef
+			for _, r := range refs[alloc.ID] {
+				if int64(r.CreatedAt) >= from {
+					res = append(res, r)
+				}
+			}
+			return res, nil
+		}
+		return s
+	}
+	files := func(s *Subscription) []string {
+		var got []string
+		for _, e := range drainEvents(s) {
+			if e.Type == EventFile {
+				got = append(got, e.AllocationID+" "+e.File.LookupHash)
+			}
+		}
+		return got
+	}
+
+	s := newSub(Cursor{Round: 100, FilesFrom: 10})
+	require.NoError(t, s.poll(context.Background()))
+	require.Equal(t, []string{"alloc1 a", "alloc1 b", "alloc2 a", "alloc2 c"}, files(s))
+	cursor := s.Cursor()
+	require.Equal(t, FilesCursor{From: 20, Seen: []string{"b"}}, cursor.Files["alloc1"])
+	require.Equal(t, FilesCursor{From: 15, Seen: []string{"c"}}, cursor.Files["alloc2"])
+
+	// resumed from the cursor, the files reported aren't reported again
+	buf, err := json.Marshal(cursor)
+	require.NoError(t, err)
+	var resumed Cursor
+	require.NoError(t, json.Unmarshal(buf, &resumed))
+	resumed.Round = 100
+	r := newSub(resumed)
+	require.NoError(t, r.poll(context.Background()))
+	require.Empty(t, files(r))
+}
+
+func TestSubscriptionAllocationUnavailable(t *testing.T) {
+	chain := &fakeChain{round: 105}
+	mockSharder(t, chain.serve(t))
+
+	s := newSubscription(SubscribeOptions{ClientID: "alice", Allocations: []string{"alloc1"}, Cursor: Cursor{Round: 100}, Buffer: 10})
+	s.balance = 0
+	fail := true
+	s.getAllocation = func(id string) (*sdk.Allocation, error) {
+		if fail {
+			return nil, errors.New("unavailable")
+		}
+		return &sdk.Allocation{ID: id, Owner: "owner", Blobbers: []*blockchain.StorageNode{{ID: "blob1"}}}, nil
+	}
+
+	// the rounds aren't processed without the transactions of the allocation
+	require.Error(t, s.poll(context.Background()))
+	require.Equal(t, int64(100), s.Cursor().Round)
+	require.Empty(t, chain.queries)
+
+	fail = false
+	require.NoError(t, s.poll(context.Background()))
+	require.Equal(t, int64(105), s.Cursor().Round)
+	require.Contains(t, chain.queries, "owner>"+StorageSmartContractAddress+"@101-105")
+}
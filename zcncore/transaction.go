@@ -16,6 +16,7 @@ import (
 	"github.com/0chain/gosdk/core/block"
 	"github.com/0chain/gosdk/core/common"
 	"github.com/0chain/gosdk/core/encryption"
+	"github.com/0chain/gosdk/core/telemetry"
 	"github.com/0chain/gosdk/core/transaction"
 	"github.com/0chain/gosdk/core/util"
 )
@@ -276,6 +277,22 @@ func (t *Transaction) ExecuteSmartContract(address, methodName string, input int
 	return t.txn, nil
 }
 
+// PercentileFeeStrategy returns a fee strategy using the given percentile,
+// between 0 and 1, of the fees of the recent transactions from GetFeeStats.
+func PercentileFeeStrategy(percentile float64) transaction.FeeStrategy {
+	return transaction.PercentileFee(func(ctx context.Context) (*transaction.FeeStats, error) {
+		stats, err := GetFeeStats(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &transaction.FeeStats{
+			Min:  uint64(stats.MinFees),
+			Mean: uint64(stats.MeanFees),
+			Max:  uint64(stats.MaxFees),
+		}, nil
+	}, percentile)
+}
+
 func (t *Transaction) Send(toClientID string, val uint64, desc string) error {
 	txnData, err := json.Marshal(transaction.SmartContractTxnData{Name: "transfer", InputArgs: SendTxnData{Note: desc}})
 	if err != nil {
@@ -786,13 +803,10 @@ func (t *Transaction) RegisterMultiSig(walletstr string, mswallet string) error
 		t.txn.ToClientID = MultiSigSmartContractAddress
 		t.txn.TransactionData = string(snBytes)
 		t.txn.Value = 0
-		nonce := t.txn.TransactionNonce
-		if nonce < 1 {
-			nonce = transaction.Cache.GetNextNonce(t.txn.ClientID)
-		} else {
-			transaction.Cache.Set(t.txn.ClientID, nonce)
+		if err := t.setNonce(); err != nil {
+			t.completeTxn(StatusError, "", err)
+			return
 		}
-		t.txn.TransactionNonce = nonce
 
 		if t.txn.TransactionFee == 0 {
 			fee, err := transaction.EstimateFee(t.txn, _config.chain.Miners, 0.2)
@@ -850,13 +864,10 @@ func (t *Transaction) RegisterVote(signerwalletstr string, msvstr string) error
 		t.txn.ToClientID = MultiSigSmartContractAddress
 		t.txn.TransactionData = string(snBytes)
 		t.txn.Value = 0
-		nonce := t.txn.TransactionNonce
-		if nonce < 1 {
-			nonce = transaction.Cache.GetNextNonce(t.txn.ClientID)
-		} else {
-			transaction.Cache.Set(t.txn.ClientID, nonce)
+		if err := t.setNonce(); err != nil {
+			t.completeTxn(StatusError, "", err)
+			return
 		}
-		t.txn.TransactionNonce = nonce
 
 		if t.txn.TransactionFee == 0 {
 			fee, err := transaction.EstimateFee(t.txn, _config.chain.Miners, 0.2)
@@ -970,9 +981,16 @@ func (t *Transaction) Verify() error {
 		return err
 	}
 
+	ctx, span := telemetry.StartSpan(context.Background(), "txn.verify")
+	span.SetAttribute("txn.hash", t.txnHash)
+	t.verifySpan = span
+
 	go func() {
 
-		for {
+		for attempt := 1; ; attempt++ {
+			if attempt > 1 {
+				telemetry.RecordRetry(ctx, telemetry.Retry{Operation: "txn.verify", Attempt: attempt})
+			}
 
 			tq.Reset()
 			// Get transaction confirmationBlock from a random sharder
@@ -1003,6 +1021,10 @@ func (t *Transaction) Verify() error {
 
 					// it is expired
 					if t.isTransactionExpired(lfbBlockHeader.getCreationDate(now), now) {
+						if t.feeBump != nil {
+							t.verifyWithFeeBump(ctx)
+							return
+						}
 						t.completeVerify(StatusError, "", errors.New("", `{"error": "verify transaction failed"}`))
 						return
 					}
@@ -6,17 +6,22 @@ import (
 	stdErrors "errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"sync/atomic"
 	"time"
 
 	"github.com/0chain/errors"
 	"github.com/0chain/gosdk/core/common"
 	"github.com/0chain/gosdk/core/sys"
+	"github.com/0chain/gosdk/core/telemetry"
 	"github.com/0chain/gosdk/zboxcore/logger"
 	"go.uber.org/zap"
 
 	"github.com/0chain/gosdk/core/conf"
 	"github.com/0chain/gosdk/core/encryption"
+	"github.com/0chain/gosdk/core/health"
+	"github.com/0chain/gosdk/core/resty"
+	"github.com/0chain/gosdk/core/signer"
 	"github.com/0chain/gosdk/core/transaction"
 	"github.com/0chain/gosdk/core/util"
 	"github.com/0chain/gosdk/core/version"
@@ -98,6 +103,10 @@ type localConfig struct {
 	isConfigured  bool
 	isValidWallet bool
 	isSplitWallet bool
+	// signer signs instead of the private keys of wallet if set
+	signer signer.Signer
+	// walletFromSigner is set if wallet is the client of signer, it has no keys
+	walletFromSigner bool
 }
 
 type ChainConfig struct {
@@ -207,6 +216,10 @@ type Transaction struct {
 	verifyConfirmationStatus int
 	verifyOut                string
 	verifyError              error
+	submitSpan               telemetry.Span
+	verifySpan               telemetry.Span
+	feeBump                  *transaction.FeeBumper
+	feeBumpResult            *transaction.FeeBumpResult
 }
 
 type SendTxnData struct {
@@ -214,6 +227,9 @@ type SendTxnData struct {
 }
 
 func Sign(hash string) (string, error) {
+	if _config.signer != nil {
+		return _config.signer.Sign(hash)
+	}
 	sigScheme := zcncrypto.NewSignatureScheme(_config.chain.SignatureScheme)
 	err := sigScheme.SetPrivateKey(_config.wallet.Keys[0].PrivateKey)
 	if err != nil {
@@ -223,6 +239,9 @@ func Sign(hash string) (string, error) {
 }
 
 var SignFn = func(hash string) (string, error) {
+	if _config.signer != nil {
+		return _config.signer.Sign(hash)
+	}
 	sigScheme := zcncrypto.NewSignatureScheme(_config.chain.SignatureScheme)
 	err := sigScheme.SetPrivateKey(_config.wallet.Keys[0].PrivateKey)
 	if err != nil {
@@ -273,6 +292,10 @@ func (t *Transaction) completeTxn(status int, out string, err error) {
 	t.txnStatus = status
 	t.txnOut = out
 	t.txnError = err
+	if t.submitSpan != nil {
+		t.submitSpan.End(err)
+		t.submitSpan = nil
+	}
 	if t.txnCb != nil {
 		t.txnCb.OnTransactionComplete(t, t.txnStatus)
 	}
@@ -287,8 +310,14 @@ func (t *Transaction) completeVerifyWithConStatus(status int, conStatus int, out
 	t.verifyConfirmationStatus = conStatus
 	t.verifyOut = out
 	t.verifyError = err
+	if t.verifySpan != nil {
+		t.verifySpan.End(err)
+		t.verifySpan = nil
+	}
 	if status == StatusError {
 		transaction.Cache.Evict(t.txn.ClientID)
+	} else if status == StatusSuccess {
+		transaction.Cache.Confirm(t.txn.ClientID, t.txn.TransactionNonce)
 	}
 	if t.txnCb != nil {
 		t.txnCb.OnVerifyComplete(t, t.verifyStatus)
@@ -309,18 +338,25 @@ func (g getNonceCallBack) OnNonceAvailable(status int, nonce int64, info string)
 }
 
 func (t *Transaction) setNonceAndSubmit() {
-	t.setNonce()
+	if err := t.setNonce(); err != nil {
+		t.completeTxn(StatusError, "", err)
+		return
+	}
 	t.submitTxn()
 }
 
-func (t *Transaction) setNonce() {
+func (t *Transaction) setNonce() error {
 	nonce := t.txn.TransactionNonce
 	if nonce < 1 {
-		nonce = transaction.Cache.GetNextNonce(t.txn.ClientID)
+		var err error
+		if nonce, err = transaction.Cache.ReserveNextNonce(t.txn.ClientID); err != nil {
+			return err
+		}
 	} else {
 		transaction.Cache.Set(t.txn.ClientID, nonce)
 	}
 	t.txn.TransactionNonce = nonce
+	return nil
 }
 
 func (t *Transaction) submitTxn() {
@@ -329,12 +365,16 @@ func (t *Transaction) submitTxn() {
 	t.txnOut = ""
 	t.txnError = nil
 
+	ctx, span := telemetry.StartSpan(context.Background(), "txn.submit")
+	span.SetAttribute("txn.type", txnTypeString(t.txn.TransactionType))
+	t.submitSpan = span
+
 	// If Signature is not passed compute signature
 	if t.txn.Signature == "" {
 		err := t.txn.ComputeHashAndSign(SignFn)
 		if err != nil {
 			t.completeTxn(StatusError, "", err)
-			transaction.Cache.Evict(t.txn.ClientID)
+			transaction.Cache.Release(t.txn.ClientID, t.txn.TransactionNonce)
 			return
 		}
 	}
@@ -349,6 +389,10 @@ func (t *Transaction) submitTxn() {
 
 	for _, miner := range randomMiners {
 		go func(minerurl string) {
+			var node string
+			if u, err := url.Parse(minerurl); err == nil {
+				node = resty.NodeOf(u)
+			}
 			url := minerurl + PUT_TRANSACTION
 			logging.Info("Submitting ", txnTypeString(t.txn.TransactionType), " transaction to ", minerurl, " with JSON ", string(t.txn.DebugJSON()))
 			req, err := util.NewHTTPPostRequest(url, t.txn)
@@ -361,7 +405,33 @@ func (t *Transaction) submitTxn() {
 				return
 			}
 
+			if err := resty.DefaultCircuitBreaker.Allow(node); err != nil {
+				logging.Error(minerurl, " submit transaction skipped. ", err.Error())
+				if int(atomic.AddInt32(&failedCount, 1)) == minersN {
+					close(failC)
+				}
+				return
+			}
+
+			start := time.Now()
 			res, err := req.Post()
+			r := telemetry.Request{
+				Kind:    telemetry.KindMiner,
+				Method:  http.MethodPost,
+				Host:    minerurl,
+				Path:    PUT_TRANSACTION,
+				Start:   start,
+				Attempt: 1,
+				Err:     err,
+			}
+			if res != nil {
+				r.StatusCode = res.StatusCode
+				r.BytesReceived = int64(len(res.Body))
+			}
+			r.Duration = time.Since(start)
+			resty.DefaultCircuitBreaker.Record(node, r.StatusCode, err)
+			health.Default.Record(telemetry.KindMiner, node, r.Duration, r.StatusCode, err)
+			telemetry.RecordRequest(ctx, r)
 			if err != nil {
 				logging.Error(minerurl, " submit transaction error. ", err.Error())
 				if int(atomic.AddInt32(&failedCount, 1)) == minersN {
@@ -386,7 +456,7 @@ func (t *Transaction) submitTxn() {
 	case <-failC:
 		logging.Error("failed to submit transaction")
 		t.completeTxn(StatusError, "", fmt.Errorf("failed to submit transaction to all miners"))
-		transaction.Cache.Evict(t.txn.ClientID)
+		transaction.Cache.Release(t.txn.ClientID, t.txn.TransactionNonce)
 		ResetStableMiners()
 		return
 	case ret := <-resultC:
@@ -395,7 +465,7 @@ func (t *Transaction) submitTxn() {
 			t.completeTxn(StatusSuccess, ret.Body, nil)
 		} else {
 			t.completeTxn(StatusError, "", fmt.Errorf("submit transaction failed. %s", ret.Body))
-			transaction.Cache.Evict(t.txn.ClientID)
+			transaction.Cache.Release(t.txn.ClientID, t.txn.TransactionNonce)
 		}
 	}
 }
@@ -440,6 +510,8 @@ type txnFeeOption struct {
 	// estimate the txn fee by calling API from 0chain network. With this option, we could force
 	// the txn to have zero fee for those exempt transactions.
 	noEstimateFee bool
+	// strategy computes the fee instead of the fees table of the miners.
+	strategy transaction.FeeStrategy
 }
 
 // FeeOption represents txn fee related option type
@@ -452,6 +524,13 @@ func WithNoEstimateFee() FeeOption {
 	}
 }
 
+// WithFeeStrategy computes the txn fee with strategy instead of the fees table
+func WithFeeStrategy(strategy transaction.FeeStrategy) FeeOption {
+	return func(o *txnFeeOption) {
+		o.strategy = strategy
+	}
+}
+
 func (t *Transaction) createSmartContractTxn(address, methodName string, input interface{}, value uint64, opts ...FeeOption) error {
 	sn := transaction.SmartContractTxnData{Name: methodName, InputArgs: input}
 	snBytes, err := json.Marshal(sn)
@@ -477,8 +556,13 @@ func (t *Transaction) createSmartContractTxn(address, methodName string, input i
 		return nil
 	}
 
+	strategy := tf.strategy
+	if strategy == nil {
+		strategy = transaction.TableFee(_config.chain.Miners)
+	}
+
 	// TODO: check if transaction is exempt to avoid unnecessary fee estimation
-	minFee, err := transaction.EstimateFee(t.txn, _config.chain.Miners, 0.2)
+	minFee, err := strategy.Fee(context.Background(), t.txn)
 	if err != nil {
 		logger.Logger.Error("failed estimate txn fee",
 			zap.Any("txn", t.txn.Hash),
@@ -511,13 +595,10 @@ func (t *Transaction) ExecuteFaucetSCWallet(walletStr string, methodName string,
 		return err
 	}
 	go func() {
-		nonce := t.txn.TransactionNonce
-		if nonce < 1 {
-			nonce = transaction.Cache.GetNextNonce(t.txn.ClientID)
-		} else {
-			transaction.Cache.Set(t.txn.ClientID, nonce)
+		if err := t.setNonce(); err != nil {
+			t.completeTxn(StatusError, "", err)
+			return
 		}
-		t.txn.TransactionNonce = nonce
 		err = t.txn.ComputeHashAndSignWithWallet(signWithWallet, w)
 		if err != nil {
 			return
@@ -759,6 +840,57 @@ func (t *Transaction) isTransactionExpired(lfbCreationTime, currentTime int64) b
 	sys.Sleep(defaultWaitSeconds)
 	return false
 }
+
+// SetFeeBump makes Verify replace the transaction, when it expires before
+// being confirmed, with copies with the same nonce and a fee raised by
+// multiplier, capped to maxFee if not zero. attempts is the number of
+// versions sent, the expired one included.
+func (t *Transaction) SetFeeBump(attempts int, multiplier float64, maxFee uint64) {
+	b := transaction.NewFeeBumper(nil, nil, SignFn)
+	if attempts > 0 {
+		b.Attempts = attempts
+	}
+	if multiplier > 1 {
+		b.Multiplier = multiplier
+	}
+	b.MaxFee = maxFee
+	t.feeBump = b
+}
+
+// GetFeeBumpResult returns the versions sent by the fee bump of Verify and the
+// one confirmed, nil if the fee wasn't bumped.
+func (t *Transaction) GetFeeBumpResult() *transaction.FeeBumpResult {
+	return t.feeBumpResult
+}
+
+// verifyWithFeeBump completes the verification of an expired transaction by
+// bumping its fee.
+func (t *Transaction) verifyWithFeeBump(ctx context.Context) {
+	t.feeBump.Miners = GetStableMiners()
+	t.feeBump.Sharders = _config.chain.Sharders
+	res, err := t.feeBump.Resubmit(ctx, t.txn)
+	t.feeBumpResult = res
+	if res.Transaction == nil {
+		t.completeVerify(StatusError, "", err)
+		return
+	}
+
+	t.txnHash = res.Transaction.Hash
+	switch res.Transaction.Status {
+	case transaction.TxnSuccess:
+		output, err := json.Marshal(res.Transaction)
+		if err != nil {
+			t.completeVerify(StatusError, "", errors.New("", `{"error": "transaction confirmation json marshal error"`))
+			return
+		}
+		t.completeVerifyWithConStatus(StatusSuccess, int(Success), string(output), nil)
+	case transaction.TxnChargeableError:
+		t.completeVerifyWithConStatus(StatusSuccess, int(ChargeableError), res.Transaction.TransactionOutput, nil)
+	default:
+		t.completeVerify(StatusError, res.Transaction.TransactionOutput, nil)
+	}
+}
+
 func (t *Transaction) GetVerifyOutput() string {
 	if t.verifyStatus == StatusSuccess {
 		return t.verifyOut
@@ -16,6 +16,7 @@ import (
 	"github.com/0chain/gosdk/core/block"
 	"github.com/0chain/gosdk/core/common"
 	"github.com/0chain/gosdk/core/encryption"
+	"github.com/0chain/gosdk/core/telemetry"
 	"github.com/0chain/gosdk/core/transaction"
 	"github.com/0chain/gosdk/core/util"
 )
@@ -950,9 +951,16 @@ func (t *Transaction) Verify() error {
 		return err
 	}
 
+	ctx, span := telemetry.StartSpan(context.Background(), "txn.verify")
+	span.SetAttribute("txn.hash", t.txnHash)
+	t.verifySpan = span
+
 	go func() {
 
-		for {
+		for attempt := 1; ; attempt++ {
+			if attempt > 1 {
+				telemetry.RecordRetry(ctx, telemetry.Retry{Operation: "txn.verify", Attempt: attempt})
+			}
 
 			tq.Reset()
 			// Get transaction confirmationBlock from a random sharder
@@ -984,6 +992,10 @@ func (t *Transaction) Verify() error {
 
 					// it is expired
 					if t.isTransactionExpired(lfbBlockHeader.getCreationDate(now), now) {
+						if t.feeBump != nil {
+							t.verifyWithFeeBump(ctx)
+							return
+						}
 						t.completeVerify(StatusError, "", errors.New("", `{"error": "verify transaction failed"}`))
 						return
 					}
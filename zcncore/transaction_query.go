@@ -15,7 +15,9 @@ import (
 	"time"
 
 	thrown "github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/health"
 	"github.com/0chain/gosdk/core/resty"
+	"github.com/0chain/gosdk/core/telemetry"
 	"github.com/0chain/gosdk/core/util"
 )
 
@@ -40,6 +42,8 @@ type QueryResult struct {
 	Content    []byte
 	StatusCode int
 	Error      error
+	// Host is the sharder of the result.
+	Host string
 }
 
 // QueryResultHandle handle query response, return true if it is a consensus-result
@@ -126,7 +130,7 @@ func (tq *TransactionQuery) checkSharderHealth(ctx context.Context, host string)
 	// check health
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-	r := resty.New()
+	r := resty.New(resty.WithTelemetryKind(telemetry.KindSharder))
 	requestUrl := tq.buildUrl(host, SharderEndpointHealthCheck)
 	logging.Info("zcn: check health ", requestUrl)
 	r.DoGet(ctx, requestUrl)
@@ -156,15 +160,14 @@ func (tq *TransactionQuery) checkSharderHealth(ctx context.Context, host string)
 	return nil
 }
 
-// getRandomSharder returns a random healthy sharder
+// getRandomSharder returns the healthiest sharder, a random one among the
+// sharders without known health
 func (tq *TransactionQuery) getRandomSharder(ctx context.Context) (string, error) {
 	if tq.sharders == nil || len(tq.sharders) == 0 {
 		return "", ErrNoAvailableMiners
 	}
 
-	shuffledSharders := util.Shuffle(tq.sharders)
-
-	return shuffledSharders[0], nil
+	return health.Default.Best(tq.sharders, 1)[0], nil
 }
 
 //nolint:unused
@@ -230,16 +233,15 @@ func (tq *TransactionQuery) getRandomSharderWithHealthcheck(ctx context.Context)
 	return "", ErrNoOnlineSharders
 }
 
-//getRandomMiner returns a random miner
+// getRandomMiner returns the healthiest miner, a random one among the miners
+// without known health
 func (tq *TransactionQuery) getRandomMiner(ctx context.Context) (string, error) {
 
 	if tq.miners == nil || len(tq.miners) == 0 {
 		return "", ErrNoAvailableMiners
 	}
 
-	shuffledMiners := util.Shuffle(tq.miners)
-
-	return shuffledMiners[0], nil
+	return health.Default.Best(tq.miners, 1)[0], nil
 }
 
 // FromAll query transaction from all sharders whatever it is selected or offline in previous queires, and return consensus result
@@ -253,7 +255,7 @@ func (tq *TransactionQuery) FromAll(ctx context.Context, query string, handle Qu
 		urls = append(urls, tq.buildUrl(host, query))
 	}
 
-	r := resty.New()
+	r := resty.New(resty.WithTelemetryKind(telemetry.KindSharder))
 	r.DoGet(ctx, urls...).
 		Then(func(req *http.Request, resp *http.Response, respBody []byte, cf context.CancelFunc, err error) error {
 			res := QueryResult{
@@ -261,6 +263,9 @@ func (tq *TransactionQuery) FromAll(ctx context.Context, query string, handle Qu
 				Error:      err,
 				StatusCode: http.StatusBadRequest,
 			}
+			if req != nil {
+				res.Host = resty.NodeOf(req.URL)
+			}
 
 			if resp != nil {
 				res.StatusCode = resp.StatusCode
@@ -365,8 +370,9 @@ func (tq *TransactionQuery) FromAny(ctx context.Context, query string, provider
 		return res, err
 	}
 
-	r := resty.New()
+	r := resty.New(resty.WithTelemetryKind(telemetry.KindSharder))
 	requestUrl := tq.buildUrl(host, query)
+	res.Host = host
 
 	logging.Debug("GET", requestUrl)
 
@@ -434,6 +440,7 @@ func (tq *TransactionQuery) getConsensusConfirmation(ctx context.Context, numSha
 						logging.Error("round info parse error.", err)
 						return false
 					}
+					health.Default.ObserveRound(telemetry.KindSharder, qr.Host, lfb.Round)
 
 					lfbBlockHeaders[lfb.Hash]++
 					if lfbBlockHeaders[lfb.Hash] > maxLfbBlockHeader {
@@ -514,6 +521,7 @@ func (tq *TransactionQuery) getFastConfirmation(ctx context.Context, txnHash str
 
 		err = json.Unmarshal([]byte(lfbRaw), &lfbBlockHeader)
 		if err == nil {
+			health.Default.ObserveRound(telemetry.KindSharder, result.Host, lfbBlockHeader.Round)
 			return confirmationBlockHeader, confirmationBlock, &lfbBlockHeader, ErrTransactionNotConfirmed
 		}
 
@@ -8,14 +8,14 @@ import (
 	"encoding/json"
 	"errors"
 	stderrors "errors"
-	"math/rand"
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	thrown "github.com/0chain/errors"
+	"github.com/0chain/gosdk/core/health"
 	"github.com/0chain/gosdk/core/resty"
+	"github.com/0chain/gosdk/core/telemetry"
 	"github.com/0chain/gosdk/core/util"
 )
 
@@ -39,6 +39,8 @@ type QueryResult struct {
 	Content    []byte
 	StatusCode int
 	Error      error
+	// Host is the sharder of the result.
+	Host string
 }
 
 // queryResultHandle handle query response, return true if it is a consensus-result
@@ -101,7 +103,7 @@ func (tq *transactionQuery) checkHealth(ctx context.Context, host string) error
 	}
 
 	// check health
-	r := resty.New()
+	r := resty.New(resty.WithTelemetryKind(telemetry.KindSharder))
 	requestUrl := tq.buildUrl(host, SharderEndpointHealthCheck)
 	logging.Info("zcn: check health ", requestUrl)
 	r.DoGet(ctx, requestUrl)
@@ -130,10 +132,9 @@ func (tq *transactionQuery) checkHealth(ctx context.Context, host string) error
 	return nil
 }
 
-// randOne random one health sharder
+// randOne returns the healthiest online sharder not selected yet
 func (tq *transactionQuery) randOne(ctx context.Context) (string, error) {
 
-	randGen := rand.New(rand.NewSource(time.Now().UnixNano()))
 	for {
 
 		// reset selected if all sharders were selected
@@ -141,14 +142,13 @@ func (tq *transactionQuery) randOne(ctx context.Context) (string, error) {
 			tq.selected = make(map[string]interface{})
 		}
 
-		i := randGen.Intn(len(tq.sharders))
-		host := tq.sharders[i]
-
-		_, ok := tq.selected[host]
-
-		// it was selected, try next
-		if ok {
-			continue
+		// the healthiest sharder not selected yet
+		var host string
+		for _, h := range health.Default.Rank(tq.sharders) {
+			if _, ok := tq.selected[h]; !ok {
+				host = h
+				break
+			}
 		}
 
 		tq.selected[host] = true
@@ -198,7 +198,7 @@ func (tq *transactionQuery) fromAll(query string, handle queryResultHandle, time
 		urls = append(urls, tq.buildUrl(host, query))
 	}
 
-	r := resty.New()
+	r := resty.New(resty.WithTelemetryKind(telemetry.KindSharder))
 	r.DoGet(ctx, urls...).
 		Then(func(req *http.Request, resp *http.Response, respBody []byte, cf context.CancelFunc, err error) error {
 			res := QueryResult{
@@ -206,6 +206,9 @@ func (tq *transactionQuery) fromAll(query string, handle queryResultHandle, time
 				Error:      err,
 				StatusCode: http.StatusBadRequest,
 			}
+			if req != nil {
+				res.Host = resty.NodeOf(req.URL)
+			}
 
 			if resp != nil {
 				res.StatusCode = resp.StatusCode
@@ -253,8 +256,9 @@ func (tq *transactionQuery) fromAny(query string, timeout RequestTimeout) (Query
 		return res, err
 	}
 
-	r := resty.New()
+	r := resty.New(resty.WithTelemetryKind(telemetry.KindSharder))
 	requestUrl := tq.buildUrl(host, query)
+	res.Host = host
 
 	logging.Debug("GET", requestUrl)
 
@@ -365,6 +369,7 @@ func (tq *transactionQuery) getConsensusConfirmation(numSharders int, txnHash st
 						logging.Error("round info parse error.", err)
 						return false
 					}
+					health.Default.ObserveRound(telemetry.KindSharder, qr.Host, lfb.Round)
 
 					lfbBlockHeaders[lfb.Hash]++
 					if lfbBlockHeaders[lfb.Hash] > maxLfbBlockHeader {
@@ -445,6 +450,7 @@ func (tq *transactionQuery) getFastConfirmation(txnHash string, timeout RequestT
 
 		err = json.Unmarshal([]byte(lfbRaw), &lfbBlockHeader)
 		if err == nil {
+			health.Default.ObserveRound(telemetry.KindSharder, result.Host, lfbBlockHeader.Round)
 			return confirmationBlockHeader, confirmationBlock, &lfbBlockHeader, ErrTransactionNotConfirmed
 		}
 
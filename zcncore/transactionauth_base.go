@@ -100,6 +100,14 @@ func verifyFn(signature, msgHash, publicKey string) (bool, error) {
 
 func (ta *TransactionWithAuth) sign(otherSig string) error {
 	ta.t.txn.ComputeHashData()
+	if _config.signer != nil {
+		own, err := _config.signer.Sign(ta.t.txn.Hash)
+		if err != nil {
+			return err
+		}
+		ta.t.txn.Signature, err = zcncrypto.AggregateSignatures(otherSig, own)
+		return err
+	}
 	sig := zcncrypto.NewSignatureScheme(_config.chain.SignatureScheme)
 
 	var err error
@@ -113,13 +121,10 @@ func (ta *TransactionWithAuth) sign(otherSig string) error {
 }
 
 func (ta *TransactionWithAuth) submitTxn() {
-	nonce := ta.t.txn.TransactionNonce
-	if nonce < 1 {
-		nonce = transaction.Cache.GetNextNonce(ta.t.txn.ClientID)
-	} else {
-		transaction.Cache.Set(ta.t.txn.ClientID, nonce)
+	if err := ta.t.setNonce(); err != nil {
+		ta.completeTxn(StatusError, "", err)
+		return
 	}
-	ta.t.txn.TransactionNonce = nonce
 	authTxn, err := ta.getAuthorize()
 	if err != nil {
 		logging.Error("get auth error for send.", err.Error())
@@ -156,13 +161,10 @@ func (ta *TransactionWithAuth) ExecuteFaucetSCWallet(walletStr string, methodNam
 		return err
 	}
 	go func() {
-		nonce := ta.t.txn.TransactionNonce
-		if nonce < 1 {
-			nonce = transaction.Cache.GetNextNonce(ta.t.txn.ClientID)
-		} else {
-			transaction.Cache.Set(ta.t.txn.ClientID, nonce)
+		if err := ta.t.setNonce(); err != nil {
+			ta.completeTxn(StatusError, "", err)
+			return
 		}
-		ta.t.txn.TransactionNonce = nonce
 		err = ta.t.txn.ComputeHashAndSignWithWallet(signWithWallet, w)
 		if err != nil {
 			return
@@ -19,7 +19,10 @@ import (
 	"github.com/0chain/errors"
 	"github.com/0chain/gosdk/core/common"
 	"github.com/0chain/gosdk/core/conf"
+	"github.com/0chain/gosdk/core/health"
+	"github.com/0chain/gosdk/core/keystore"
 	"github.com/0chain/gosdk/core/logger"
+	"github.com/0chain/gosdk/core/signer"
 	"github.com/0chain/gosdk/core/tokenrate"
 	"github.com/0chain/gosdk/core/util"
 	"github.com/0chain/gosdk/core/version"
@@ -237,7 +240,7 @@ func GetStableMiners() []string {
 	mGuard.Lock()
 	defer mGuard.Unlock()
 	if len(miners) == 0 {
-		miners = util.GetRandom(_config.chain.Miners, getMinMinersSubmit())
+		miners = health.Default.Best(_config.chain.Miners, getMinMinersSubmit())
 	}
 
 	return miners
@@ -245,7 +248,7 @@ func GetStableMiners() []string {
 func ResetStableMiners() {
 	mGuard.Lock()
 	defer mGuard.Unlock()
-	miners = util.GetRandom(_config.chain.Miners, getMinMinersSubmit())
+	miners = health.Default.Best(_config.chain.Miners, getMinMinersSubmit())
 }
 
 func checkSdkInit() error {
@@ -541,10 +544,69 @@ func SetWalletInfo(jsonWallet string, splitKeyWallet bool) error {
 			_config.isSplitWallet = splitKeyWallet
 		}
 		_config.isValidWallet = true
+		_config.walletFromSigner = false
 	}
 	return err
 }
 
+// SetSigner makes the sdk sign with s, a remote signer for example, instead of
+// the private keys of the wallet. The wallet is set to the client of s if no
+// wallet is set. A nil s restores signing with the wallet keys, the wallet set
+// from s is unset.
+func SetSigner(s signer.Signer) error {
+	if s == nil {
+		_config.signer = nil
+		if _config.walletFromSigner {
+			_config.wallet = zcncrypto.Wallet{}
+			_config.isValidWallet = false
+			_config.walletFromSigner = false
+		}
+		return nil
+	}
+	if _config.chain.SignatureScheme != "" && s.SignatureScheme() != _config.chain.SignatureScheme {
+		return errors.New("", "the signer uses the "+s.SignatureScheme()+" signature scheme")
+	}
+	if _config.isValidWallet && _config.wallet.ClientID != s.ClientID() {
+		return errors.New("", "the signer doesn't sign for the wallet")
+	}
+	if !_config.isValidWallet {
+		_config.wallet = zcncrypto.Wallet{
+			ClientID:  s.ClientID(),
+			ClientKey: s.PublicKey(),
+			Version:   zcncrypto.CryptoVersion,
+		}
+		_config.isValidWallet = true
+		_config.walletFromSigner = true
+	}
+	_config.signer = s
+	return nil
+}
+
+// SetWalletFromKeystore sets the wallet stored under name in the keystore
+// directory, decrypted with password. See SetWalletInfo.
+// The decrypted wallet is kept by the sdk until another one is set; it isn't
+// tied to Keystore.Unlock, whose timeout only covers Keystore.Wallet.
+// # Inputs
+//   - keystoreDir: directory of the keystore, see core/keystore
+//   - name: name of the wallet in the keystore
+//   - password: password of the wallet
+//   - splitKeyWallet: if wallet keys is split
+func SetWalletFromKeystore(keystoreDir, name, password string, splitKeyWallet bool) error {
+	ks, err := keystore.New(keystoreDir, nil)
+	if err != nil {
+		return err
+	}
+	w, err := ks.Get(name, password)
+	if err != nil {
+		return err
+	}
+	jsonWallet, err := w.Marshal()
+	if err != nil {
+		return err
+	}
+	return SetWalletInfo(jsonWallet, splitKeyWallet)
+}
+
 // SetAuthUrl will be called by app to set zauth URL to SDK.
 // # Inputs
 //   - url: the url of zAuth server
@@ -716,6 +778,16 @@ func getNonceFromSharders(clientID string) (int64, string, error) {
 }
 
 func getBalanceFieldFromSharders(clientID, name string) (int64, string, error) {
+	values, info, err := getBalanceFieldsFromSharders(clientID, name)
+	if err != nil {
+		return 0, info, err
+	}
+	return values[0], info, nil
+}
+
+// getBalanceFieldsFromSharders returns the fields of the balance of the client
+// in the order of names, read from one request to the sharders.
+func getBalanceFieldsFromSharders(clientID string, names ...string) ([]int64, string, error) {
 	result := make(chan *util.GetResponse)
 	defer close(result)
 	// getMinShardersVerify
@@ -743,22 +815,24 @@ func getBalanceFieldFromSharders(clientID, name string) (int64, string, error) {
 	rate := consensusMaps.MaxConsensus * 100 / len(_config.chain.Sharders)
 	if rate < consensusThresh {
 		if strings.TrimSpace(consensusMaps.WinError) == `{"error":"value not present"}` {
-			return 0, consensusMaps.WinError, nil
+			return make([]int64, len(names)), consensusMaps.WinError, nil
 		}
-		return 0, consensusMaps.WinError, errors.New("", "get balance failed. consensus not reached")
+		return nil, consensusMaps.WinError, errors.New("", "get balance failed. consensus not reached")
 	}
 
-	winValue, ok := consensusMaps.GetValue(name)
-	if ok {
-		winBalance, err := strconv.ParseInt(string(winValue), 10, 64)
+	values := make([]int64, len(names))
+	for i, name := range names {
+		winValue, ok := consensusMaps.GetValue(name)
+		if !ok {
+			return nil, consensusMaps.WinInfo, errors.New("", "get balance failed. balance field is missed")
+		}
+		value, err := strconv.ParseInt(string(winValue), 10, 64)
 		if err != nil {
-			return 0, "", fmt.Errorf("get balance failed. %w", err)
+			return nil, "", fmt.Errorf("get balance failed. %w", err)
 		}
-
-		return winBalance, consensusMaps.WinInfo, nil
+		values[i] = value
 	}
-
-	return 0, consensusMaps.WinInfo, errors.New("", "get balance failed. balance field is missed")
+	return values, consensusMaps.WinInfo, nil
 }
 
 // ConvertToToken converts the SAS tokens to ZCN tokens
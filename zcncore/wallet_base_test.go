@@ -3,6 +3,9 @@ package zcncore
 import (
 	"testing"
 
+	"github.com/0chain/gosdk/core/encryption"
+	"github.com/0chain/gosdk/core/signer"
+	"github.com/0chain/gosdk/core/zcncrypto"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,3 +38,37 @@ func TestCBCEncryption(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, mnemonics, string(dec))
 }
+
+func TestSetSigner(t *testing.T) {
+	config := _config
+	t.Cleanup(func() { _config = config })
+	_config.chain.SignatureScheme = "bls0chain"
+	_config.wallet, _config.isValidWallet, _config.signer = zcncrypto.Wallet{}, false, nil
+
+	w, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	s, err := signer.NewLocalSigner("bls0chain", w)
+	require.NoError(t, err)
+
+	// the wallet is the client of the signer, without keys
+	require.NoError(t, SetSigner(s))
+	require.True(t, _config.isValidWallet)
+	require.Equal(t, w.ClientID, _config.wallet.ClientID)
+	_, err = Sign(encryption.Hash("message"))
+	require.NoError(t, err)
+
+	// the wallet of the signer is unset with it
+	require.NoError(t, SetSigner(nil))
+	require.False(t, _config.isValidWallet)
+	require.Empty(t, _config.wallet.ClientID)
+
+	// a wallet set by SetWalletInfo is kept
+	walletJSON, err := w.Marshal()
+	require.NoError(t, err)
+	require.NoError(t, SetWalletInfo(walletJSON, false))
+	require.NoError(t, SetSigner(s))
+	require.NoError(t, SetSigner(nil))
+	require.True(t, _config.isValidWallet)
+	_, err = Sign(encryption.Hash("message"))
+	require.NoError(t, err)
+}